@@ -73,7 +73,7 @@ func RunGRPC(cfg *config.Config, logger *logrus.Logger) {
 
 func RunGrpcGateway(ctx context.Context, logger *logrus.Logger, cfg *config.Config) func() {
 	// Register gRPC server endpoint
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(handler.GatewayErrorHandler))
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
 	if err := gen.RegisterAuthHandlerFromEndpoint(ctx, mux, "auth_service:"+cfg.GRPC.Port, opts); err != nil { ////docker host
@@ -1,7 +1,13 @@
 package models
 
+const (
+	RoleCustomer = "customer"
+	RoleStaff    = "staff" //сотрудник банка,назначается в базе вручную
+)
+
 type User struct {
 	ID       int64
 	Username string
 	Password []byte //in db hashed pass
+	Role     string
 }
@@ -55,7 +55,7 @@ func (s *AuthServer) Register(ctx context.Context, req *gen.RegisterRequest) (*g
 	userId, err := s.service.Register(ctx, req.GetUsername(), req.GetPassword())
 	if err != nil {
 		s.logger.Errorf("method register failed:%s", err)
-		return nil, status.Errorf(codes.Internal, "register failed:%s", err)
+		return nil, toStatus("register", err)
 	}
 
 	return &gen.RegisterResponse{
@@ -79,7 +79,7 @@ func (s *AuthServer) Login(ctx context.Context, req *gen.LoginRequest) (*gen.Log
 	accessToken, refreshToken, err := s.service.Login(ctx, req.GetUsername(), req.GetPassword())
 	if err != nil {
 		s.logger.Errorf("method login failed:%s", err)
-		return nil, status.Errorf(codes.Internal, "login failed:%s", err)
+		return nil, toStatus("login", err)
 	}
 
 	return &gen.LoginResponse{
@@ -103,7 +103,7 @@ func (s *AuthServer) RefreshToken(ctx context.Context, req *gen.RefreshTokenRequ
 	newAccessToken, newRefreshToken, err := s.service.RefreshToken(ctx, req.GetRefreshToken())
 	if err != nil {
 		s.logger.Errorf("method refresh token failed:%s", err)
-		return nil, status.Errorf(codes.Internal, "method refresh token failed:%s", err)
+		return nil, toStatus("method refresh token", err)
 	}
 
 	return &gen.RefreshTokenResponse{
@@ -129,13 +129,13 @@ func (s *AuthServer) ValidateAccessToken(ctx context.Context, req *gen.ValidateA
 	bearerAndToken := header[0]
 	headerParts := strings.Split(bearerAndToken, " ") //делим на 2 части: до пробела и после
 
-	accessToken := headerParts[1]
-
-	if len(headerParts) != 2 && headerParts[0] != "Bearer" {
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
 		s.logger.Error("invalid auth header")
 		return nil, status.Errorf(codes.Unauthenticated, "invalid auth header")
 	}
 
+	accessToken := headerParts[1]
+
 	if len(accessToken) == 0 {
 		s.logger.Error("empty auth token")
 		return nil, status.Error(codes.Unauthenticated, "empty auth token")
@@ -158,7 +158,7 @@ func (s *AuthServer) ValidateValues(req *RequestDTO) error {
 	if err := validate.Struct(req); err != nil {
 		validateErr := err.(validator.ValidationErrors)
 		s.logger.Errorf("invalid req:%s", ValidationErrors(validateErr))
-		return status.Errorf(codes.InvalidArgument, "invalid req:%s", ValidationErrors(validateErr))
+		return validationStatus(validateErr)
 	}
 
 	return nil
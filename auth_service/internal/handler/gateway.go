@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type gatewayError struct {
+	Code            string           `json:"code"`
+	Message         string           `json:"message"`
+	FieldViolations []fieldViolation `json:"fieldViolations,omitempty"`
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// GatewayErrorHandler writes grpc errors to http clients with the http status matching the grpc code
+func GatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+
+	body := gatewayError{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	}
+
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			body.FieldViolations = append(body.FieldViolations, fieldViolation{
+				Field:       violation.GetField(),
+				Description: violation.GetDescription(),
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -1,15 +1,76 @@
 package handler
 
 import (
+	"bank/auth_service/internal/domain/models"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+type errorMapping struct {
+	err  error
+	code codes.Code
+}
+
+// errorMappings maps domain errors to grpc codes,the first match wins
+var errorMappings = []errorMapping{
+	{models.ErrUserAlreadyExists, codes.AlreadyExists},
+	{models.ErrUserNotFound, codes.NotFound},
+	{models.ErrInvalidCredentials, codes.Unauthenticated},
+	{models.ErrTokenExpired, codes.Unauthenticated},
+	{models.ErrInvalidToken, codes.Unauthenticated}, //поддельный или битый токен-та же ошибка входа,что и истекший
+}
+
+// toStatus converts an error of the service layer to a grpc status error,unknown errors become Internal
+func toStatus(method string, err error) error {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return status.Errorf(mapping.code, "%s failed:%s", method, err)
+		}
+	}
+	return status.Errorf(codes.Internal, "%s failed:%s", method, err)
+}
+
+// ValidationErrors describes the first failed rule
 func ValidationErrors(errs validator.ValidationErrors) error {
 	for _, err := range errs {
-		if err.Tag() == "required_if" {
-			return fmt.Errorf("you must fill the '%s' value", err.Field())
-		}
+		return errors.New(violation(err))
 	}
 	return nil
 }
+
+// violation describes the rule the field failed
+func violation(err validator.FieldError) string {
+	switch {
+	case err.Tag() == "required" || err.Tag() == "required_if":
+		return fmt.Sprintf("you must fill the '%s' value", err.Field())
+	case err.Param() != "":
+		return fmt.Sprintf("the '%s' value failed the '%s=%s' rule", err.Field(), err.Tag(), err.Param())
+	default:
+		return fmt.Sprintf("the '%s' value failed the '%s' rule", err.Field(), err.Tag())
+	}
+}
+
+// validationStatus is InvalidArgument with a BadRequest detail listing every invalid field
+func validationStatus(errs validator.ValidationErrors) error {
+	badRequest := &errdetails.BadRequest{}
+
+	for _, err := range errs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       err.Field(),
+			Description: violation(err),
+		})
+	}
+
+	st := status.Newf(codes.InvalidArgument, "invalid req:%s", ValidationErrors(errs))
+
+	stWithDetails, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+
+	return stWithDetails.Err()
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"bank/auth_service/internal/config"
+	"bank/auth_service/internal/domain/models"
 	"bank/auth_service/pkg/jwt"
 	"context"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"time"
@@ -49,12 +51,15 @@ func (s *Service) Login(ctx context.Context, username, password string) (accessT
 	user, err := s.storage.GetUserByUsername(ctx, username)
 	if err != nil {
 		s.logger.Errorf("failed to get user by username:%s", err)
+		if errors.Is(err, models.ErrUserNotFound) {
+			return "", "", models.ErrInvalidCredentials //тот же ответ,что и на неверный пароль,иначе по логину можно перебирать юзеров
+		}
 		return "", "", err
 	}
 
 	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil { //валидность введенного юзером пароля
 		s.logger.Errorf("password wrong:%s", err)
-		return "", "", err
+		return "", "", models.ErrInvalidCredentials
 	}
 
 	secretKey := s.cfg.Auth.SecretKey
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const uniqueViolationCode = "23505" //код ошибки postgres при нарушении unique
+
 type AuthPostgres struct {
 	db *pgxpool.Pool
 }
@@ -23,6 +26,10 @@ func (p *AuthPostgres) SaveUser(ctx context.Context, username string, hashedPass
 	row := p.db.QueryRow(ctx, query, username, hashedPassword)
 
 	if err = row.Scan(&userId); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, fmt.Errorf("%w with username:%s", models.ErrUserAlreadyExists, username)
+		}
 		return 0, fmt.Errorf("failed to scan in saveUser:%s", err)
 	}
 
@@ -30,13 +37,13 @@ func (p *AuthPostgres) SaveUser(ctx context.Context, username string, hashedPass
 }
 
 func (p *AuthPostgres) GetUserByUsername(ctx context.Context, username string) (user models.User, err error) {
-	query := "select * from users where username=$1"
+	query := "select id,username,password,role from users where username=$1"
 
 	row := p.db.QueryRow(ctx, query, username)
 
-	if err = row.Scan(&user.ID, &user.Username, &user.Password); err != nil {
+	if err = row.Scan(&user.ID, &user.Username, &user.Password, &user.Role); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return models.User{}, fmt.Errorf("user not found with username:%s", username)
+			return models.User{}, fmt.Errorf("%w with username:%s", models.ErrUserNotFound, username)
 		}
 		return models.User{}, fmt.Errorf("failed to scan in getUserByUsername:%s", err)
 	}
@@ -45,13 +52,13 @@ func (p *AuthPostgres) GetUserByUsername(ctx context.Context, username string) (
 }
 
 func (p *AuthPostgres) GetUserById(ctx context.Context, userId int64) (user models.User, err error) {
-	query := "select * from users where id=$1"
+	query := "select id,username,password,role from users where id=$1"
 
 	row := p.db.QueryRow(ctx, query, userId)
 
-	if err = row.Scan(&user.ID, &user.Username, &user.Password); err != nil {
+	if err = row.Scan(&user.ID, &user.Username, &user.Password, &user.Role); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return models.User{}, fmt.Errorf("user not found with id:%v", userId)
+			return models.User{}, fmt.Errorf("%w with id:%v", models.ErrUserNotFound, userId)
 		}
 		return models.User{}, fmt.Errorf("failed to scan in getUserById:%s", err)
 	}
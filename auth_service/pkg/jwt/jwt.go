@@ -14,6 +14,7 @@ func GenerateAccessToken(user models.User, accessTokenTTL time.Duration, secretK
 
 	claims["userId"] = user.ID
 	claims["username"] = user.Username
+	claims["role"] = user.Role //по роли credit_service отличает сотрудников от клиентов
 	claims["exp"] = time.Now().Add(accessTokenTTL).Unix()
 
 	accessTokenString, err := accessToken.SignedString([]byte(secretKey))
@@ -29,21 +30,24 @@ func ValidateAccessToken(accessToken, secretKey string) (bool, error) {
 		return []byte(secretKey), nil
 	})
 	if err != nil {
-		return false, fmt.Errorf("parse accessToken failed:%s", err)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return false, fmt.Errorf("%w:parse accessToken failed:%s", models.ErrTokenExpired, err)
+		}
+		return false, fmt.Errorf("%w:parse accessToken failed:%s", models.ErrInvalidToken, err)
 	}
 
 	if !token.Valid { // подпись токена верна,claims прошли проверку,не истек ли
-		return false, errors.New("accessToken is not valid")
+		return false, fmt.Errorf("%w:accessToken is not valid", models.ErrInvalidToken)
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return false, errors.New("invalid accessToken claims")
+		return false, fmt.Errorf("%w:invalid accessToken claims", models.ErrInvalidToken)
 	}
 
 	_, ok = claims["userId"].(float64)
 	if !ok {
-		return false, errors.New("userID is missing or invalid in accessToken")
+		return false, fmt.Errorf("%w:userID is missing or invalid in accessToken", models.ErrInvalidToken)
 	}
 
 	return true, nil
@@ -68,21 +72,24 @@ func ParseRefreshToken(refreshToken, secretKey string) (int64, error) { //to get
 		return []byte(secretKey), nil
 	})
 	if err != nil {
-		return 0, fmt.Errorf("parse refreshTOken failed:%s", err)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, fmt.Errorf("%w:parse refreshTOken failed:%s", models.ErrTokenExpired, err)
+		}
+		return 0, fmt.Errorf("%w:parse refreshTOken failed:%s", models.ErrInvalidToken, err)
 	}
 
 	if !token.Valid { // подпись токена верна,claims прошли проверку,не истек ли
-		return 0, errors.New("refresh token is not valid")
+		return 0, fmt.Errorf("%w:refresh token is not valid", models.ErrInvalidToken)
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("invalid refresh token claims")
+		return 0, fmt.Errorf("%w:invalid refresh token claims", models.ErrInvalidToken)
 	}
 
 	userIdFloat, ok := claims["userId"].(float64) //float,тк извлекаем из json(весь код jwt),а там это стандартный тип
 	if !ok {
-		return 0, errors.New("userID is missing or invalid in refresh token")
+		return 0, fmt.Errorf("%w:userID is missing or invalid in refresh token", models.ErrInvalidToken)
 	}
 
 	return int64(userIdFloat), nil
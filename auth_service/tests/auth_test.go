@@ -2,11 +2,18 @@ package tests
 
 import (
 	"bank/auth_service/gen"
+	"bank/auth_service/internal/domain/models"
+	"bank/auth_service/internal/handler"
 	"bank/auth_service/pkg/jwt"
 	"bank/auth_service/tests/suite"
 	"github.com/brianvoe/gofakeit"
+	"github.com/go-playground/validator/v10"
+	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"testing"
 )
 
@@ -39,6 +46,13 @@ func TestAuth_Ok(t *testing.T) {
 	require.True(t, ok)
 	require.NoError(t, err)
 
+	claims := jwtlib.MapClaims{}
+	_, err = jwtlib.ParseWithClaims(accessToken, claims, func(*jwtlib.Token) (interface{}, error) {
+		return []byte(secretKey), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.RoleCustomer, claims["role"]) //сотрудником через регистрацию не стать
+
 	refreshToken := loginResp.GetRefreshToken()
 	require.NotEmpty(t, refreshToken)
 
@@ -132,7 +146,7 @@ func TestLogin_Fail(t *testing.T) {
 			name:        "incorrect data",
 			username:    gofakeit.Username(),
 			password:    fakePass(),
-			expectedErr: "user not found with username",
+			expectedErr: "login failed:invalid username or password",
 		},
 	}
 	for _, tt := range tests {
@@ -217,6 +231,80 @@ func TestValidateAccessToken_Fail(t *testing.T) {
 	}
 }
 
+func TestStatusCodes(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	username := gofakeit.Username()
+	password := fakePass()
+
+	_, err := st.AuthClient.Register(ctx, &gen.RegisterRequest{Username: username, Password: password})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.Register(ctx, &gen.RegisterRequest{Username: username, Password: password})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = st.AuthClient.Login(ctx, &gen.LoginRequest{Username: username, Password: fakePass()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	wrongPassword := status.Convert(err)
+
+	_, err = st.AuthClient.Login(ctx, &gen.LoginRequest{Username: gofakeit.Username(), Password: password})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.Equal(t, wrongPassword.Message(), status.Convert(err).Message()) //неизвестный юзер неотличим от неверного пароля
+
+	_, err = st.AuthClient.RefreshToken(ctx, &gen.RefreshTokenRequest{RefreshToken: "qwerty123"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.Login(ctx, &gen.LoginRequest{Username: username})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = br
+		}
+	}
+	require.NotNil(t, badRequest)
+	require.Equal(t, "Password", badRequest.GetFieldViolations()[0].GetField())
+}
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		expectedErr string
+	}{
+		{
+			name: "required",
+			value: struct {
+				Username string `validate:"required"`
+			}{},
+			expectedErr: "you must fill the 'Username' value",
+		},
+		{
+			name: "rule with param",
+			value: struct {
+				Password string `validate:"min=8"`
+			}{Password: "short"},
+			expectedErr: "the 'Password' value failed the 'min=8' rule",
+		},
+		{
+			name: "rule without param",
+			value: struct {
+				Email string `validate:"email"`
+			}{Email: "qwerty"},
+			expectedErr: "the 'Email' value failed the 'email' rule",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Struct(tt.value)
+			require.Error(t, err)
+			require.EqualError(t, handler.ValidationErrors(err.(validator.ValidationErrors)), tt.expectedErr)
+		})
+	}
+}
+
 func fakePass() string {
 	return gofakeit.Password(true, true, true, true, false, 15)
 }
@@ -0,0 +1,126 @@
+// Command credits imports credits from CSV or JSON Lines and exports them as JSON Lines.
+//
+//	credits [-config path] import [-format csv|jsonl] [-dry-run] <file>
+//	credits [-config path] export [<file>]
+//
+// The import prints the report as json and fails if any row failed
+package main
+
+import (
+	"bank/credit_service/internal/app"
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr) //stdout занят отчетом или выгрузкой
+	logger.SetLevel(logrus.WarnLevel)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	configPath := flag.String("config", "config/local.yml", "path to the service config")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.InitConfigByPath(*configPath)
+	if err != nil {
+		logger.Fatalf("init config failed:%s", err)
+	}
+
+	switch command, args := flag.Arg(0), flag.Args()[1:]; command {
+	case "import":
+		err = runImport(cfg, logger, args)
+	case "export":
+		err = runExport(cfg, logger, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func runImport(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "csv or jsonl,by the file extension by default")
+	dryRun := flags.Bool("dry-run", false, "only check the rows")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("import needs exactly one file")
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = models.ImportJSONL
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			*format = models.ImportCSV
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s failed:%s", path, err)
+	}
+	defer file.Close()
+
+	report, err := app.RunImport(cfg, logger, file, *format, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return fmt.Errorf("write report failed:%s", err)
+	}
+
+	if len(report.Errors) != 0 {
+		return fmt.Errorf("%v of %v rows failed", len(report.Errors), report.Rows)
+	}
+
+	return nil
+}
+
+func runExport(cfg *config.Config, logger *logrus.Logger, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("export takes at most one file")
+	}
+
+	if len(args) == 0 {
+		return app.RunExport(cfg, logger, os.Stdout)
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf("create %s failed:%s", args[0], err)
+	}
+
+	if err = app.RunExport(cfg, logger, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  credits [-config path] import [-format csv|jsonl] [-dry-run] <file>")
+	fmt.Fprintln(os.Stderr, "  credits [-config path] export [<file>]")
+	flag.PrintDefaults()
+}
@@ -2,13 +2,18 @@ package app
 
 import (
 	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/document"
+	"bank/credit_service/internal/exchange"
 	"bank/credit_service/internal/kafka/consumer"
+	"bank/credit_service/internal/kafka/producer"
 	"bank/credit_service/internal/rest"
 	"bank/credit_service/internal/service"
 	"bank/credit_service/internal/storage"
+	"bank/credit_service/internal/validation"
 	"bank/credit_service/pkg/mongodb"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/sirupsen/logrus"
@@ -16,11 +21,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func RunRest(cfg *config.Config, logger *logrus.Logger) {
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 
 	db, err := mongodb.ConnToMongoDB(cfg)
@@ -35,10 +42,39 @@ func RunRest(cfg *config.Config, logger *logrus.Logger) {
 		logger.Info("mongodb connection closed")
 	}()
 
-	storages := storage.NewStorage(db, cfg.MongoDb.CreditCollection, cfg.MongoDb.UserIDCollection)
-	services := service.NewService(logger, storages)
-	handlers := rest.NewHandler(logger, services)
+	storages := storage.NewStorage(db, cfg.MongoDb)
+	services, err := newService(cfg, logger, storages)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if err = storages.CreateOutboxIndex(context.Background()); err != nil {
+		logger.Fatal(err)
+	}
+
+	if _, err = service.DayCountFraction(cfg.Accrual.DayCount, time.Now(), time.Now()); err != nil {
+		logger.Fatalf("invalid accrual config:%s", err)
+	}
+	if cfg.Accrual.Interval <= 0 { //time.NewTicker паникует на таком значении
+		logger.Fatalf("invalid accrual config:interval must be positive")
+	}
+	if cfg.FloatingRate.ResetMonths <= 0 {
+		logger.Fatalf("invalid floating rate config:reset_months must be positive")
+	}
+	if cfg.Statements.Interval <= 0 {
+		logger.Fatalf("invalid statements config:interval must be positive")
+	}
+	if cfg.Kafka.Events.PublishInterval <= 0 { //outbox опрашивался бы без паузы
+		logger.Fatalf("invalid kafka config:publish_interval must be positive")
+	}
+
+	if cfg.Auth.SecretKey == "" {
+		logger.Fatalf("invalid auth config:secret_key is required to check access tokens")
+	}
+
+	handlers := rest.NewHandler(logger, services, validation.New(cfg.Validation), cfg.Auth.SecretKey)
 	kc := consumer.NewKafkaConsumer(storages)
+	kp := producer.NewKafkaProducer(storages)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Rest.Port,
@@ -55,6 +91,18 @@ func RunRest(cfg *config.Config, logger *logrus.Logger) {
 		}
 	}()
 
+	producerCtx, stopProducer := context.WithCancel(context.Background())
+
+	go func() {
+		logger.Infof("kafka producer starting:%s", cfg.Kafka.Brokers)
+		if err = kp.KafkaProducer(producerCtx, cfg, logger); err != nil {
+			logger.Errorf("kafka producer failed:%s", err)
+		}
+	}()
+
+	go runAccrual(producerCtx, cfg, logger, services)
+	go runStatements(producerCtx, cfg, logger, services)
+
 	go func() {
 		logger.Infof("rest starting on port:%s", cfg.Rest.Port)
 		if err = srv.ListenAndServe(); err != nil && !errors.Is(http.ErrServerClosed, err) {
@@ -64,6 +112,8 @@ func RunRest(cfg *config.Config, logger *logrus.Logger) {
 
 	<-stop
 
+	stopProducer()
+
 	if err = srv.Shutdown(context.Background()); err != nil {
 		logger.Fatalf("shutdown rest failed:%s", err)
 	}
@@ -71,3 +121,70 @@ func RunRest(cfg *config.Config, logger *logrus.Logger) {
 	logger.Info("kafka consumer stopped")
 	logger.Info("rest server stopped")
 }
+
+func newService(cfg *config.Config, logger *logrus.Logger, storages *storage.MongoDB) (*service.Service, error) {
+	rates, err := newRateProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init exchange rate provider failed:%s", err)
+	}
+
+	documents, err := document.NewRenderer(cfg.Documents.TemplatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("load document templates failed:%s", err)
+	}
+
+	return service.NewService(logger, storages, rates, documents, cfg), nil
+}
+
+func newRateProvider(cfg *config.Config) (service.RateProvider, error) {
+	if cfg.Exchange.RatesFile != "" {
+		return exchange.NewFileProvider(cfg.Exchange.RatesFile)
+	}
+	return exchange.NewInMemoryProviderFromMap(cfg.Exchange.BaseCurrency, cfg.Exchange.Rates), nil
+}
+
+// runAccrual accrues interest for completed days on every tick,missed days are backfilled by the service.
+// Floating rates due by today are reset first,so the days after a reset are accrued at the new rate
+func runAccrual(ctx context.Context, cfg *config.Config, logger *logrus.Logger, services *service.Service) {
+	ticker := time.NewTicker(cfg.Accrual.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := services.RunRateResets(ctx, time.Now().UTC()); err != nil {
+			logger.Errorf("rate resets failed:%s", err)
+		}
+
+		yesterday := time.Now().UTC().AddDate(0, 0, -1)
+		if _, err := services.RunAccrual(ctx, yesterday); err != nil {
+			logger.Errorf("interest accrual failed:%s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("interest accrual stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runStatements makes the statements of the previous month on every tick,credits that already have them are skipped
+func runStatements(ctx context.Context, cfg *config.Config, logger *logrus.Logger, services *service.Service) {
+	ticker := time.NewTicker(cfg.Statements.Interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		month := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC) //AddDate(0,-1,0) 31-го числа может остаться в том же месяце
+		if _, err := services.RunMonthlyStatements(ctx, month); err != nil {
+			logger.Errorf("monthly statements failed:%s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("monthly statements stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
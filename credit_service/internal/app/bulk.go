@@ -0,0 +1,59 @@
+package app
+
+import (
+	"bank/credit_service/internal/bulk"
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/internal/validation"
+	"bank/credit_service/pkg/mongodb"
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// cliActor is the actor of the history records of credits imported from the command line
+const cliActor = "cli"
+
+// RunImport imports the credits from r like the POST /admin/credits/import endpoint
+func RunImport(cfg *config.Config, logger *logrus.Logger, r io.Reader, format string, dryRun bool) (models.ImportReport, error) {
+	var report models.ImportReport
+
+	err := withService(cfg, logger, func(ctx context.Context, services *service.Service) (err error) {
+		report, err = bulk.NewImporter(services, validation.New(cfg.Validation)).Import(ctx, r, format, dryRun)
+		return err
+	})
+
+	return report, err
+}
+
+// RunExport writes every credit to w as JSON Lines
+func RunExport(cfg *config.Config, logger *logrus.Logger, w io.Writer) error {
+	return withService(cfg, logger, func(ctx context.Context, services *service.Service) error {
+		return bulk.Export(ctx, w, services)
+	})
+}
+
+func withService(cfg *config.Config, logger *logrus.Logger, fn func(ctx context.Context, services *service.Service) error) error {
+	db, err := mongodb.ConnToMongoDB(cfg)
+	if err != nil {
+		return fmt.Errorf("connect to mongo failed:%s", err)
+	}
+
+	defer func() {
+		if err := db.Client().Disconnect(context.Background()); err != nil {
+			logger.Errorf("mongodb close failed:%s", err)
+		}
+	}()
+
+	services, err := newService(cfg, logger, storage.NewStorage(db, cfg.MongoDb))
+	if err != nil {
+		return err
+	}
+
+	ctx := service.WithRequestMeta(context.Background(), cliActor, "", 0)
+
+	return fn(ctx, services)
+}
@@ -0,0 +1,109 @@
+package bulk
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type CreditImporter interface {
+	ImportCredit(ctx context.Context, credit models.Credit, dryRun bool) (models.Credit, error)
+}
+
+type CreditExporter interface {
+	ExportCredits(ctx context.Context, fn func(models.Credit) error) error
+}
+
+type Validator interface {
+	Struct(data interface{}) error
+}
+
+// Importer loads credits from CSV or JSON Lines,every row is validated and created like a CreateCredit request
+type Importer struct {
+	service   CreditImporter
+	validator Validator
+}
+
+func NewImporter(service CreditImporter, validator Validator) *Importer {
+	return &Importer{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// creditKey is what makes two credits the same for CreateCredit
+type creditKey struct {
+	userID   int64
+	amount   int
+	term     int
+	currency string
+	rate     float64
+}
+
+// Import imports the rows of the file one by one,a bad row is reported and doesn't stop the import.
+// The error is returned only when the file can't be read further
+func (i *Importer) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: dryRun}
+	seen := make(map[creditKey]int) //в dry run повторы в файле не дойдут до базы
+
+	err := decode(r, format, func(row int, credit models.Credit, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		report.Rows++
+
+		if err == nil {
+			err = i.validator.Struct(&credit)
+		}
+		if err == nil {
+			credit, err = i.service.ImportCredit(ctx, credit, dryRun)
+		}
+		if err == nil && dryRun {
+			key := creditKey{credit.UserID, credit.Amount, credit.Term, credit.Currency, credit.AnnualInterestRate}
+			if first, ok := seen[key]; ok {
+				err = fmt.Errorf("%w:same as the row %v", models.ErrCreditAlreadyExists, first)
+			} else {
+				seen[key] = row
+			}
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, rowError(row, err))
+			return nil
+		}
+
+		report.Valid++
+		if !dryRun {
+			report.Imported++
+		}
+		return nil
+	})
+
+	return report, err
+}
+
+func rowError(row int, err error) models.ImportRowError {
+	rowErr := models.ImportRowError{Row: row, Message: err.Error()}
+
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		rowErr.Fields = validationErr.Fields
+	}
+
+	return rowErr
+}
+
+// Export writes every credit as a line of JSON
+func Export(ctx context.Context, w io.Writer, service CreditExporter) error {
+	encoder := json.NewEncoder(w)
+
+	return service.ExportCredits(ctx, func(credit models.Credit) error {
+		if err := encoder.Encode(credit); err != nil {
+			return fmt.Errorf("write credit %s failed:%s", credit.ID, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,170 @@
+package bulk
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const maxLineSize = 1 << 20 //строка JSON Lines с залогами и поручителями
+
+// csvColumns are the fields of a credit a CSV file can have,the header names them in any order and case
+var csvColumns = []string{"UserID", "ProductID", "Amount", "Currency", "Term", "Scheme"}
+
+// rowFunc gets the credit of the line of the file or why it couldn't be read
+type rowFunc func(row int, credit models.Credit, err error) error
+
+func decode(r io.Reader, format string, fn rowFunc) error {
+	switch format {
+	case models.ImportCSV:
+		return decodeCSV(r, fn)
+	case models.ImportJSONL:
+		return decodeJSONL(r, fn)
+	default:
+		return fmt.Errorf("%w:unknown format %q,use csv or jsonl", models.ErrInvalidRequest, format)
+	}
+}
+
+func decodeCSV(r io.Reader, fn rowFunc) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w:the file is empty", models.ErrInvalidRequest)
+	}
+	if err != nil {
+		return fmt.Errorf("%w:failed to read csv header:%s", models.ErrInvalidRequest, err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		for _, column := range csvColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				columns[i] = column
+			}
+		}
+		if columns[i] == "" {
+			return fmt.Errorf("%w:unknown csv column %q,use %s", models.ErrInvalidRequest, name, strings.Join(csvColumns, ","))
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) { //битая строка не мешает читать следующие
+			if err = fn(parseErr.StartLine, models.Credit{}, fmt.Errorf("%w:%s", models.ErrInvalidRequest, parseErr.Err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w:failed to read csv:%s", models.ErrInvalidRequest, err)
+		}
+
+		row, _ := reader.FieldPos(0)
+		credit, err := csvCredit(columns, record)
+		if err = fn(row, credit, err); err != nil {
+			return err
+		}
+	}
+}
+
+// csvCredit fills the credit from the record,every value that isn't a number where it must be is reported
+func csvCredit(columns, record []string) (models.Credit, error) {
+	var credit models.Credit
+	validationErr := &models.ValidationError{}
+
+	number := func(column, value string) int64 {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   column,
+				Message: fmt.Sprintf("the '%s' value must be a whole number", column),
+			})
+		}
+		return n
+	}
+
+	for i, column := range columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue //пропуски поймает валидатор
+		}
+
+		switch column {
+		case "UserID":
+			credit.UserID = number(column, value)
+		case "ProductID":
+			credit.ProductID = value
+		case "Amount":
+			credit.Amount = int(number(column, value))
+		case "Currency":
+			credit.Currency = value
+		case "Term":
+			credit.Term = int(number(column, value))
+		case "Scheme":
+			credit.Scheme = value
+		}
+	}
+
+	if len(validationErr.Fields) != 0 {
+		return credit, validationErr
+	}
+
+	return newCredit(credit), nil
+}
+
+func decodeJSONL(r io.Reader, fn rowFunc) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var credit models.Credit
+		err := json.Unmarshal([]byte(line), &credit)
+		if err != nil {
+			err = fmt.Errorf("%w:failed to decode the line:%s", models.ErrInvalidRequest, err)
+		}
+
+		if err = fn(row, newCredit(credit), err); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w:failed to read json lines:%s", models.ErrInvalidRequest, err)
+	}
+
+	return nil
+}
+
+// newCredit keeps only what a client sends to CreateCredit,so lines of an export can be imported again
+func newCredit(credit models.Credit) models.Credit {
+	return models.Credit{
+		UserID:        credit.UserID,
+		ProductID:     credit.ProductID,
+		Amount:        credit.Amount,
+		Currency:      credit.Currency,
+		Term:          credit.Term,
+		Scheme:        credit.Scheme,
+		Borrowers:     credit.Borrowers,
+		Collateral:    credit.Collateral,
+		Guarantors:    credit.Guarantors,
+		OperationType: "create",
+	}
+}
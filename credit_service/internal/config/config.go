@@ -1,14 +1,27 @@
 package config
 
 import (
+	"bank/credit_service/internal/domain/models"
 	"fmt"
 	"github.com/spf13/viper"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Rest    Rest
-	MongoDb MongoDb
-	Kafka   Kafka
+	Rest          Rest
+	MongoDb       MongoDb
+	Kafka         Kafka
+	Exchange      Exchange
+	Validation    Validation
+	Accrual       Accrual
+	Payoff        Payoff
+	CreditLine    CreditLine
+	Affordability Affordability
+	FloatingRate  FloatingRate
+	Documents     Documents
+	Statements    Statements
+	Auth          Auth
 }
 
 type Rest struct {
@@ -16,18 +29,118 @@ type Rest struct {
 }
 
 type MongoDb struct {
-	Host             string
-	Port             string
-	Dbname           string
-	CreditCollection string
-	UserIDCollection string
-	Username         string
-	Password         string
+	Host                    string
+	Port                    string
+	Dbname                  string
+	CreditCollection        string
+	UserIDCollection        string
+	HistoryCollection       string
+	ProductCollection       string
+	ExchangeRateCollection  string
+	LedgerCollection        string
+	PayoffQuoteCollection   string
+	CreditLineCollection    string
+	RestructuringCollection string
+	RateIndexCollection     string
+	DocumentCollection      string
+	StatementCollection     string
+	Username                string
+	Password                string
+	//имя replica set,транзакции на standalone сервере не работают
+	ReplicaSet string
+	//подключение к одному узлу replica set без обнаружения остальных,транзакции требуют replica set
+	DirectConnection bool
 }
 
 type Kafka struct {
 	Brokers string
 	Topic   string
+	Events  Events
+}
+
+// Events is where credit domain events are published,a topic per event type
+type Events struct {
+	CreditCreated       string
+	CreditUpdated       string
+	CreditStatusChanged string
+	PaymentReceived     string
+	CreditClosed        string
+	PublishInterval     time.Duration //как часто outbox проверяется на новые события
+	BatchSize           int           //сколько кредитов с событиями берется за раз
+}
+
+type Exchange struct {
+	BaseCurrency string
+	RatesFile    string             //json с курсами,если пустой-курсы берутся из Rates
+	Rates        map[string]float64 //курсы к базовой валюте на сегодня
+}
+
+// Topic returns the topic for the event type
+func (e Events) Topic(eventType string) (string, bool) {
+	topics := map[string]string{
+		models.EventCreditCreated:       e.CreditCreated,
+		models.EventCreditUpdated:       e.CreditUpdated,
+		models.EventCreditStatusChanged: e.CreditStatusChanged,
+		models.EventPaymentReceived:     e.PaymentReceived,
+		models.EventCreditClosed:        e.CreditClosed,
+	}
+	topic, ok := topics[eventType]
+	return topic, ok && topic != ""
+}
+
+// Accrual configures the daily interest accrual job
+type Accrual struct {
+	DayCount string        //30/360,ACT/365 или ACT/ACT
+	Interval time.Duration //как часто запускается,начисляются только завершившиеся дни
+}
+
+// Payoff configures early payoff quotes
+type Payoff struct {
+	QuoteTTL   time.Duration //сколько действует расчет суммы досрочного погашения
+	FeePercent float64       //комиссия за досрочное погашение,% от остатка основного долга
+}
+
+// CreditLine is the minimum monthly payment rule of revolving credit lines
+type CreditLine struct {
+	MinPaymentPercent float64 //% от основного долга на дату выписки,плюс проценты за цикл
+	MinPaymentAmount  int     //нижняя граница минимального платежа
+	PaymentDueDays    int     //сколько дней после выписки дается на платеж
+}
+
+// Affordability limits monthly payments of borrowers that declared their income
+type Affordability struct {
+	MaxDebtToIncome float64 //предел платежей по всем кредитам заемщиков к их общему доходу,%.0 - без проверки
+}
+
+// FloatingRate configures credits whose rate is a reference index plus a margin
+type FloatingRate struct {
+	ResetMonths int //как часто пересматривается ставка,если продукт не задает свой период
+}
+
+// Documents configures the credit agreement and schedule documents
+type Documents struct {
+	TemplatesDir string //шаблоны <type>_v<version>.html,если пустой-встроенные
+}
+
+// Statements configures the month-end statements job
+type Statements struct {
+	Interval time.Duration //как часто проверяется,есть ли выписки за прошлый месяц
+}
+
+// Auth is how access tokens of auth_service are checked,the secret key must be the one auth_service signs them with
+type Auth struct {
+	SecretKey string
+}
+
+// Validation is the limits every credit request must fit,products can only narrow them
+type Validation struct {
+	MinAmount  int
+	MaxAmount  int
+	MinTerm    int //в месяцах
+	MaxTerm    int
+	Currencies []string
+	//верхняя граница годовой ставки в процентах
+	MaxInterestRate float64
 }
 
 func InitConfig() (*Config, error) {
@@ -39,6 +152,51 @@ func InitConfigByPath(configPath string) (*Config, error) {
 
 	viper.SetConfigFile(configPath)
 
+	//коллекции,добавленные после первого релиза,в старых конфигах их нет
+	viper.SetDefault("mongodb.history_collection", "credit_history")
+	viper.SetDefault("mongodb.product_collection", "products")
+	viper.SetDefault("mongodb.exchange_rate_collection", "exchange_rates")
+	viper.SetDefault("mongodb.ledger_collection", "ledger")
+	viper.SetDefault("mongodb.payoff_quote_collection", "payoff_quotes")
+	viper.SetDefault("mongodb.credit_line_collection", "credit_lines")
+	viper.SetDefault("mongodb.restructuring_collection", "restructurings")
+	viper.SetDefault("mongodb.rate_index_collection", "rate_indices")
+	viper.SetDefault("mongodb.document_collection", "documents")
+	viper.SetDefault("mongodb.statement_collection", "statements")
+
+	viper.SetDefault("kafka.events.credit_created", "credit.created")
+	viper.SetDefault("kafka.events.credit_updated", "credit.updated")
+	viper.SetDefault("kafka.events.credit_status_changed", "credit.status_changed")
+	viper.SetDefault("kafka.events.payment_received", "credit.payment_received")
+	viper.SetDefault("kafka.events.credit_closed", "credit.closed")
+	viper.SetDefault("kafka.events.publish_interval", 5*time.Second)
+	viper.SetDefault("kafka.events.batch_size", 100)
+
+	viper.SetDefault("exchange.base_currency", "RUB")
+
+	viper.SetDefault("accrual.day_count", "ACT/365")
+	viper.SetDefault("accrual.interval", time.Hour)
+
+	viper.SetDefault("payoff.quote_ttl", 24*time.Hour)
+	viper.SetDefault("payoff.fee_percent", 0)
+
+	viper.SetDefault("credit_line.min_payment_percent", 5)
+	viper.SetDefault("credit_line.min_payment_amount", 500)
+	viper.SetDefault("credit_line.payment_due_days", 20)
+
+	viper.SetDefault("affordability.max_debt_to_income", 50)
+
+	viper.SetDefault("floating_rate.reset_months", 3)
+
+	viper.SetDefault("statements.interval", 6*time.Hour)
+
+	viper.SetDefault("validation.min_amount", 1000)
+	viper.SetDefault("validation.max_amount", 100_000_000)
+	viper.SetDefault("validation.min_term", 1)
+	viper.SetDefault("validation.max_term", 360)
+	viper.SetDefault("validation.currencies", []string{"RUB", "USD", "EUR"})
+	viper.SetDefault("validation.max_interest_rate", 100)
+
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("read config failed:%s", err)
 	}
@@ -48,18 +206,88 @@ func InitConfigByPath(configPath string) (*Config, error) {
 			Port: viper.GetString("rest.port"),
 		},
 		MongoDb: MongoDb{
-			Host:             viper.GetString("mongodb.host"),
-			Port:             viper.GetString("mongodb.port"),
-			Dbname:           viper.GetString("mongodb.dbname"),
-			CreditCollection: viper.GetString("mongodb.credit_collection"),
-			UserIDCollection: viper.GetString("mongodb.userid_collection"),
-			Username:         viper.GetString("mongodb.username"),
-			Password:         viper.GetString("mongodb.password"),
+			Host:                    viper.GetString("mongodb.host"),
+			Port:                    viper.GetString("mongodb.port"),
+			Dbname:                  viper.GetString("mongodb.dbname"),
+			CreditCollection:        viper.GetString("mongodb.credit_collection"),
+			UserIDCollection:        viper.GetString("mongodb.userid_collection"),
+			HistoryCollection:       viper.GetString("mongodb.history_collection"),
+			ProductCollection:       viper.GetString("mongodb.product_collection"),
+			ExchangeRateCollection:  viper.GetString("mongodb.exchange_rate_collection"),
+			LedgerCollection:        viper.GetString("mongodb.ledger_collection"),
+			PayoffQuoteCollection:   viper.GetString("mongodb.payoff_quote_collection"),
+			CreditLineCollection:    viper.GetString("mongodb.credit_line_collection"),
+			RestructuringCollection: viper.GetString("mongodb.restructuring_collection"),
+			RateIndexCollection:     viper.GetString("mongodb.rate_index_collection"),
+			DocumentCollection:      viper.GetString("mongodb.document_collection"),
+			StatementCollection:     viper.GetString("mongodb.statement_collection"),
+			Username:                viper.GetString("mongodb.username"),
+			Password:                viper.GetString("mongodb.password"),
+			ReplicaSet:              viper.GetString("mongodb.replica_set"),
+			DirectConnection:        viper.GetBool("mongodb.direct_connection"),
 		},
 		Kafka: Kafka{
 			Brokers: viper.GetString("kafka.brokers"),
 			Topic:   viper.GetString("kafka.topic"),
+			Events: Events{
+				CreditCreated:       viper.GetString("kafka.events.credit_created"),
+				CreditUpdated:       viper.GetString("kafka.events.credit_updated"),
+				CreditStatusChanged: viper.GetString("kafka.events.credit_status_changed"),
+				PaymentReceived:     viper.GetString("kafka.events.payment_received"),
+				CreditClosed:        viper.GetString("kafka.events.credit_closed"),
+				PublishInterval:     viper.GetDuration("kafka.events.publish_interval"),
+				BatchSize:           viper.GetInt("kafka.events.batch_size"),
+			},
+		},
+		Exchange: Exchange{
+			BaseCurrency: viper.GetString("exchange.base_currency"),
+			RatesFile:    viper.GetString("exchange.rates_file"),
+			Rates:        rates(viper.GetStringMap("exchange.rates")),
+		},
+		Accrual: Accrual{
+			DayCount: viper.GetString("accrual.day_count"),
+			Interval: viper.GetDuration("accrual.interval"),
+		},
+		Payoff: Payoff{
+			QuoteTTL:   viper.GetDuration("payoff.quote_ttl"),
+			FeePercent: viper.GetFloat64("payoff.fee_percent"),
+		},
+		CreditLine: CreditLine{
+			MinPaymentPercent: viper.GetFloat64("credit_line.min_payment_percent"),
+			MinPaymentAmount:  viper.GetInt("credit_line.min_payment_amount"),
+			PaymentDueDays:    viper.GetInt("credit_line.payment_due_days"),
+		},
+		Affordability: Affordability{
+			MaxDebtToIncome: viper.GetFloat64("affordability.max_debt_to_income"),
+		},
+		FloatingRate: FloatingRate{
+			ResetMonths: viper.GetInt("floating_rate.reset_months"),
+		},
+		Documents: Documents{
+			TemplatesDir: viper.GetString("documents.templates_dir"),
+		},
+		Statements: Statements{
+			Interval: viper.GetDuration("statements.interval"),
+		},
+		Auth: Auth{
+			SecretKey: viper.GetString("auth.secret_key"),
+		},
+		Validation: Validation{
+			MinAmount:       viper.GetInt("validation.min_amount"),
+			MaxAmount:       viper.GetInt("validation.max_amount"),
+			MinTerm:         viper.GetInt("validation.min_term"),
+			MaxTerm:         viper.GetInt("validation.max_term"),
+			Currencies:      viper.GetStringSlice("validation.currencies"),
+			MaxInterestRate: viper.GetFloat64("validation.max_interest_rate"),
 		},
 	}
 	return &cfg, nil
 }
+
+func rates(values map[string]interface{}) map[string]float64 {
+	res := make(map[string]float64, len(values))
+	for currency := range values {
+		res[strings.ToUpper(currency)] = viper.GetFloat64("exchange.rates." + currency) //viper приводит ключи к нижнему регистру
+	}
+	return res
+}
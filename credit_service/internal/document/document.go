@@ -0,0 +1,130 @@
+package document
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/*.html
+var embedded embed.FS
+
+// templateName is <document type>_v<version>.html
+var templateName = regexp.MustCompile(`^([a-z_]+)_v([0-9]+)\.html$`)
+
+// Renderer fills the latest version of the template of every document type.
+// A new version of a template is a new file,documents made from the old one keep its version
+type Renderer struct {
+	templates map[string]*template.Template
+	versions  map[string]string
+}
+
+// NewRenderer loads the templates from the dir,the embedded templates are used when dir is empty
+func NewRenderer(dir string) (*Renderer, error) {
+	var templates fs.FS = os.DirFS(dir)
+	if dir == "" {
+		var err error
+		if templates, err = fs.Sub(embedded, "templates"); err != nil {
+			return nil, fmt.Errorf("open embedded templates failed:%s", err)
+		}
+	}
+
+	names, err := fs.Glob(templates, "*.html")
+	if err != nil {
+		return nil, fmt.Errorf("list templates failed:%s", err)
+	}
+
+	r := &Renderer{
+		templates: make(map[string]*template.Template),
+		versions:  make(map[string]string),
+	}
+	latest := make(map[string]int)
+
+	for _, name := range names {
+		match := templateName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("template %s must be named <type>_v<version>.html", name)
+		}
+
+		docType := match[1]
+		version, _ := strconv.Atoi(match[2])
+		if version <= latest[docType] {
+			continue
+		}
+
+		tmpl, err := template.New(name).Funcs(funcs).ParseFS(templates, name)
+		if err != nil {
+			return nil, fmt.Errorf("parse template %s failed:%s", name, err)
+		}
+
+		r.templates[docType] = tmpl
+		r.versions[docType] = "v" + match[2]
+		latest[docType] = version
+	}
+
+	for _, docType := range []string{models.DocumentAgreement, models.DocumentSchedule} {
+		if r.templates[docType] == nil {
+			return nil, fmt.Errorf("no template for document %s", docType)
+		}
+	}
+
+	return r, nil
+}
+
+// Render fills the template of the document type and returns the content with the template version
+func (r *Renderer) Render(docType string, data models.DocumentData) ([]byte, string, error) {
+	tmpl, ok := r.templates[docType]
+	if !ok {
+		return nil, "", fmt.Errorf("no template for document %s", docType)
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return nil, "", fmt.Errorf("render %s failed:%s", docType, err)
+	}
+
+	return content.Bytes(), r.versions[docType], nil
+}
+
+// ContentType is the type of the rendered documents
+func (r *Renderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+var funcs = template.FuncMap{
+	"money": money,
+	"date": func(t time.Time) string {
+		return t.Format("02.01.2006")
+	},
+	"percent": func(rate float64) string {
+		return strconv.FormatFloat(rate, 'f', 2, 64) + "%"
+	},
+}
+
+// money groups digits by thousands:1234567 -> 1 234 567
+func money(amount int) string {
+	digits := strconv.Itoa(amount)
+
+	sign := ""
+	if amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(' ')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return sign + grouped.String()
+}
@@ -0,0 +1,223 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	pdfLinesPerPage = 64
+	pdfFontSize     = 8
+	pdfLeading      = 12 //расстояние между строками
+)
+
+// pdf is a minimal pdf 1.4 writer:A4 pages of monospaced text.
+// Go Mono is embedded into the document,so Cyrillic text is shown and can be copied
+type pdf struct {
+	pages [][]string
+}
+
+// addPages starts a new page and adds the lines to as many pages as they need
+func (p *pdf) addPages(lines []string) {
+	for len(lines) > pdfLinesPerPage {
+		p.pages = append(p.pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	p.pages = append(p.pages, lines)
+}
+
+func (p *pdf) write(w io.Writer) error {
+	if len(p.pages) == 0 {
+		p.pages = [][]string{{"No statements"}}
+	}
+
+	mono, err := newPDFFont()
+	if err != nil {
+		return err
+	}
+
+	//содержимое страниц собирается до шрифта,ToUnicode нужны использованные глифы
+	contents := make([]string, len(p.pages))
+	for i, lines := range p.pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL 36 806 Td\n", pdfFontSize, pdfLeading)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "<%s> Tj T*\n", mono.encode(line))
+		}
+		content.WriteString("ET")
+		contents[i] = content.String()
+	}
+
+	fontFile, err := deflate(gomono.TTF)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n") //в файле есть двоичные данные
+
+	//1-каталог,2-список страниц,3-7 шрифт,дальше страница и ее содержимое
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 8+i*2)
+	}
+
+	toUnicode := mono.toUnicode()
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /GoMono /Encoding /Identity-H /DescendantFonts [4 0 R] /ToUnicode 7 0 R >>")
+	object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /GoMono /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor 5 0 R /DW %d /CIDToGIDMap /Identity >>", mono.width))
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /GoMono /Flags 33 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 6 0 R >>",
+		mono.bbox[0], mono.bbox[1], mono.bbox[2], mono.bbox[3], mono.ascent, mono.descent, mono.ascent))
+	object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(fontFile), len(gomono.TTF), fontFile))
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(toUnicode), toUnicode))
+
+	for i, content := range contents {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 9+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write pdf failed:%s", err)
+	}
+
+	return nil
+}
+
+// pdfFont is the embedded font and the glyphs the document uses.
+// Sizes are in thousandths of the font size,as pdf font dictionaries want them
+type pdfFont struct {
+	font    *sfnt.Font
+	buf     sfnt.Buffer
+	used    map[sfnt.GlyphIndex]rune //глиф-символ,для ToUnicode
+	width   int                      //моноширинный,у всех глифов одна ширина
+	ascent  int
+	descent int
+	bbox    [4]int
+}
+
+func newPDFFont() (*pdfFont, error) {
+	f, err := sfnt.Parse(gomono.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("parse font failed:%s", err)
+	}
+
+	p := &pdfFont{font: f, used: make(map[sfnt.GlyphIndex]rune)}
+	ppem := fixed.I(1000)
+
+	glyph, err := f.GlyphIndex(&p.buf, 'M')
+	if err != nil {
+		return nil, fmt.Errorf("font has no glyph 'M':%s", err)
+	}
+
+	advance, err := f.GlyphAdvance(&p.buf, glyph, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("font has no advance of 'M':%s", err)
+	}
+
+	metrics, err := f.Metrics(&p.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("font has no metrics:%s", err)
+	}
+
+	bounds, err := f.Bounds(&p.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("font has no bounds:%s", err)
+	}
+
+	//в sfnt ось Y направлена вниз,в pdf вверх
+	p.width = advance.Round()
+	p.ascent = metrics.Ascent.Round()
+	p.descent = -metrics.Descent.Round()
+	p.bbox = [4]int{bounds.Min.X.Round(), -bounds.Max.Y.Round(), bounds.Max.X.Round(), -bounds.Min.Y.Round()}
+
+	return p, nil
+}
+
+// encode is the text as glyph ids for a pdf hex string,characters the font doesn't have become '?'
+func (p *pdfFont) encode(s string) string {
+	var hex strings.Builder
+	for _, r := range s {
+		glyph, err := p.font.GlyphIndex(&p.buf, r)
+		if err != nil || glyph == 0 {
+			r = '?'
+			glyph, _ = p.font.GlyphIndex(&p.buf, r)
+		}
+
+		p.used[glyph] = r
+		fmt.Fprintf(&hex, "%04X", glyph)
+	}
+	return hex.String()
+}
+
+// toUnicode is the CMap that maps the used glyphs back to text,so the text can be searched and copied
+func (p *pdfFont) toUnicode() string {
+	glyphs := make([]sfnt.GlyphIndex, 0, len(p.used))
+	for glyph := range p.used {
+		glyphs = append(glyphs, glyph)
+	}
+	slices.Sort(glyphs)
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	for len(glyphs) > 0 {
+		n := min(len(glyphs), 100) //в одном блоке не больше 100 записей
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", n)
+		for _, glyph := range glyphs[:n] {
+			fmt.Fprintf(&cmap, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{p.used[glyph]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+		glyphs = glyphs[n:]
+	}
+
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+
+	return cmap.String()
+}
+
+func deflate(data []byte) (string, error) {
+	var buf bytes.Buffer
+
+	writer := zlib.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return "", fmt.Errorf("compress font failed:%s", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("compress font failed:%s", err)
+	}
+
+	return buf.String(), nil
+}
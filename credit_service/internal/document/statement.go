@@ -0,0 +1,93 @@
+package document
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteStatementsCSV writes a row per transaction,every statement starts with the opening balance and ends with the closing one
+func WriteStatementsCSV(w io.Writer, statements []models.CreditStatement) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"credit_id", "currency", "date", "type", "description", "principal", "interest", "penalties", "total"}}
+
+	for _, statement := range statements {
+		row := func(date time.Time, kind, description string, principal, interest, penalties int) []string {
+			return []string{
+				statement.CreditID,
+				statement.Currency,
+				date.Format(time.DateOnly),
+				kind,
+				description,
+				strconv.Itoa(principal),
+				strconv.Itoa(interest),
+				strconv.Itoa(penalties),
+				strconv.Itoa(principal + interest + penalties),
+			}
+		}
+
+		opening, closing := statement.OpeningBalance, statement.ClosingBalance
+		rows = append(rows, row(statement.From, "opening_balance", "", opening.Principal, opening.Interest, opening.Penalties))
+
+		for _, tx := range statement.Transactions {
+			rows = append(rows, row(tx.ValueDate, tx.Type, tx.Description, tx.Principal, tx.Interest, tx.Penalties))
+		}
+		if statement.Interest != 0 {
+			rows = append(rows, row(statement.To.AddDate(0, 0, -1), models.EntryAccrual, "interest for the period", 0, statement.Interest, 0))
+		}
+
+		rows = append(rows, row(statement.To.AddDate(0, 0, -1), "closing_balance", "", closing.Principal, closing.Interest, closing.Penalties))
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write csv failed:%s", err)
+	}
+
+	return nil
+}
+
+// WriteStatementsPDF writes the statements as a plain text pdf,a page per statement at least
+func WriteStatementsPDF(w io.Writer, statements []models.CreditStatement) error {
+	doc := &pdf{}
+
+	for _, statement := range statements {
+		last := statement.To.AddDate(0, 0, -1).Format(time.DateOnly)
+
+		lines := []string{
+			fmt.Sprintf("Statement of credit %s", statement.CreditID),
+			fmt.Sprintf("Period %s - %s, currency %s", statement.From.Format(time.DateOnly), last, statement.Currency),
+			"",
+			balanceLine("Opening balance", statement.OpeningBalance),
+			"",
+			fmt.Sprintf("%-10s  %-14s  %-34s  %12s  %10s  %10s", "Date", "Type", "Description", "Principal", "Interest", "Penalties"),
+		}
+
+		for _, tx := range statement.Transactions {
+			lines = append(lines, fmt.Sprintf("%-10s  %-14s  %-34.34s  %12d  %10d  %10d",
+				tx.ValueDate.Format(time.DateOnly), tx.Type, tx.Description, tx.Principal, tx.Interest, tx.Penalties))
+		}
+
+		lines = append(lines,
+			"",
+			fmt.Sprintf("Disbursed    %12d", statement.Disbursed),
+			fmt.Sprintf("Payments     %12d", statement.Payments),
+			fmt.Sprintf("Interest     %12d", statement.Interest),
+			fmt.Sprintf("Penalties    %12d", statement.Penalties),
+			fmt.Sprintf("Adjustments  %12d", statement.Adjustments),
+			"",
+			balanceLine("Closing balance", statement.ClosingBalance),
+		)
+
+		doc.addPages(lines)
+	}
+
+	return doc.write(w)
+}
+
+func balanceLine(title string, balance models.DebtBalance) string {
+	return fmt.Sprintf("%s: %d (principal %d, interest %d, penalties %d)", title, balance.Total, balance.Principal, balance.Interest, balance.Penalties)
+}
@@ -0,0 +1,17 @@
+package models
+
+const (
+	DayCount30360  = "30/360"
+	DayCountACT365 = "ACT/365"
+	DayCountACTACT = "ACT/ACT"
+)
+
+// AccrualReport is the result of one run of the interest accrual job
+type AccrualReport struct {
+	Through       string         //последний начисленный день
+	Credits       int            //сколько кредитов проверено
+	CreditLines   int            //сколько кредитных линий проверено
+	PostedEntries int            //сколько дней начислено
+	Interest      map[string]int //начисленные проценты по валютам
+	Failed        []string       `json:",omitempty"` //id кредитов с ошибкой,они начислятся при следующем запуске
+}
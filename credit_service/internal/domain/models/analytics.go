@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AnalyticsFilter limits analytics to credits issued in [From,To),zero values mean no bound
+type AnalyticsFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+type OutstandingByCurrency struct {
+	Currency             string `bson:"_id"`
+	Count                int    `bson:"count"`
+	OutstandingPrincipal int    `bson:"outstandingPrincipal"`
+}
+
+type WeightedAverageRate struct {
+	Currency             string  `bson:"_id"`
+	OutstandingPrincipal int     `bson:"outstandingPrincipal"`
+	WeightedAverageRate  float64 `bson:"weightedAverageRate"` //ставка,взвешенная по остатку долга
+}
+
+type IssuedByMonth struct {
+	Month    string `bson:"month"` //YYYY-MM
+	Currency string `bson:"currency"`
+	Count    int    `bson:"count"`
+	Volume   int    `bson:"volume"`
+}
+
+type MaturityBucket struct {
+	Year                 int    `bson:"year"`
+	Quarter              int    `bson:"quarter"`
+	Currency             string `bson:"currency"`
+	Count                int    `bson:"count"`
+	OutstandingPrincipal int    `bson:"outstandingPrincipal"`
+}
+
+type BorrowerExposure struct {
+	UserID       int64
+	Count        int
+	Exposure     float64 //в базовой валюте
+	BaseCurrency string
+}
+
+// UserCurrencyExposure is the exposure of a user in one currency before conversion to the base currency
+type UserCurrencyExposure struct {
+	UserID               int64  `bson:"userID"`
+	Currency             string `bson:"currency"`
+	Count                int    `bson:"count"`
+	OutstandingPrincipal int    `bson:"outstandingPrincipal"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+)
+
+// Approval is the maker-checker state of a credit above the approval threshold of its product.
+// The credit isn't disbursed until a staff member other than the one who created it approves it
+type Approval struct {
+	Threshold   int                `bson:"threshold"`   //порог продукта в валюте кредита
+	RequestedBy string             `bson:"requestedBy"` //кто создал кредит,не может его одобрить
+	RequestedAt time.Time          `bson:"requestedAt"`
+	Decisions   []ApprovalDecision `bson:"decisions,omitempty" json:",omitempty"`
+}
+
+type ApprovalDecision struct {
+	Decision  string    `bson:"decision"` //approve,reject
+	Actor     string    `bson:"actor"`
+	Comment   string    `bson:"comment,omitempty" json:",omitempty"`
+	DecidedAt time.Time `bson:"decidedAt"`
+}
+
+// ApprovalRequest is the comment of the approver,a rejection must explain itself
+type ApprovalRequest struct {
+	Comment string `validate:"max=1000"`
+}
@@ -0,0 +1,26 @@
+package models
+
+const (
+	BorrowerPrimary = "primary"
+	BorrowerCo      = "co_borrower"
+)
+
+// Borrower is one of the users jointly liable for the credit,the primary borrower is Credit.UserID
+type Borrower struct {
+	UserID        int64  `bson:"userID" validate:"required"`
+	Role          string `bson:"role" validate:"required,oneof=primary co_borrower"`
+	MonthlyIncome int    `bson:"monthlyIncome,omitempty" validate:"omitempty,gt=0"` //в валюте кредита
+}
+
+// HasBorrower reports whether the user is the primary borrower or a co-borrower of the credit
+func (c Credit) HasBorrower(userID int64) bool {
+	if c.UserID == userID {
+		return true
+	}
+	for _, borrower := range c.Borrowers {
+		if borrower.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
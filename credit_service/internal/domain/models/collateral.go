@@ -0,0 +1,27 @@
+package models
+
+const (
+	CollateralCar        = "car"
+	CollateralRealEstate = "real_estate"
+	CollateralDeposit    = "deposit"
+	CollateralOther      = "other"
+
+	LienPending    = "pending"    //залог еще не зарегистрирован
+	LienRegistered = "registered" //обременение зарегистрировано
+	LienReleased   = "released"   //обременение снято
+)
+
+// Collateral secures the credit,its value is in the credit currency
+type Collateral struct {
+	Type           string `bson:"type" validate:"required,oneof=car real_estate deposit other"`
+	Description    string `bson:"description"`
+	AppraisedValue int    `bson:"appraisedValue" validate:"required,gt=0"`
+	ValuationDate  string `bson:"valuationDate" validate:"required,datetime=2006-01-02"`
+	LienStatus     string `bson:"lienStatus" validate:"omitempty,oneof=pending registered released"` //pending по умолчанию
+}
+
+// Guarantor is a user who answers for the part of the debt
+type Guarantor struct {
+	UserID int64   `bson:"userID" validate:"required"`
+	Share  float64 `bson:"share" validate:"required,gt=0,lte=100"` //гарантируемая доля долга,%
+}
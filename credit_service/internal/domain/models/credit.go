@@ -1,14 +1,55 @@
 package models
 
+import "time"
+
+const (
+	CreditStatusActive    = "active"
+	CreditStatusClosed    = "closed"           //полностью погашен
+	CreditStatusCancelled = "cancelled"        //удален
+	CreditStatusPending   = "pending_approval" //ждет одобрения,еще не выдан
+	CreditStatusRejected  = "rejected"
+)
+
 type Credit struct {
-	ID                 string  `bson:"_id,omitempty"`
-	UserID             int64   `bson:"userID" validate:"required_if=OperationType create"`
-	Amount             int     `bson:"amount" validate:"required"`
-	Currency           string  `bson:"currency" validate:"required"`
-	AnnualInterestRate float64 `bson:"annualInterestRate" validate:"required"` //годовая % ставка
-	Term               int     `bson:"term" validate:"required"`               //срок кредита
-	DateOfIssue        string  `bson:"dateOfIssue"`                            //дата выдачи
-	MaturityDate       string  `bson:"maturityDate"`                           //срок погашения
-	MonthlyPayment     int     `bson:"monthlyPayment"`
-	OperationType      string
+	ID                   string        `bson:"_id,omitempty"`
+	UserID               int64         `bson:"userID" validate:"required_if=OperationType create"`
+	ProductID            string        `bson:"productID" validate:"required_if=OperationType create"`
+	Amount               int           `bson:"amount" validate:"required,credit_amount"`
+	Currency             string        `bson:"currency" validate:"required,currency"`
+	AnnualInterestRate   float64       `bson:"annualInterestRate"`                   //годовая % ставка,берется из продукта
+	Term                 int           `bson:"term" validate:"required,credit_term"` //срок кредита
+	DateOfIssue          string        `bson:"dateOfIssue"`                          //дата выдачи
+	MaturityDate         string        `bson:"maturityDate"`                         //срок погашения
+	MonthlyPayment       int           `bson:"monthlyPayment"`
+	IssuedAt             time.Time     `bson:"issuedAt"`
+	MaturesAt            time.Time     `bson:"maturesAt"`
+	OutstandingPrincipal int           `bson:"outstandingPrincipal"`                                     //остаток основного долга
+	Scheme               string        `bson:"scheme" validate:"omitempty,oneof=annuity differentiated"` //annuity,differentiated
+	Fees                 []Fee         `bson:"fees"`                                                     //берутся из продукта
+	APR                  float64       `bson:"apr"`                                                      //полная стоимость кредита с комиссиями,% годовых
+	Borrowers            []Borrower    `bson:"borrowers,omitempty" validate:"omitempty,dive"`            //основной заемщик и созаемщики
+	Collateral           []Collateral  `bson:"collateral,omitempty" validate:"omitempty,dive"`
+	Guarantors           []Guarantor   `bson:"guarantors,omitempty" validate:"omitempty,dive"`
+	RefinancedFrom       []string      `bson:"refinancedFrom,omitempty" json:",omitempty"` //кредиты,погашенные этим кредитом
+	RefinancedBy         string        `bson:"refinancedBy,omitempty" json:",omitempty"`
+	LTV                  float64       `bson:"ltv,omitempty" json:",omitempty"`       //сумма кредита к стоимости залога,%
+	RateIndex            string        `bson:"rateIndex,omitempty" json:",omitempty"` //плавающая ставка:индекс плюс RateMargin
+	RateMargin           float64       `bson:"rateMargin,omitempty" json:",omitempty"`
+	RateResetMonths      int           `bson:"rateResetMonths,omitempty" json:",omitempty"` //период пересмотра ставки
+	NextRateReset        *time.Time    `bson:"nextRateReset,omitempty" json:",omitempty"`
+	RateHistory          []RatePeriod  `bson:"rateHistory,omitempty" json:",omitempty"` //прежние ставки,прошлые дни начисляются по ним
+	Schedule             []Payment     `bson:"schedule,omitempty" json:",omitempty"`    //график после реструктуризации,иначе строится по условиям кредита
+	Approval             *Approval     `bson:"approval,omitempty" json:",omitempty"`    //для кредитов выше порога продукта
+	DeletedAt            *time.Time    `bson:"deletedAt,omitempty" json:",omitempty"`   //soft delete
+	Status               string        `bson:"status"`
+	Outbox               []CreditEvent `bson:"outbox,omitempty" json:"-"` //события,еще не отправленные в kafka
+	OperationType        string
+	//льготный период,берется из продукта
+	InitialPeriod `bson:",inline"`
+}
+
+// RatePeriod is a rate the credit had before,it was in effect until the day the next rate started
+type RatePeriod struct {
+	AnnualInterestRate float64   `bson:"annualInterestRate"`
+	Until              time.Time `bson:"until"` //первый день следующей ставки
 }
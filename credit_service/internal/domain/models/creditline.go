@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	CreditLineStatusActive = "active"
+	CreditLineStatusClosed = "closed"
+)
+
+// CreditLine is a revolving credit:the borrower draws money up to the limit,repayments restore the available limit
+type CreditLine struct {
+	ID                 string     `bson:"_id,omitempty"`
+	UserID             int64      `bson:"userID" validate:"required"`
+	Currency           string     `bson:"currency" validate:"required,currency"`
+	Limit              int        `bson:"limit" validate:"required,credit_amount"`
+	AnnualInterestRate float64    `bson:"annualInterestRate" validate:"required,interest_rate"`
+	BillingDay         int        `bson:"billingDay" validate:"omitempty,min=1,max=28"` //день формирования выписки,по умолчанию день открытия
+	Drawn              int        `bson:"drawn"`                                        //использованная часть лимита(основной долг)
+	Available          int        `bson:"-"`
+	Status             string     `bson:"status"`
+	OpenedAt           time.Time  `bson:"openedAt"`
+	ClosedAt           *time.Time `bson:"closedAt,omitempty" json:",omitempty"`
+}
+
+// Drawdown takes money from the credit line,DrawdownID makes retries safe
+type Drawdown struct {
+	DrawdownID string
+	Amount     int `validate:"required,gt=0"`
+}
+
+type DrawdownResult struct {
+	EntryID   string
+	Drawn     int
+	Available int
+}
+
+// CreditLineStatement is one billing cycle [From,To),the minimum payment is due by DueDate
+type CreditLineStatement struct {
+	From             time.Time
+	To               time.Time //дата выписки
+	OpeningBalance   int       //основной долг и проценты
+	Drawdowns        int
+	Repayments       int
+	InterestCharged  int
+	ClosingBalance   int
+	ClosingPrincipal int
+	MinimumPayment   int
+	DueDate          time.Time
+	PaidByDueDate    int  //платежи с даты выписки по дату платежа включительно
+	Overdue          bool //дата платежа прошла,а минимальный платеж не внесен
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	DocumentAgreement = "agreement" //договор
+	DocumentSchedule  = "schedule"  //график платежей
+)
+
+// Document is a generated document of the credit.SHA256 of the content is saved with it
+// and checked on every download,so a changed document is never handed out
+type Document struct {
+	ID              string    `bson:"_id"`
+	CreditID        string    `bson:"creditID"`
+	Type            string    `bson:"type"`            //agreement,schedule
+	TemplateVersion string    `bson:"templateVersion"` //версия шаблона,из которого сделан документ
+	ContentType     string    `bson:"contentType"`
+	SHA256          string    `bson:"sha256"`
+	Size            int       `bson:"size"`
+	CreatedAt       time.Time `bson:"createdAt"`
+	Content         []byte    `bson:"content,omitempty" json:"-"` //отдается отдельным запросом
+}
+
+// DocumentData is what the document templates are filled with
+type DocumentData struct {
+	Credit        Credit //с заемщиками
+	Schedule      []Payment
+	OneOffFees    int //удерживаются при выдаче
+	TotalInterest int
+	TotalPayment  int
+	GeneratedAt   time.Time
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidID              = errors.New("invalid ID")
+	ErrCreditNotFound         = errors.New("no credit found with provided ID")
+	ErrCreditsNotFound        = errors.New("no credits found")
+	ErrUserCreditsNotFound    = errors.New("no credits found for provided userID")
+	ErrCreditAlreadyExists    = errors.New("you already took this credit")
+	ErrUserNotFound           = errors.New("provided userID doesn't exist")
+	ErrUserAlreadyInserted    = errors.New("userID already inserted into MongoDB")
+	ErrHistoryNotFound        = errors.New("no history found for provided credit ID")
+	ErrProductNotFound        = errors.New("no product found with provided ID")
+	ErrProductsNotFound       = errors.New("no products found")
+	ErrInvalidProduct         = errors.New("invalid product")
+	ErrProductMismatch        = errors.New("credit doesn't match product")
+	ErrUnknownScheme          = errors.New("unknown repayment scheme")
+	ErrExchangeRateNotFound   = errors.New("no exchange rate found")
+	ErrExchangeRatesNotFound  = errors.New("no exchange rates found")
+	ErrInvalidRequest         = errors.New("invalid request")
+	ErrUnbalancedEntry        = errors.New("journal entry is not balanced")
+	ErrEntryAlreadyPosted     = errors.New("journal entry already posted")
+	ErrUnknownAccount         = errors.New("unknown ledger account")
+	ErrPaymentExceedsDebt     = errors.New("payment exceeds the debt")
+	ErrCreditNotActive        = errors.New("credit is not active")
+	ErrCreditHasPayments      = errors.New("credit already has payments")
+	ErrUnknownDayCount        = errors.New("unknown day count convention")
+	ErrPayoffQuoteNotFound    = errors.New("no payoff quote found with provided ID")
+	ErrPayoffQuoteExpired     = errors.New("payoff quote expired")
+	ErrPayoffQuoteOutdated    = errors.New("credit balances changed since the payoff quote")
+	ErrPayoffAmountMismatch   = errors.New("payment doesn't match the payoff quote")
+	ErrCreditLineNotFound     = errors.New("no credit line found with provided ID")
+	ErrCreditLimitExceeded    = errors.New("drawdown exceeds the available limit")
+	ErrCreditLineNotActive    = errors.New("credit line is not active")
+	ErrCreditLineHasDebt      = errors.New("credit line still has debt")
+	ErrCreditRestructured     = errors.New("credit was restructured")
+	ErrIndexValueNotFound     = errors.New("no rate index value found")
+	ErrIndexValuesNotFound    = errors.New("no rate index values found")
+	ErrRestructuringsNotFound = errors.New("no restructurings found for provided credit ID")
+	ErrCreditNotPending       = errors.New("credit is not waiting for approval")
+	ErrApprovalNotAllowed     = errors.New("actor can't decide on the credit")
+	ErrApprovalRequired       = errors.New("credit needs approval")
+	ErrDocumentNotFound       = errors.New("no document found with provided ID")
+	ErrDocumentsNotFound      = errors.New("no documents found for provided credit ID")
+	ErrDocumentTampered       = errors.New("document content doesn't match its hash")
+	ErrStatementsNotFound     = errors.New("no statements found for provided credit ID")
+	ErrStaffOnly              = errors.New("operation is allowed only to staff")
+	ErrForbidden              = errors.New("customer can't act for another user")
+	ErrUnauthenticated        = errors.New("missing or invalid access token")
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError holds every invalid field of a request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return "invalid req:" + strings.Join(messages, ",")
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	EventCreditCreated       = "CreditCreated"
+	EventCreditUpdated       = "CreditUpdated"
+	EventCreditStatusChanged = "CreditStatusChanged"
+	EventPaymentReceived     = "PaymentReceived"
+	EventCreditClosed        = "CreditClosed"
+)
+
+// EventSchemaVersion is bumped on every incompatible change of CreditEvent,consumers read it from the event and the message header
+const EventSchemaVersion = 1
+
+// CreditEvent is a domain event of a credit.It's stored in the credit outbox together with the change and published to kafka
+type CreditEvent struct {
+	ID         string          `bson:"id"`
+	Type       string          `bson:"type"`
+	Version    int             `bson:"version"`
+	CreditID   string          `bson:"creditID"`
+	UserID     int64           `bson:"userID"`
+	Actor      string          `bson:"actor"`
+	OccurredAt time.Time       `bson:"occurredAt"`
+	Data       CreditEventData `bson:"data"`
+}
+
+// CreditEventData is the state of the credit after the change
+type CreditEventData struct {
+	ProductID            string             `bson:"productID"`
+	Amount               int                `bson:"amount"`
+	Currency             string             `bson:"currency"`
+	Term                 int                `bson:"term"`
+	AnnualInterestRate   float64            `bson:"annualInterestRate"`
+	MonthlyPayment       int                `bson:"monthlyPayment"`
+	OutstandingPrincipal int                `bson:"outstandingPrincipal"`
+	MaturesAt            time.Time          `bson:"maturesAt"`
+	Status               string             `bson:"status"`
+	PreviousStatus       string             `bson:"previousStatus,omitempty" json:",omitempty"` //для CreditStatusChanged
+	PaymentAmount        int                `bson:"paymentAmount,omitempty" json:",omitempty"`  //для PaymentReceived
+	Schedule             []ScheduledPayment `bson:"schedule,omitempty" json:",omitempty"`       //текущий график,по нему напоминают о платежах
+}
+
+// ScheduledPayment is one payment of the credit schedule,zero Amount is a month of a payment holiday
+type ScheduledPayment struct {
+	Number  int       `bson:"number"`
+	DueDate time.Time `bson:"dueDate"`
+	Amount  int       `bson:"amount"`
+}
@@ -0,0 +1,28 @@
+package models
+
+type ExchangeRate struct {
+	ID       string  `bson:"_id,omitempty"`
+	Currency string  `bson:"currency"`
+	Base     string  `bson:"base"`
+	Rate     float64 `bson:"rate"` //сколько единиц base стоит одна единица currency
+	Date     string  `bson:"date"` //YYYY-MM-DD,дата курса
+	AsOf     string  `bson:"asOf"` //на какую дату запрашивали курс
+	Source   string  `bson:"source"`
+}
+
+type CurrencyTotal struct {
+	Currency     string
+	Count        int
+	Amount       int
+	Rate         float64
+	RateDate     string
+	AmountInBase float64
+}
+
+type Summary struct {
+	UserID       int64 `json:",omitempty"`
+	BaseCurrency string
+	Date         string //на какую дату взяты курсы
+	Totals       []CurrencyTotal
+	TotalInBase  float64
+}
@@ -0,0 +1,13 @@
+package models
+
+const (
+	FeeOneOff  = "one_off"
+	FeeMonthly = "monthly"
+)
+
+type Fee struct {
+	Name    string  `bson:"name"`    //origination,insurance,service
+	Type    string  `bson:"type"`    //one_off,monthly
+	Amount  int     `bson:"amount"`  //фиксированная сумма
+	Percent float64 `bson:"percent"` //% от суммы кредита
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	ActionCreate      = "create"
+	ActionUpdate      = "update"
+	ActionDelete      = "delete"
+	ActionRepay       = "repayment"
+	ActionRestructure = "restructuring"
+	ActionRateReset   = "rate_reset"
+	ActionApprove     = "approval"
+	ActionReject      = "rejection"
+)
+
+type CreditHistory struct {
+	ID        string        `bson:"_id,omitempty"`
+	CreditID  string        `bson:"creditID"`
+	Action    string        `bson:"action"`
+	Actor     string        `bson:"actor"`     //кто изменил
+	RequestID string        `bson:"requestID"` //id http запроса
+	Timestamp time.Time     `bson:"timestamp"`
+	Changes   []FieldChange `bson:"changes"`
+}
+
+type FieldChange struct {
+	Field    string      `bson:"field"`
+	OldValue interface{} `bson:"oldValue"`
+	NewValue interface{} `bson:"newValue"`
+}
@@ -0,0 +1,22 @@
+package models
+
+const (
+	ImportCSV   = "csv"
+	ImportJSONL = "jsonl" //JSON Lines:кредит на строку,как в выгрузке
+)
+
+// ImportReport is the result of a bulk import,in a dry run nothing is created and Imported stays 0
+type ImportReport struct {
+	DryRun   bool
+	Rows     int
+	Valid    int //прошли все проверки CreateCredit
+	Imported int
+	Errors   []ImportRowError `json:",omitempty"`
+}
+
+// ImportRowError is why the row of the file wasn't imported,Row is the line number in the file
+type ImportRowError struct {
+	Row     int
+	Message string
+	Fields  []FieldError `json:",omitempty"`
+}
@@ -0,0 +1,148 @@
+package models
+
+import "time"
+
+const (
+	AccountCash               = "cash"                //деньги банка
+	AccountLoan               = "loan"                //основной долг клиента по кредиту
+	AccountInterestReceivable = "interest_receivable" //начисленные,но не уплаченные проценты
+	AccountPenaltyReceivable  = "penalty_receivable"  //начисленные штрафы
+	AccountInterestIncome     = "interest_income"
+	AccountFeeIncome          = "fee_income"
+	AccountPenaltyIncome      = "penalty_income"
+)
+
+// CreditAccounts are kept per credit,the other accounts are bank-wide
+var CreditAccounts = []string{AccountLoan, AccountInterestReceivable, AccountPenaltyReceivable}
+
+// Accounts is the chart of accounts
+var Accounts = []string{
+	AccountCash,
+	AccountLoan,
+	AccountInterestReceivable,
+	AccountPenaltyReceivable,
+	AccountInterestIncome,
+	AccountFeeIncome,
+	AccountPenaltyIncome,
+}
+
+const (
+	EntryDisbursement   = "disbursement"
+	EntryRepayment      = "repayment"
+	EntryAccrual        = "accrual"
+	EntryPenalty        = "penalty"
+	EntryReversal       = "reversal"       //сторно проводок кредита при изменении или отмене
+	EntryDrawdown       = "drawdown"       //выборка по кредитной линии
+	EntryCapitalization = "capitalization" //начисленные проценты переносятся в основной долг
+)
+
+// JournalEntry is a double-entry posting,sum of debits always equals sum of credits
+type JournalEntry struct {
+	ID          string      `bson:"_id"` //ключ идемпотентности:тип проводки и ее источник
+	Type        string      `bson:"type"`
+	CreditID    string      `bson:"creditID"`
+	Currency    string      `bson:"currency"`
+	ValueDate   time.Time   `bson:"valueDate"` //дата операции
+	PostedAt    time.Time   `bson:"postedAt"`
+	Description string      `bson:"description"`
+	Lines       []EntryLine `bson:"lines"`
+}
+
+type EntryLine struct {
+	Account  string `bson:"account"`
+	CreditID string `bson:"creditID,omitempty" json:",omitempty"` //для счетов кредита
+	Debit    int    `bson:"debit"`
+	Credit   int    `bson:"credit"`
+}
+
+// Balanced reports whether the entry has lines,each line is one-sided and positive and debits equal credits
+func (e JournalEntry) Balanced() bool {
+	if len(e.Lines) == 0 {
+		return false
+	}
+
+	var debit, credit int
+	for _, line := range e.Lines {
+		if line.Debit < 0 || line.Credit < 0 || (line.Debit == 0) == (line.Credit == 0) {
+			return false
+		}
+		debit += line.Debit
+		credit += line.Credit
+	}
+
+	return debit == credit
+}
+
+// AccountBalance is the turnover of the account,Balance is debit minus credit
+type AccountBalance struct {
+	Account  string `bson:"account"`
+	Currency string `bson:"currency"`
+	Debit    int    `bson:"debit"`
+	Credit   int    `bson:"credit"`
+	Balance  int    `bson:"balance"`
+}
+
+type TrialBalance struct {
+	AsOf        time.Time
+	Accounts    []AccountBalance
+	TotalDebit  map[string]int //по валютам
+	TotalCredit map[string]int
+	Balanced    bool
+}
+
+type LedgerFilter struct {
+	Account  string
+	CreditID string
+	Currency string
+	From     time.Time
+	To       time.Time //не включая
+}
+
+type AccountStatement struct {
+	Account        string
+	CreditID       string `json:",omitempty"`
+	Currency       string `json:",omitempty"`
+	From           time.Time
+	To             time.Time
+	OpeningBalance int
+	Lines          []StatementLine
+	ClosingBalance int
+}
+
+type StatementLine struct {
+	EntryID     string
+	Type        string
+	ValueDate   time.Time
+	Description string
+	Debit       int
+	Credit      int
+	Balance     int
+}
+
+type LedgerCheck struct {
+	Balanced          bool
+	UnbalancedEntries []string
+}
+
+// Repayment is money received from the borrower,PaymentID makes retries safe
+type Repayment struct {
+	PaymentID string
+	QuoteID   string //досрочное погашение по расчету,сумма должна совпадать с Total
+	Amount    int    `validate:"required,gt=0"`
+	Date      string `validate:"omitempty,datetime=2006-01-02"` //сегодня по умолчанию
+}
+
+type RepaymentResult struct {
+	EntryID              string
+	Penalty              int
+	Fee                  int `json:",omitempty"`
+	Interest             int
+	Principal            int
+	OutstandingPrincipal int
+	Status               string
+}
+
+type Penalty struct {
+	Amount int    `validate:"required,gt=0"`
+	Reason string `validate:"required"`
+}
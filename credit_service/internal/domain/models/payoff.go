@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PayoffQuote is the amount that closes the credit on Date,the payoff payment refers to it until it expires
+type PayoffQuote struct {
+	ID              string    `bson:"_id"`
+	CreditID        string    `bson:"creditID"`
+	Currency        string    `bson:"currency"`
+	Date            string    `bson:"date"` //YYYY-MM-DD
+	Principal       int       `bson:"principal"`
+	AccruedInterest int       `bson:"accruedInterest"` //начисленные и еще не начисленные проценты до Date
+	Penalties       int       `bson:"penalties"`
+	Fees            int       `bson:"fees"` //комиссия за досрочное погашение
+	Total           int       `bson:"total"`
+	CreatedAt       time.Time `bson:"createdAt"`
+	ExpiresAt       time.Time `bson:"expiresAt"`
+}
@@ -0,0 +1,37 @@
+package models
+
+const (
+	ProductConsumerLoan = "consumer_loan"
+	ProductCarLoan      = "car_loan"
+	ProductMortgage     = "mortgage"
+)
+
+type Product struct {
+	ID         string          `bson:"_id,omitempty"`
+	Name       string          `bson:"name" validate:"required"`
+	Type       string          `bson:"type" validate:"required"` //consumer_loan,car_loan,mortgage
+	Currencies []string        `bson:"currencies" validate:"required,dive,currency"`
+	MinAmount  int             `bson:"minAmount" validate:"required,credit_amount"`
+	MaxAmount  int             `bson:"maxAmount" validate:"required,credit_amount"`
+	MinTerm    int             `bson:"minTerm" validate:"required,credit_term"` //в месяцах
+	MaxTerm    int             `bson:"maxTerm" validate:"required,credit_term"`
+	RateGrid   []RateGridEntry `bson:"rateGrid" validate:"required,dive"`
+	Fees       []Fee           `bson:"fees"`
+	MaxLTV     float64         `bson:"maxLTV" validate:"omitempty,gt=0,lte=100"` //предел суммы кредита к стоимости залога,%.0 - беззалоговый продукт
+	RateIndex  string          `bson:"rateIndex,omitempty" json:",omitempty"`    //плавающая ставка:ставки сетки становятся маржой к индексу
+	//период пересмотра плавающей ставки в месяцах,по умолчанию из конфига
+	RateResetMonths int `bson:"rateResetMonths,omitempty" json:",omitempty" validate:"omitempty,gt=0,lte=12"`
+	//сумма в валюте,выше которой кредит ждет одобрения второго сотрудника
+	ApprovalThresholds map[string]int `bson:"approvalThresholds,omitempty" json:",omitempty" validate:"omitempty,dive,keys,currency,endkeys,gt=0"`
+	//льготный период в начале каждого кредита продукта
+	InitialPeriod `bson:",inline"`
+}
+
+// RateGridEntry sets the annual interest rate for credits whose term and amount fall into the bounds(inclusive)
+type RateGridEntry struct {
+	MinTerm            int     `bson:"minTerm"`
+	MaxTerm            int     `bson:"maxTerm"`
+	MinAmount          int     `bson:"minAmount"`
+	MaxAmount          int     `bson:"maxAmount"`
+	AnnualInterestRate float64 `bson:"annualInterestRate" validate:"interest_rate"`
+}
@@ -0,0 +1,53 @@
+package models
+
+const (
+	SchemeAnnuity        = "annuity"        //равные платежи
+	SchemeDifferentiated = "differentiated" //равные доли основного долга
+)
+
+// InitialPeriod is the start of the credit before amortization,it's part of the term.
+// First GraceMonths pass without payments and their interest is added to the principal,
+// then InterestOnlyMonths only interest and monthly fees are paid
+type InitialPeriod struct {
+	GraceMonths        int `bson:"graceMonths,omitempty" json:",omitempty" validate:"gte=0"`
+	InterestOnlyMonths int `bson:"interestOnlyMonths,omitempty" json:",omitempty" validate:"gte=0"`
+}
+
+// Months is the length of the initial period
+func (p InitialPeriod) Months() int {
+	return p.GraceMonths + p.InterestOnlyMonths
+}
+
+type QuoteRequest struct {
+	ProductID string `validate:"required"`
+	Amount    int    `validate:"required,credit_amount"`
+	Currency  string `validate:"required,currency"`
+	Term      int    `validate:"required,credit_term"`
+	Scheme    string `validate:"omitempty,oneof=annuity differentiated"` //annuity по умолчанию
+}
+
+type Quote struct {
+	ProductID          string
+	Amount             int
+	Currency           string
+	Term               int
+	Scheme             string
+	AnnualInterestRate float64
+	MonthlyPayment     int //первый платеж после льготного периода,для дифференцированной схемы
+	TotalInterest      int
+	TotalFees          int
+	TotalPayment       int
+	APR                float64
+	Schedule           []Payment
+	InitialPeriod
+}
+
+type Payment struct {
+	Number    int    `bson:"number"`
+	Date      string `bson:"date"`
+	Payment   int    `bson:"payment"` //principal+interest+fees
+	Principal int    `bson:"principal"`
+	Interest  int    `bson:"interest"`
+	Fees      int    `bson:"fees"`
+	Balance   int    `bson:"balance"` //остаток основного долга после платежа
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// IndexValue is the value of a reference rate index,for example the key rate,from Date on
+type IndexValue struct {
+	Index string  `bson:"index"`
+	Date  string  `bson:"date" validate:"required,datetime=2006-01-02"` //с какой даты действует
+	Rate  float64 `bson:"rate" validate:"gte=0,lte=100"`                //% годовых
+}
+
+// RateResetReport is the result of one run of floating rate resets
+type RateResetReport struct {
+	Date    string
+	Credits int //кредиты,у которых наступила дата пересмотра
+	Resets  []RateReset
+	Failed  []string `json:",omitempty"`
+}
+
+type RateReset struct {
+	CreditID       string
+	IndexDate      string //значение индекса,по которому пересмотрена ставка
+	OldRate        float64
+	NewRate        float64
+	MonthlyPayment int
+	NextRateReset  time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+// RefinanceRequest closes active credits of the user with a new credit,
+// by default its amount covers their payoff amounts and one-off fees of the new credit
+type RefinanceRequest struct {
+	UserID    int64    `validate:"required"`
+	ProductID string   `validate:"required"`
+	CreditIDs []string `validate:"required,min=1,dive,required"`
+	Amount    int      `validate:"omitempty,credit_amount"`
+	Currency  string   `validate:"required,currency"`
+	Term      int      `validate:"required,credit_term"`
+	Scheme    string   `validate:"omitempty,oneof=annuity differentiated"`
+}
+
+type Refinancing struct {
+	Credit      Credit
+	Closed      []RefinancedCredit
+	PayoffTotal int
+	CashOut     int //выдается заемщику сверх погашения и комиссий
+}
+
+type RefinancedCredit struct {
+	CreditID     string
+	QuoteID      string
+	PayoffAmount int
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	RestructuringHoliday = "payment_holiday"
+	RestructuringTerms   = "restructuring" //новый срок или ставка
+
+	InterestCapitalize = "capitalize" //проценты за каникулы прибавляются к основному долгу
+	InterestDefer      = "defer"      //проценты за каникулы делятся поровну между оставшимися платежами
+)
+
+// PaymentHoliday suspends payments of the credit for Months months
+type PaymentHoliday struct {
+	Months            int    `validate:"required,gt=0,lte=12"`
+	InterestTreatment string `validate:"required,oneof=capitalize defer"`
+	Reason            string `validate:"required"`
+}
+
+// RestructureRequest changes the remaining term or the rate of the credit,at least one of them is required
+type RestructureRequest struct {
+	Term               int     `validate:"omitempty,credit_term"`   //количество оставшихся платежей
+	AnnualInterestRate float64 `validate:"omitempty,interest_rate"` //новая годовая % ставка
+	Reason             string  `validate:"required"`
+}
+
+// Restructuring is a change of the credit schedule,the schedule before the change is kept for history
+type Restructuring struct {
+	ID                   string    `bson:"_id,omitempty"`
+	CreditID             string    `bson:"creditID"`
+	Type                 string    `bson:"type"`
+	HolidayMonths        int       `bson:"holidayMonths,omitempty" json:",omitempty"`
+	InterestTreatment    string    `bson:"interestTreatment,omitempty" json:",omitempty"`
+	CapitalizedInterest  int       `bson:"capitalizedInterest,omitempty" json:",omitempty"` //начисленные проценты,перенесенные в основной долг
+	OldTerm              int       `bson:"oldTerm"`
+	NewTerm              int       `bson:"newTerm"`
+	OldInterestRate      float64   `bson:"oldInterestRate"`
+	NewInterestRate      float64   `bson:"newInterestRate"`
+	OutstandingPrincipal int       `bson:"outstandingPrincipal"` //основной долг на дату изменения
+	EffectiveDate        string    `bson:"effectiveDate"`
+	Reason               string    `bson:"reason"`
+	ApprovedBy           string    `bson:"approvedBy"` //сотрудник из токена
+	Actor                string    `bson:"actor"`
+	CreatedAt            time.Time `bson:"createdAt"`
+	OriginalSchedule     []Payment `bson:"originalSchedule"`
+	Schedule             []Payment `bson:"schedule"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	StatementJSON = "json"
+	StatementCSV  = "csv"
+	StatementPDF  = "pdf"
+)
+
+// CreditStatement is the movement of the debt of the credit in [From,To).
+// Balances are what the borrowers owe,so the closing balance is the opening one plus disbursed,interest,
+// penalties and adjustments minus payments
+type CreditStatement struct {
+	ID             string                 `bson:"_id,omitempty" json:",omitempty"` //у выписок на конец месяца:creditID:YYYY-MM
+	CreditID       string                 `bson:"creditID"`
+	UserID         int64                  `bson:"userID"`
+	Currency       string                 `bson:"currency"`
+	From           time.Time              `bson:"from"`
+	To             time.Time              `bson:"to"` //не включая
+	OpeningBalance DebtBalance            `bson:"openingBalance"`
+	Disbursed      int                    `bson:"disbursed"`
+	Payments       int                    `bson:"payments"`
+	Interest       int                    `bson:"interest"` //начисленные проценты
+	Penalties      int                    `bson:"penalties"`
+	Adjustments    int                    `bson:"adjustments"` //сторно
+	ClosingBalance DebtBalance            `bson:"closingBalance"`
+	Transactions   []StatementTransaction `bson:"transactions"` //проценты за день не перечисляются,они в Interest
+	CreatedAt      time.Time              `bson:"createdAt,omitempty" json:",omitempty"`
+}
+
+type DebtBalance struct {
+	Principal int `bson:"principal"`
+	Interest  int `bson:"interest"`
+	Penalties int `bson:"penalties"`
+	Total     int `bson:"total"`
+}
+
+// StatementTransaction is a posting of the credit,the amounts are changes of the debt
+type StatementTransaction struct {
+	EntryID     string    `bson:"entryID"`
+	Type        string    `bson:"type"`
+	ValueDate   time.Time `bson:"valueDate"`
+	Description string    `bson:"description"`
+	Principal   int       `bson:"principal"`
+	Interest    int       `bson:"interest"`
+	Penalties   int       `bson:"penalties"`
+}
+
+// UserStatement is the statements of every issued credit of the user for the same period
+type UserStatement struct {
+	UserID  int64
+	From    time.Time
+	To      time.Time
+	Credits []CreditStatement
+}
+
+type StatementReport struct {
+	Period    string //YYYY-MM
+	Credits   int
+	Generated int
+	Failed    []string `json:",omitempty"`
+}
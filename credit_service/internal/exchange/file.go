@@ -0,0 +1,46 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type ratesFile struct {
+	Base  string `json:"base"`
+	Rates []struct {
+		Currency string  `json:"currency"`
+		Rate     float64 `json:"rate"`
+		Date     string  `json:"date"`
+	} `json:"rates"`
+}
+
+// NewFileProvider loads the rates from a json file:
+// {"base":"RUB","rates":[{"currency":"USD","rate":92.5,"date":"2024-05-01"}]}
+func NewFileProvider(path string) (*InMemoryProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates file failed:%s", err)
+	}
+
+	var file ratesFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode exchange rates file failed:%s", err)
+	}
+
+	if file.Base == "" {
+		return nil, fmt.Errorf("exchange rates file %s has no base currency", path)
+	}
+
+	p := NewInMemoryProvider(file.Base)
+	p.source = "file"
+
+	for _, rate := range file.Rates {
+		if rate.Rate <= 0 {
+			return nil, fmt.Errorf("exchange rate of %s on %s must be positive", rate.Currency, rate.Date)
+		}
+		p.AddRate(rate.Currency, rate.Rate, rate.Date)
+	}
+
+	return p, nil
+}
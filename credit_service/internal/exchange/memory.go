@@ -0,0 +1,104 @@
+package exchange
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// InMemoryProvider keeps the rates of currencies to its base currency
+type InMemoryProvider struct {
+	mu     sync.RWMutex
+	base   string
+	source string
+	rates  map[string][]models.ExchangeRate //отсортированы по дате
+}
+
+func NewInMemoryProvider(base string) *InMemoryProvider {
+	return &InMemoryProvider{
+		base:   base,
+		source: "memory",
+		rates:  make(map[string][]models.ExchangeRate),
+	}
+}
+
+// NewInMemoryProviderFromMap creates the provider with the rates dated today
+func NewInMemoryProviderFromMap(base string, rates map[string]float64) *InMemoryProvider {
+	p := NewInMemoryProvider(base)
+
+	today := time.Now().Format(dateLayout)
+	for currency, rate := range rates {
+		p.AddRate(currency, rate, today)
+	}
+
+	return p
+}
+
+func (p *InMemoryProvider) AddRate(currency string, rate float64, date string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rates[currency] = append(p.rates[currency], models.ExchangeRate{
+		Currency: currency,
+		Base:     p.base,
+		Rate:     rate,
+		Date:     date,
+		Source:   p.source,
+	})
+
+	sort.Slice(p.rates[currency], func(i, j int) bool {
+		return p.rates[currency][i].Date < p.rates[currency][j].Date
+	})
+}
+
+// Rate returns the latest known rate of currency to base on the date.Cross rates are calculated through the provider base
+func (p *InMemoryProvider) Rate(_ context.Context, currency, base string, date time.Time) (models.ExchangeRate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	day := date.Format(dateLayout)
+
+	currencyRate, err := p.rateToBase(currency, day)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	baseRate, err := p.rateToBase(base, day)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	rateDate := currencyRate.Date
+	if baseRate.Date < rateDate {
+		rateDate = baseRate.Date
+	}
+
+	return models.ExchangeRate{
+		Currency: currency,
+		Base:     base,
+		Rate:     currencyRate.Rate / baseRate.Rate,
+		Date:     rateDate,
+		Source:   p.source,
+	}, nil
+}
+
+func (p *InMemoryProvider) rateToBase(currency, day string) (models.ExchangeRate, error) {
+	if currency == p.base {
+		return models.ExchangeRate{Currency: currency, Base: p.base, Rate: 1, Date: day}, nil
+	}
+
+	rates := p.rates[currency]
+
+	for i := len(rates) - 1; i >= 0; i-- {
+		if rates[i].Date <= day {
+			return rates[i], nil
+		}
+	}
+
+	return models.ExchangeRate{}, fmt.Errorf("%w for %s on %s", models.ErrExchangeRateNotFound, currency, day)
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
-	"fmt"
 	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -72,7 +71,7 @@ func (k *KafkaConsumer) KafkaConsumer(ctx context.Context, cfg *config.Config, l
 			credit.UserID = int64(binary.BigEndian.Uint64(msg.Value))
 
 			if err = k.storage.NewUserIDCollection(ctx, credit.UserID); err != nil {
-				if err.Error() == fmt.Sprintf("userID:%v already inserted into MongoDB", credit.UserID) {
+				if errors.Is(err, models.ErrUserAlreadyInserted) {
 					continue //в начало цикла
 				}
 				logger.Errorf("Error inserting userID into MongoDB: %v", err)
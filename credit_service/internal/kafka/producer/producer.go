@@ -0,0 +1,138 @@
+package producer
+
+import (
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+// KafkaProducer relays credit events from the outbox to kafka.
+// An event leaves the outbox only after kafka accepted it,so consumers get it at least once and must dedupe by event ID
+type KafkaProducer struct {
+	storage service.Storage
+}
+
+func NewKafkaProducer(storage service.Storage) *KafkaProducer {
+	return &KafkaProducer{storage: storage}
+}
+
+func (k *KafkaProducer) KafkaProducer(ctx context.Context, cfg *config.Config, logger *logrus.Logger) error {
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(cfg.Kafka.Brokers),
+		Balancer:               &kafka.Hash{}, //события одного кредита попадают в одну партицию и не перемешиваются
+		RequiredAcks:           kafka.RequireAll,
+		BatchTimeout:           10 * time.Millisecond, //по умолчанию writer ждет 1s перед каждой отправкой
+		AllowAutoTopicCreation: true,
+	}
+
+	defer func() {
+		if err := writer.Close(); err != nil {
+			logger.Errorf("failed to close kafka writer:%s", err)
+			return
+		}
+		logger.Info("kafka writer closed")
+	}()
+
+	for {
+		select {
+		case <-time.After(cfg.Kafka.Events.PublishInterval):
+			if err := k.publishPending(ctx, cfg.Kafka.Events, writer); err != nil {
+				logger.Errorf("failed to publish credit events:%s", err)
+			}
+		case <-ctx.Done():
+			logger.Info("kafka producer shutting down...")
+			return nil
+		}
+	}
+}
+
+func (k *KafkaProducer) publishPending(ctx context.Context, cfg config.Events, writer *kafka.Writer) error {
+	events, err := k.storage.GetPendingEvents(ctx, cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var failedCredits int
+	var publishErr error
+
+	for _, creditEvents := range groupByCredit(events) {
+		if err = publish(ctx, cfg, writer, creditEvents); err != nil {
+			failedCredits++
+			publishErr = err
+			continue //события кредита уйдут заново в следующий раз,остальные кредиты не ждут
+		}
+
+		ids := make([]string, 0, len(creditEvents))
+		for _, event := range creditEvents {
+			ids = append(ids, event.ID)
+		}
+
+		if err = k.storage.DeletePublishedEvents(ctx, creditEvents[0].CreditID, ids); err != nil {
+			return err
+		}
+	}
+
+	if failedCredits != 0 {
+		return fmt.Errorf("events of %v credits were not published:%s", failedCredits, publishErr)
+	}
+
+	return nil
+}
+
+// groupByCredit splits events into per-credit batches keeping the order of events within a credit
+func groupByCredit(events []models.CreditEvent) [][]models.CreditEvent {
+	var groups [][]models.CreditEvent
+	index := make(map[string]int)
+
+	for _, event := range events {
+		i, ok := index[event.CreditID]
+		if !ok {
+			i = len(groups)
+			index[event.CreditID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], event)
+	}
+
+	return groups
+}
+
+// publish writes all events of one credit in a single call
+func publish(ctx context.Context, cfg config.Events, writer *kafka.Writer, events []models.CreditEvent) error {
+	msgs := make([]kafka.Message, 0, len(events))
+
+	for _, event := range events {
+		topic, ok := cfg.Topic(event.Type)
+		if !ok {
+			return fmt.Errorf("no topic for event type %s", event.Type)
+		}
+
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal event failed:%s", err)
+		}
+
+		msgs = append(msgs, kafka.Message{
+			Topic: topic,
+			Key:   []byte(event.CreditID),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: "eventType", Value: []byte(event.Type)},
+				{Key: "schemaVersion", Value: []byte(strconv.Itoa(event.Version))},
+			},
+		})
+	}
+
+	if err := writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("write messages failed:%s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/render"
+	"net/http"
+	"time"
+)
+
+// RunAccrual accrues interest through the 'through' query param(YYYY-MM-DD),yesterday by default
+func (h *Handler) RunAccrual() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		through := today.AddDate(0, 0, -1)
+
+		if date := r.URL.Query().Get("through"); date != "" {
+			day, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:invalid 'through',use YYYY-MM-DD:%s", models.ErrInvalidRequest, err))
+				return
+			}
+			if day.After(today) {
+				h.writeError(w, r, fmt.Errorf("%w:'through' can't be in the future", models.ErrInvalidRequest))
+				return
+			}
+			through = day
+		}
+
+		res, err := h.service.RunAccrual(r.Context(), through)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/render"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultTopBorrowers = 10
+
+func (h *Handler) OutstandingByCurrency() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		res, err := h.service.OutstandingByCurrency(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) WeightedAverageRate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		res, err := h.service.WeightedAverageRate(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) IssuedByMonth() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		res, err := h.service.IssuedByMonth(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) MaturityProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		res, err := h.service.MaturityProfile(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) TopBorrowers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		limit := defaultTopBorrowers
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				h.writeError(w, r, fmt.Errorf("%w:limit must be a positive number", models.ErrInvalidRequest))
+				return
+			}
+		}
+
+		res, err := h.service.TopBorrowers(r.Context(), filter, limit)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+// analyticsFilter reads the issue date range from the from and to query params(YYYY-MM-DD),to is inclusive
+func analyticsFilter(r *http.Request) (filter models.AnalyticsFilter, err error) {
+	if from := r.URL.Query().Get("from"); from != "" {
+		filter.From, err = time.Parse(time.DateOnly, from)
+		if err != nil {
+			return filter, fmt.Errorf("%w:invalid 'from' date,use YYYY-MM-DD:%s", models.ErrInvalidRequest, err)
+		}
+	}
+
+	if to := r.URL.Query().Get("to"); to != "" {
+		filter.To, err = time.Parse(time.DateOnly, to)
+		if err != nil {
+			return filter, fmt.Errorf("%w:invalid 'to' date,use YYYY-MM-DD:%s", models.ErrInvalidRequest, err)
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("%w:'from' must not be after 'to'", models.ErrInvalidRequest)
+	}
+
+	return filter, nil
+}
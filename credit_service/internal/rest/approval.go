@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+// GetPendingApprovals lists the credits waiting for approval,the oldest first
+func (h *Handler) GetPendingApprovals() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		credits, err := h.service.GetPendingApprovals(r.Context())
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, credits)
+	}
+}
+
+func (h *Handler) ApproveCredit() http.HandlerFunc {
+	return h.decideCredit(h.service.ApproveCredit)
+}
+
+func (h *Handler) RejectCredit() http.HandlerFunc {
+	return h.decideCredit(h.service.RejectCredit)
+}
+
+// decideCredit reads the comment of the approver,the body may be omitted when there's nothing to say
+func (h *Handler) decideCredit(decide func(ctx context.Context, creditID string, req models.ApprovalRequest) (models.Credit, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req models.ApprovalRequest
+
+		if r.ContentLength != 0 {
+			if err := h.decodeJSONFromBody(w, r, &req); err != nil {
+				return
+			}
+		}
+
+		if err := h.ValidateValues(w, r, &req); err != nil {
+			return
+		}
+
+		credit, err := decide(r.Context(), chi.URLParam(r, "id"), req)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, credit)
+	}
+}
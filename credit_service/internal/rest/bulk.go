@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"bank/credit_service/internal/bulk"
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/render"
+	"net/http"
+	"strconv"
+)
+
+// ImportCredits imports the credits of the body in the 'format' query param:jsonl(default) or csv.
+// With dryRun=true the rows are only checked,the report lists the error of every bad row
+func (h *Handler) ImportCredits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = models.ImportJSONL
+		}
+
+		var dryRun bool
+		if param := r.URL.Query().Get("dryRun"); param != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(param); err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:'dryRun' must be true or false", models.ErrInvalidRequest))
+				return
+			}
+		}
+
+		res, err := bulk.NewImporter(h.service, h.validator).Import(r.Context(), r.Body, format, dryRun)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+// ExportCredits streams every credit as JSON Lines
+func (h *Handler) ExportCredits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		writer := &exportWriter{ResponseWriter: w}
+
+		if err := bulk.Export(r.Context(), writer, h.service); err != nil {
+			if !writer.written { //ответ еще не начат
+				h.writeError(w, r, err)
+				return
+			}
+			h.logger.Errorf("failed to export credits:%s", err)
+		}
+	}
+}
+
+// exportWriter sets the content type on the first write,so an error before it is still written as json
+type exportWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.written = true
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -2,28 +2,30 @@ package rest
 
 import (
 	"bank/credit_service/internal/domain/models"
-	"context"
+	"bank/credit_service/internal/validation"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 type Handler struct {
-	logger  *logrus.Logger
-	service Service
+	logger    *logrus.Logger
+	service   Service
+	validator *validation.Validator
+	secretKey string //ключ подписи токенов auth_service
 }
 
-func NewHandler(logger *logrus.Logger, service Service) *Handler {
+func NewHandler(logger *logrus.Logger, service Service, validator *validation.Validator, secretKey string) *Handler {
 	return &Handler{
-		logger:  logger,
-		service: service,
+		logger:    logger,
+		service:   service,
+		validator: validator,
+		secretKey: secretKey,
 	}
 }
 
@@ -37,14 +39,13 @@ func (h *Handler) CreateCredit() http.HandlerFunc {
 		}
 
 		credit.OperationType = "create"
-		if err := h.ValidateValues(w, &credit); err != nil {
+		if err := h.ValidateValues(w, r, &credit); err != nil {
 			return
 		}
 
-		createdCredit, err := h.service.CreateCredit(context.Background(), credit)
+		createdCredit, err := h.service.CreateCredit(r.Context(), credit)
 		if err != nil {
-			h.logger.Errorf("create credit failed:%s", err)
-			http.Error(w, fmt.Sprintf("create credit failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -57,15 +58,9 @@ func (h *Handler) CreateCredit() http.HandlerFunc {
 func (h *Handler) GetCredits() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		credits, err := h.service.GetCredits(context.Background())
+		credits, err := h.service.GetCredits(r.Context())
 		if err != nil {
-			if strings.Contains(err.Error(), "no credits found") {
-				h.logger.Error("no credits found")
-				http.Error(w, err.Error(), http.StatusNotFound)
-				return
-			}
-			h.logger.Errorf("get credits failed:%s", err)
-			http.Error(w, fmt.Sprintf("get credits failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -78,15 +73,9 @@ func (h *Handler) GetCreditById() http.HandlerFunc {
 
 		creditID := chi.URLParam(r, "id") //получаем id из url req
 
-		credit, err := h.service.GetCreditById(context.Background(), creditID)
+		credit, err := h.service.GetCreditById(r.Context(), creditID)
 		if err != nil {
-			if strings.Contains(err.Error(), "no credit found with provided ID") {
-				h.logger.Errorf("no credit found with provided ID: %s", creditID)
-				http.Error(w, fmt.Sprintf("no credit found with provided ID: %s", creditID), http.StatusNotFound)
-				return
-			}
-			h.logger.Errorf("get credit by id failed:%s", err)
-			http.Error(w, fmt.Sprintf("get credit by id failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -97,26 +86,15 @@ func (h *Handler) GetCreditById() http.HandlerFunc {
 func (h *Handler) GetCreditsByUserId() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		userIdStr := chi.URLParam(r, "id") //получаем id из url req
-
-		userId, err := strconv.Atoi(userIdStr)
+		userID, err := userIDFromURL(r)
 		if err != nil {
-			h.logger.Errorf("convert userId to int failed:%s", err)
-			http.Error(w, fmt.Sprintf("convert userId to int failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
-		userIdInt64 := int64(userId)
-
-		credits, err := h.service.GetCreditsByUserId(context.Background(), userIdInt64)
+		credits, err := h.service.GetCreditsByUserId(r.Context(), userID)
 		if err != nil {
-			if strings.Contains(err.Error(), "no credits found for provided userID") {
-				h.logger.Errorf("no credits found for provided userID: %v", userIdInt64)
-				http.Error(w, fmt.Sprintf("no credits found for provided userID: %v", userIdInt64), http.StatusNotFound)
-				return
-			}
-			h.logger.Errorf("get credit by userId failed:%s", err)
-			http.Error(w, fmt.Sprintf("get credit by userId failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -133,21 +111,15 @@ func (h *Handler) UpdateCredit() http.HandlerFunc { //return credit
 			return
 		}
 
-		if err := h.ValidateValues(w, &credit); err != nil {
+		if err := h.ValidateValues(w, r, &credit); err != nil {
 			return
 		}
 
 		credit.ID = chi.URLParam(r, "id")
 
-		updatedCredit, err := h.service.UpdateCredit(context.Background(), credit)
+		updatedCredit, err := h.service.UpdateCredit(r.Context(), credit)
 		if err != nil {
-			if strings.Contains(err.Error(), "no credit found with provided ID") {
-				h.logger.Errorf("no credit found with provided ID: %s", credit.ID)
-				http.Error(w, fmt.Sprintf("no credit found with provided ID: %s", credit.ID), http.StatusNotFound)
-				return
-			}
-			h.logger.Errorf("update credit failed:%s", err)
-			http.Error(w, fmt.Sprintf("update credit failed:%s", err), http.StatusInternalServerError)
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -162,14 +134,8 @@ func (h *Handler) DeleteCredit() http.HandlerFunc {
 
 		creditID := chi.URLParam(r, "id")
 
-		if err := h.service.DeleteCredit(context.Background(), creditID); err != nil {
-			if strings.Contains(err.Error(), "no credit found with provided ID") {
-				h.logger.Errorf("no credit found with provided ID: %s", creditID)
-				http.Error(w, fmt.Sprintf("no credit found with provided ID: %s", creditID), http.StatusNotFound)
-				return
-			}
-			h.logger.Errorf("delete credit failed:%s", err)
-			http.Error(w, fmt.Sprintf("delete credit failed:%s", err), http.StatusInternalServerError)
+		if err := h.service.DeleteCredit(r.Context(), creditID); err != nil {
+			h.writeError(w, r, err)
 			return
 		}
 
@@ -177,29 +143,48 @@ func (h *Handler) DeleteCredit() http.HandlerFunc {
 	}
 }
 
+func (h *Handler) GetCreditHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		creditID := chi.URLParam(r, "id")
+
+		history, err := h.service.GetCreditHistory(r.Context(), creditID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, history)
+	}
+}
+
 func (h *Handler) decodeJSONFromBody(w http.ResponseWriter, r *http.Request, data interface{}) error {
 	if err := render.DecodeJSON(r.Body, data); err != nil {
 		if errors.Is(err, io.EOF) {
-			h.logger.Errorf("request body is empty,you must enter the data: %s", err)
-			http.Error(w, "request body is empty,you must enter the data", http.StatusBadRequest)
+			err = fmt.Errorf("%w:request body is empty,you must enter the data", models.ErrInvalidRequest)
+			h.writeError(w, r, err)
 			return err
 		}
-		h.logger.Errorf("failed to decode request body: %s", err)
-		http.Error(w, fmt.Sprintf("failed to decode request body:%s", err), http.StatusBadRequest)
+		err = fmt.Errorf("%w:failed to decode request body:%s", models.ErrInvalidRequest, err)
+		h.writeError(w, r, err)
 		return err
 	}
 	return nil
 }
 
-func (h *Handler) ValidateValues(w http.ResponseWriter, credit *models.Credit) error {
-	validate := validator.New()
-
-	if err := validate.Struct(credit); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-		h.logger.Errorf("invalid req:%s", ValidationErrors(validateErr))
-		http.Error(w, fmt.Sprintf("invalid req:%s", ValidationErrors(validateErr)), http.StatusBadRequest)
+func (h *Handler) ValidateValues(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	if err := h.validator.Struct(data); err != nil {
+		h.writeError(w, r, err)
 		return err
 	}
 
 	return nil
 }
+
+func userIDFromURL(r *http.Request) (int64, error) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w:userID must be a number:%s", models.ErrInvalidID, err)
+	}
+	return userID, nil
+}
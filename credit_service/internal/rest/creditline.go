@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+func (h *Handler) CreateCreditLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var line models.CreditLine
+
+		if err := h.decodeJSONFromBody(w, r, &line); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &line); err != nil {
+			return
+		}
+
+		createdLine, err := h.service.CreateCreditLine(r.Context(), line)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, createdLine)
+	}
+}
+
+func (h *Handler) GetCreditLineById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		line, err := h.service.GetCreditLineById(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, line)
+	}
+}
+
+func (h *Handler) Drawdown() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var drawdown models.Drawdown
+
+		if err := h.decodeJSONFromBody(w, r, &drawdown); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &drawdown); err != nil {
+			return
+		}
+
+		res, err := h.service.Drawdown(r.Context(), chi.URLParam(r, "id"), drawdown)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) RepayCreditLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var repayment models.Repayment
+
+		if err := h.decodeJSONFromBody(w, r, &repayment); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &repayment); err != nil {
+			return
+		}
+
+		res, err := h.service.RepayCreditLine(r.Context(), chi.URLParam(r, "id"), repayment)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+// GetCreditLineStatements returns statements of the billing cycles that already ended
+func (h *Handler) GetCreditLineStatements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		statements, err := h.service.GetCreditLineStatements(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, statements)
+	}
+}
+
+func (h *Handler) CloseCreditLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if err := h.service.CloseCreditLine(r.Context(), chi.URLParam(r, "id")); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, "closed successfully")
+	}
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+// GetDocuments lists the documents of the credit with their hashes,the content is downloaded by GetDocument
+func (h *Handler) GetDocuments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		documents, err := h.service.GetDocuments(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, documents)
+	}
+}
+
+// GetDocument writes the document content,the X-Content-SHA256 header lets the client check it
+func (h *Handler) GetDocument() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		document, err := h.service.GetDocument(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "documentID"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", document.ContentType)
+		w.Header().Set("X-Content-SHA256", document.SHA256)
+
+		if _, err = w.Write(document.Content); err != nil {
+			h.logger.Errorf("failed to write document %s:%s", document.ID, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"github.com/go-chi/chi"
 	"net/http"
+	"time"
 )
 
 type Service interface {
@@ -14,16 +15,124 @@ type Service interface {
 	GetCreditsByUserId(ctx context.Context, userID int64) ([]models.Credit, error)
 	UpdateCredit(ctx context.Context, credit models.Credit) (updatedCredit models.Credit, err error)
 	DeleteCredit(ctx context.Context, id string) error
+	GetCreditHistory(ctx context.Context, creditID string) ([]models.CreditHistory, error)
+	CreateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	GetProducts(ctx context.Context) ([]models.Product, error)
+	GetProductById(ctx context.Context, id string) (models.Product, error)
+	UpdateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	Quote(ctx context.Context, req models.QuoteRequest) (models.Quote, error)
+	GetSummary(ctx context.Context, userID int64) (models.Summary, error)
+	GetExchangeRateHistory(ctx context.Context, currency string) ([]models.ExchangeRate, error)
+	OutstandingByCurrency(ctx context.Context, filter models.AnalyticsFilter) ([]models.OutstandingByCurrency, error)
+	WeightedAverageRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.WeightedAverageRate, error)
+	IssuedByMonth(ctx context.Context, filter models.AnalyticsFilter) ([]models.IssuedByMonth, error)
+	MaturityProfile(ctx context.Context, filter models.AnalyticsFilter) ([]models.MaturityBucket, error)
+	TopBorrowers(ctx context.Context, filter models.AnalyticsFilter, limit int) ([]models.BorrowerExposure, error)
+	Repay(ctx context.Context, creditID string, repayment models.Repayment) (models.RepaymentResult, error)
+	ChargePenalty(ctx context.Context, creditID string, penalty models.Penalty) (models.JournalEntry, error)
+	GetTrialBalance(ctx context.Context, asOf time.Time) (models.TrialBalance, error)
+	GetAccountStatement(ctx context.Context, filter models.LedgerFilter) (models.AccountStatement, error)
+	CheckLedger(ctx context.Context) (models.LedgerCheck, error)
+	RunAccrual(ctx context.Context, through time.Time) (models.AccrualReport, error)
+	GetPayoffQuote(ctx context.Context, creditID string, date time.Time) (models.PayoffQuote, error)
+	CreateCreditLine(ctx context.Context, line models.CreditLine) (models.CreditLine, error)
+	GetCreditLineById(ctx context.Context, id string) (models.CreditLine, error)
+	Drawdown(ctx context.Context, lineID string, drawdown models.Drawdown) (models.DrawdownResult, error)
+	RepayCreditLine(ctx context.Context, lineID string, repayment models.Repayment) (models.RepaymentResult, error)
+	CloseCreditLine(ctx context.Context, lineID string) error
+	GetCreditLineStatements(ctx context.Context, lineID string) ([]models.CreditLineStatement, error)
+	Refinance(ctx context.Context, req models.RefinanceRequest) (models.Refinancing, error)
+	GrantPaymentHoliday(ctx context.Context, creditID string, holiday models.PaymentHoliday) (models.Restructuring, error)
+	RestructureCredit(ctx context.Context, creditID string, req models.RestructureRequest) (models.Restructuring, error)
+	GetRestructurings(ctx context.Context, creditID string) ([]models.Restructuring, error)
+	SetIndexValue(ctx context.Context, value models.IndexValue) error
+	GetIndexValues(ctx context.Context, index string) ([]models.IndexValue, error)
+	RunRateResets(ctx context.Context, date time.Time) (models.RateResetReport, error)
+	GetPendingApprovals(ctx context.Context) ([]models.Credit, error)
+	ApproveCredit(ctx context.Context, creditID string, req models.ApprovalRequest) (models.Credit, error)
+	RejectCredit(ctx context.Context, creditID string, req models.ApprovalRequest) (models.Credit, error)
+	GetDocuments(ctx context.Context, creditID string) ([]models.Document, error)
+	GetDocument(ctx context.Context, creditID, documentID string) (models.Document, error)
+	GetCreditStatement(ctx context.Context, creditID string, from, to time.Time) (models.CreditStatement, error)
+	GetUserStatement(ctx context.Context, userID int64, from, to time.Time) (models.UserStatement, error)
+	GetMonthlyStatements(ctx context.Context, creditID string) ([]models.CreditStatement, error)
+	RunMonthlyStatements(ctx context.Context, month time.Time) (models.StatementReport, error)
+	ImportCredit(ctx context.Context, credit models.Credit, dryRun bool) (models.Credit, error)
+	ExportCredits(ctx context.Context, fn func(models.Credit) error) error
 }
 
 func (h *Handler) InitRoutes(r *chi.Mux) http.Handler {
+	r.Use(h.RequestMeta)
+
 	r.Route("/credits", func(r chi.Router) {
 		r.Post("/", h.CreateCredit())
+		r.Post("/quote", h.Quote())
+		r.Post("/refinance", h.Refinance())
 		r.Get("/", h.GetCredits())
+		r.Get("/summary", h.GetSummary())
+		r.Get("/approvals", h.GetPendingApprovals())
 		r.Get("/objectID/{id}", h.GetCreditById())
+		r.Get("/objectID/{id}/history", h.GetCreditHistory())
+		r.With(h.StaffOnly).Post("/objectID/{id}/payments", h.Repay()) //платеж проводит банк,когда деньги пришли
+		r.With(h.StaffOnly).Post("/objectID/{id}/penalties", h.ChargePenalty())
+		r.Get("/objectID/{id}/payoff", h.GetPayoffQuote())
+		r.With(h.StaffOnly).Post("/objectID/{id}/holidays", h.GrantPaymentHoliday())
+		r.With(h.StaffOnly).Post("/objectID/{id}/restructurings", h.RestructureCredit())
+		r.Get("/objectID/{id}/restructurings", h.GetRestructurings())
+		r.Post("/objectID/{id}/approve", h.ApproveCredit())
+		r.Post("/objectID/{id}/reject", h.RejectCredit())
+		r.Get("/objectID/{id}/documents", h.GetDocuments())
+		r.Get("/objectID/{id}/documents/{documentID}", h.GetDocument())
+		r.Get("/objectID/{id}/statement", h.GetCreditStatement())
+		r.Get("/objectID/{id}/statements", h.GetMonthlyStatements())
 		r.Get("/userID/{id}", h.GetCreditsByUserId())
+		r.Get("/userID/{id}/summary", h.GetUserSummary())
+		r.Get("/userID/{id}/statement", h.GetUserStatement())
 		r.Put("/{id}", h.UpdateCredit())
 		r.Delete("/{id}", h.DeleteCredit())
 	})
+	r.Route("/credit-lines", func(r chi.Router) {
+		r.Post("/", h.CreateCreditLine())
+		r.Get("/{id}", h.GetCreditLineById())
+		r.Delete("/{id}", h.CloseCreditLine())
+		r.Post("/{id}/drawdowns", h.Drawdown())
+		r.With(h.StaffOnly).Post("/{id}/payments", h.RepayCreditLine())
+		r.Get("/{id}/statements", h.GetCreditLineStatements())
+	})
+	r.Get("/exchange-rates/{currency}", h.GetExchangeRateHistory())
+	r.Route("/analytics", func(r chi.Router) {
+		r.Use(h.StaffOnly)
+		r.Get("/outstanding", h.OutstandingByCurrency())
+		r.Get("/weighted-rate", h.WeightedAverageRate())
+		r.Get("/issued-by-month", h.IssuedByMonth())
+		r.Get("/maturity-profile", h.MaturityProfile())
+		r.Get("/top-borrowers", h.TopBorrowers())
+	})
+	r.Route("/ledger", func(r chi.Router) {
+		r.Use(h.StaffOnly)
+		r.Get("/trial-balance", h.GetTrialBalance())
+		r.Get("/accounts/{account}/statement", h.GetAccountStatement())
+		r.Get("/check", h.CheckLedger())
+	})
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(h.StaffOnly)
+		r.Post("/accruals", h.RunAccrual())
+		r.Post("/rate-resets", h.RunRateResets())
+		r.Post("/statements", h.RunMonthlyStatements())
+		r.Post("/credits/import", h.ImportCredits())
+		r.Get("/credits/export", h.ExportCredits())
+		r.Route("/rate-indices/{index}/values", func(r chi.Router) {
+			r.Post("/", h.SetIndexValue())
+			r.Get("/", h.GetIndexValues())
+		})
+		r.Route("/products", func(r chi.Router) {
+			r.Post("/", h.CreateProduct())
+			r.Get("/", h.GetProducts())
+			r.Get("/{id}", h.GetProductById())
+			r.Put("/{id}", h.UpdateProduct())
+			r.Delete("/{id}", h.DeleteProduct())
+		})
+	})
 	return r
 }
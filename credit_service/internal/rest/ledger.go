@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (h *Handler) Repay() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var repayment models.Repayment
+
+		if err := h.decodeJSONFromBody(w, r, &repayment); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &repayment); err != nil {
+			return
+		}
+
+		res, err := h.service.Repay(r.Context(), chi.URLParam(r, "id"), repayment)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) ChargePenalty() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var penalty models.Penalty
+
+		if err := h.decodeJSONFromBody(w, r, &penalty); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &penalty); err != nil {
+			return
+		}
+
+		entry, err := h.service.ChargePenalty(r.Context(), chi.URLParam(r, "id"), penalty)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, entry)
+	}
+}
+
+// GetTrialBalance returns balances of all accounts,the date query param(YYYY-MM-DD) is inclusive and today by default
+func (h *Handler) GetTrialBalance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		asOf := time.Now().UTC()
+		if date := r.URL.Query().Get("date"); date != "" {
+			day, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:invalid 'date',use YYYY-MM-DD:%s", models.ErrInvalidRequest, err))
+				return
+			}
+			asOf = day.AddDate(0, 0, 1)
+		}
+
+		res, err := h.service.GetTrialBalance(r.Context(), asOf)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+// GetAccountStatement reads creditID,currency,from and to from the query,from and to like in analytics
+func (h *Handler) GetAccountStatement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		period, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		filter := models.LedgerFilter{
+			Account:  chi.URLParam(r, "account"),
+			CreditID: r.URL.Query().Get("creditID"),
+			Currency: strings.ToUpper(r.URL.Query().Get("currency")),
+			From:     period.From,
+			To:       period.To,
+		}
+
+		res, err := h.service.GetAccountStatement(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+func (h *Handler) CheckLedger() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		res, err := h.service.CheckLedger(r.Context())
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		if !res.Balanced {
+			render.Status(r, http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/pkg/jwt"
+	"fmt"
+	"github.com/go-chi/chi/middleware"
+	"net/http"
+	"strings"
+)
+
+// RequestMeta passes the user of the access token and the request id to the service layer
+// for the credit history,ownership and approval checks.
+// Staff tokens make internal requests,other tokens are requests of the customer the token belongs to.
+// Identity headers of the client like X-Actor or X-User-ID are never trusted
+func (h *Handler) RequestMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			h.writeError(w, r, fmt.Errorf("%w:use the Authorization header with the Bearer scheme", models.ErrUnauthenticated))
+			return
+		}
+
+		claims, err := jwt.ParseAccessToken(token, h.secretKey)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		actor := claims.Username
+		if actor == "" {
+			actor = fmt.Sprintf("user:%v", claims.UserID)
+		}
+
+		userID := claims.UserID
+		if claims.Role == jwt.RoleStaff {
+			userID = 0 //сотрудник видит все кредиты
+		}
+
+		ctx := service.WithRequestMeta(r.Context(), actor, middleware.GetReqID(r.Context()), userID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// StaffOnly rejects requests of customers,it goes after RequestMeta
+func (h *Handler) StaffOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !service.IsStaff(r.Context()) {
+			h.writeError(w, r, fmt.Errorf("%w:%s %s", models.ErrStaffOnly, r.Method, r.URL.Path))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
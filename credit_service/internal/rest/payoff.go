@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+	"time"
+)
+
+// GetPayoffQuote returns the amount that closes the credit on the date query param(YYYY-MM-DD),today by default
+func (h *Handler) GetPayoffQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		date := time.Now().UTC()
+		if param := r.URL.Query().Get("date"); param != "" {
+			day, err := time.Parse(time.DateOnly, param)
+			if err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:invalid 'date',use YYYY-MM-DD:%s", models.ErrInvalidRequest, err))
+				return
+			}
+			date = day
+		}
+
+		quote, err := h.service.GetPayoffQuote(r.Context(), chi.URLParam(r, "id"), date)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, quote)
+	}
+}
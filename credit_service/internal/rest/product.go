@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+func (h *Handler) CreateProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var product models.Product
+
+		if err := h.decodeJSONFromBody(w, r, &product); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &product); err != nil {
+			return
+		}
+
+		createdProduct, err := h.service.CreateProduct(r.Context(), product)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		response := map[string]models.Product{"Created Product": createdProduct}
+
+		render.JSON(w, r, response)
+	}
+}
+
+func (h *Handler) GetProducts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		products, err := h.service.GetProducts(r.Context())
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, products)
+	}
+}
+
+func (h *Handler) GetProductById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		productID := chi.URLParam(r, "id")
+
+		product, err := h.service.GetProductById(r.Context(), productID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, product)
+	}
+}
+
+func (h *Handler) UpdateProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var product models.Product
+
+		if err := h.decodeJSONFromBody(w, r, &product); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &product); err != nil {
+			return
+		}
+
+		product.ID = chi.URLParam(r, "id")
+
+		updatedProduct, err := h.service.UpdateProduct(r.Context(), product)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		response := map[string]models.Product{"Updated Product": updatedProduct}
+
+		render.JSON(w, r, response)
+	}
+}
+
+func (h *Handler) DeleteProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		productID := chi.URLParam(r, "id")
+
+		if err := h.service.DeleteProduct(r.Context(), productID); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, "deleted successfully")
+	}
+}
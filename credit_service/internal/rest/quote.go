@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+func (h *Handler) Quote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req models.QuoteRequest
+
+		if err := h.decodeJSONFromBody(w, r, &req); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &req); err != nil {
+			return
+		}
+
+		quote, err := h.service.Quote(r.Context(), req)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, quote)
+	}
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+	"time"
+)
+
+func (h *Handler) SetIndexValue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var value models.IndexValue
+
+		if err := h.decodeJSONFromBody(w, r, &value); err != nil {
+			return
+		}
+
+		value.Index = chi.URLParam(r, "index")
+		if err := h.ValidateValues(w, r, &value); err != nil {
+			return
+		}
+
+		if err := h.service.SetIndexValue(r.Context(), value); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, value)
+	}
+}
+
+func (h *Handler) GetIndexValues() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		values, err := h.service.GetIndexValues(r.Context(), chi.URLParam(r, "index"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, values)
+	}
+}
+
+// RunRateResets resets floating rates due by the 'date' query param(YYYY-MM-DD),today by default
+func (h *Handler) RunRateResets() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		date := today
+
+		if param := r.URL.Query().Get("date"); param != "" {
+			day, err := time.Parse(time.DateOnly, param)
+			if err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:invalid 'date',use YYYY-MM-DD:%s", models.ErrInvalidRequest, err))
+				return
+			}
+			if day.After(today) {
+				h.writeError(w, r, fmt.Errorf("%w:'date' can't be in the future", models.ErrInvalidRequest))
+				return
+			}
+			date = day
+		}
+
+		res, err := h.service.RunRateResets(r.Context(), date)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+func (h *Handler) Refinance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req models.RefinanceRequest
+
+		if err := h.decodeJSONFromBody(w, r, &req); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &req); err != nil {
+			return
+		}
+
+		res, err := h.service.Refinance(r.Context(), req)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
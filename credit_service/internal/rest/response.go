@@ -1,15 +1,110 @@
 package rest
 
 import (
-	"fmt"
-	"github.com/go-playground/validator/v10"
+	"bank/credit_service/internal/domain/models"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"net/http"
 )
 
-func ValidationErrors(errs validator.ValidationErrors) error {
-	for _, err := range errs {
-		if err.Tag() == "required" || err.Tag() == "required_if" {
-			return fmt.Errorf("you must fill the '%s' value", err.Field())
+type ErrorResponse struct {
+	Error ErrorBody
+}
+
+type ErrorBody struct {
+	Code      string //машиночитаемый код ошибки
+	Message   string
+	Fields    []models.FieldError `json:",omitempty"`
+	RequestID string              `json:",omitempty"`
+}
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings maps domain errors to http statuses,the first match wins
+var errorMappings = []errorMapping{
+	{models.ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{models.ErrInvalidID, http.StatusBadRequest, "invalid_id"},
+	{models.ErrCreditNotFound, http.StatusNotFound, "credit_not_found"},
+	{models.ErrUserCreditsNotFound, http.StatusNotFound, "user_credits_not_found"},
+	{models.ErrCreditsNotFound, http.StatusNotFound, "credits_not_found"},
+	{models.ErrHistoryNotFound, http.StatusNotFound, "history_not_found"},
+	{models.ErrProductNotFound, http.StatusNotFound, "product_not_found"},
+	{models.ErrProductsNotFound, http.StatusNotFound, "products_not_found"},
+	{models.ErrExchangeRatesNotFound, http.StatusNotFound, "exchange_rates_not_found"},
+	{models.ErrCreditAlreadyExists, http.StatusConflict, "credit_already_exists"},
+	{models.ErrUserNotFound, http.StatusUnprocessableEntity, "user_not_found"},
+	{models.ErrInvalidProduct, http.StatusBadRequest, "invalid_product"},
+	{models.ErrProductMismatch, http.StatusUnprocessableEntity, "product_mismatch"},
+	{models.ErrUnknownScheme, http.StatusBadRequest, "unknown_scheme"},
+	{models.ErrExchangeRateNotFound, http.StatusUnprocessableEntity, "exchange_rate_not_found"},
+	{models.ErrEntryAlreadyPosted, http.StatusConflict, "entry_already_posted"},
+	{models.ErrUnknownAccount, http.StatusNotFound, "unknown_account"},
+	{models.ErrPaymentExceedsDebt, http.StatusUnprocessableEntity, "payment_exceeds_debt"},
+	{models.ErrCreditNotActive, http.StatusConflict, "credit_not_active"},
+	{models.ErrCreditHasPayments, http.StatusConflict, "credit_has_payments"},
+	{models.ErrPayoffQuoteNotFound, http.StatusNotFound, "payoff_quote_not_found"},
+	{models.ErrPayoffQuoteExpired, http.StatusGone, "payoff_quote_expired"},
+	{models.ErrPayoffQuoteOutdated, http.StatusConflict, "payoff_quote_outdated"},
+	{models.ErrPayoffAmountMismatch, http.StatusUnprocessableEntity, "payoff_amount_mismatch"},
+	{models.ErrCreditLineNotFound, http.StatusNotFound, "credit_line_not_found"},
+	{models.ErrCreditLimitExceeded, http.StatusUnprocessableEntity, "credit_limit_exceeded"},
+	{models.ErrCreditLineNotActive, http.StatusConflict, "credit_line_not_active"},
+	{models.ErrCreditLineHasDebt, http.StatusConflict, "credit_line_has_debt"},
+	{models.ErrCreditRestructured, http.StatusConflict, "credit_restructured"},
+	{models.ErrRestructuringsNotFound, http.StatusNotFound, "restructurings_not_found"},
+	{models.ErrIndexValueNotFound, http.StatusUnprocessableEntity, "index_value_not_found"},
+	{models.ErrIndexValuesNotFound, http.StatusNotFound, "index_values_not_found"},
+	{models.ErrCreditNotPending, http.StatusConflict, "credit_not_pending"},
+	{models.ErrApprovalNotAllowed, http.StatusForbidden, "approval_not_allowed"},
+	{models.ErrApprovalRequired, http.StatusUnprocessableEntity, "approval_required"},
+	{models.ErrDocumentNotFound, http.StatusNotFound, "document_not_found"},
+	{models.ErrDocumentsNotFound, http.StatusNotFound, "documents_not_found"},
+	{models.ErrDocumentTampered, http.StatusInternalServerError, "document_tampered"},
+	{models.ErrStatementsNotFound, http.StatusNotFound, "statements_not_found"},
+	{models.ErrStaffOnly, http.StatusForbidden, "staff_only"},
+	{models.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{models.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+}
+
+// writeError is the only place where errors become http responses
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	body := ErrorBody{
+		Code:      "internal_error",
+		Message:   "internal server error",
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	status := http.StatusInternalServerError
+
+	var validationErr *models.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		status = http.StatusBadRequest
+		body.Code = "validation_failed"
+		body.Message = validationErr.Error()
+		body.Fields = validationErr.Fields
+	default:
+		for _, mapping := range errorMappings {
+			if errors.Is(err, mapping.err) {
+				status = mapping.status
+				body.Code = mapping.code
+				body.Message = err.Error()
+				break
+			}
 		}
 	}
-	return nil
+
+	if status == http.StatusInternalServerError {
+		h.logger.Errorf("request %s failed:%s", body.RequestID, err)
+	} else {
+		h.logger.Infof("request %s rejected:%s", body.RequestID, err)
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, ErrorResponse{Error: body})
 }
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+func (h *Handler) GrantPaymentHoliday() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var holiday models.PaymentHoliday
+
+		if err := h.decodeJSONFromBody(w, r, &holiday); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &holiday); err != nil {
+			return
+		}
+
+		restructuring, err := h.service.GrantPaymentHoliday(r.Context(), chi.URLParam(r, "id"), holiday)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, restructuring)
+	}
+}
+
+func (h *Handler) RestructureCredit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req models.RestructureRequest
+
+		if err := h.decodeJSONFromBody(w, r, &req); err != nil {
+			return
+		}
+
+		if err := h.ValidateValues(w, r, &req); err != nil {
+			return
+		}
+
+		restructuring, err := h.service.RestructureCredit(r.Context(), chi.URLParam(r, "id"), req)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, restructuring)
+	}
+}
+
+// GetRestructurings lists the schedule changes of the credit with the schedules before them
+func (h *Handler) GetRestructurings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		restructurings, err := h.service.GetRestructurings(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, restructurings)
+	}
+}
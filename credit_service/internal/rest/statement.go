@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"bank/credit_service/internal/document"
+	"bank/credit_service/internal/domain/models"
+	"bytes"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+	"time"
+)
+
+// GetCreditStatement writes the statement of the credit for the from and to query params(YYYY-MM-DD,to is inclusive)
+// in the 'format' query param:json(default),csv or pdf
+func (h *Handler) GetCreditStatement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		period, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		statement, err := h.service.GetCreditStatement(r.Context(), chi.URLParam(r, "id"), period.From, period.To)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		h.writeStatements(w, r, statement, []models.CreditStatement{statement})
+	}
+}
+
+// GetUserStatement writes the statements of every credit of the user,the query params are like in GetCreditStatement
+func (h *Handler) GetUserStatement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		userID, err := userIDFromURL(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		period, err := analyticsFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		statement, err := h.service.GetUserStatement(r.Context(), userID, period.From, period.To)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		h.writeStatements(w, r, statement, statement.Credits)
+	}
+}
+
+func (h *Handler) GetMonthlyStatements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		statements, err := h.service.GetMonthlyStatements(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, statements)
+	}
+}
+
+// RunMonthlyStatements makes the statements of the 'month' query param(YYYY-MM),the previous month by default
+func (h *Handler) RunMonthlyStatements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		now := time.Now().UTC()
+		month := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC) //AddDate(0,-1,0) 31-го числа может остаться в том же месяце
+
+		if param := r.URL.Query().Get("month"); param != "" {
+			day, err := time.Parse("2006-01", param)
+			if err != nil {
+				h.writeError(w, r, fmt.Errorf("%w:invalid 'month',use YYYY-MM:%s", models.ErrInvalidRequest, err))
+				return
+			}
+			month = day
+		}
+
+		res, err := h.service.RunMonthlyStatements(r.Context(), month)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, res)
+	}
+}
+
+// writeStatements writes res as json or the statements as csv or pdf
+func (h *Handler) writeStatements(w http.ResponseWriter, r *http.Request, res any, statements []models.CreditStatement) {
+	var buf bytes.Buffer
+	var contentType string
+	var err error
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", models.StatementJSON:
+		render.JSON(w, r, res)
+		return
+	case models.StatementCSV:
+		contentType = "text/csv; charset=utf-8"
+		err = document.WriteStatementsCSV(&buf, statements)
+	case models.StatementPDF:
+		contentType = "application/pdf"
+		err = document.WriteStatementsPDF(&buf, statements)
+	default:
+		h.writeError(w, r, fmt.Errorf("%w:unknown format %q,use json,csv or pdf", models.ErrInvalidRequest, format))
+		return
+	}
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		h.logger.Errorf("failed to write statement:%s", err)
+	}
+}
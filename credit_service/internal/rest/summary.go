@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"net/http"
+	"strings"
+)
+
+func (h *Handler) GetSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		summary, err := h.service.GetSummary(r.Context(), 0)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, summary)
+	}
+}
+
+func (h *Handler) GetUserSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		userID, err := userIDFromURL(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		summary, err := h.service.GetSummary(r.Context(), userID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, summary)
+	}
+}
+
+func (h *Handler) GetExchangeRateHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		currency := strings.ToUpper(chi.URLParam(r, "currency"))
+
+		rates, err := h.service.GetExchangeRateHistory(r.Context(), currency)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, rates)
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// interestBearing is what earns daily interest in the ledger,a credit or a credit line
+type interestBearing struct {
+	id       string
+	currency string
+	start    time.Time //первый день начисления
+	rate     float64   //годовая % ставка
+	rates    []models.RatePeriod
+}
+
+func creditInterest(credit models.Credit) interestBearing {
+	return interestBearing{id: credit.ID, currency: credit.Currency, start: credit.IssuedAt, rate: credit.AnnualInterestRate, rates: credit.RateHistory}
+}
+
+func creditLineInterest(line models.CreditLine) interestBearing {
+	return interestBearing{id: line.ID, currency: line.Currency, start: line.OpenedAt, rate: line.AnnualInterestRate}
+}
+
+// rateOn is the rate in effect on the day,days before a rate change keep the rate they had
+func (a interestBearing) rateOn(day time.Time) float64 {
+	for _, period := range a.rates {
+		if day.Before(truncateDay(period.Until)) {
+			return period.AnnualInterestRate
+		}
+	}
+	return a.rate
+}
+
+// RunAccrual accrues daily interest of every active credit and credit line for all days up to through(inclusive).
+// Days that were missed,for example during downtime,are backfilled,days already accrued are skipped
+func (s *Service) RunAccrual(ctx context.Context, through time.Time) (models.AccrualReport, error) {
+	s.logger.Info("received run accrual req")
+
+	through = truncateDay(through)
+
+	if _, err := DayCountFraction(s.cfg.Accrual.DayCount, through, through); err != nil {
+		s.logger.Errorf("failed to run accrual:%s", err)
+		return models.AccrualReport{}, err
+	}
+
+	credits, err := s.storage.GetActiveCredits(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get active credits:%s", err)
+		return models.AccrualReport{}, err
+	}
+
+	lines, err := s.storage.GetActiveCreditLines(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get active credit lines:%s", err)
+		return models.AccrualReport{}, err
+	}
+
+	report := models.AccrualReport{
+		Through:     through.Format(time.DateOnly),
+		Credits:     len(credits),
+		CreditLines: len(lines),
+		Interest:    make(map[string]int),
+	}
+
+	accounts := make([]interestBearing, 0, len(credits)+len(lines))
+	for _, credit := range credits {
+		accounts = append(accounts, creditInterest(credit))
+	}
+	for _, line := range lines {
+		accounts = append(accounts, creditLineInterest(line))
+	}
+
+	for _, account := range accounts {
+		posted, interest, err := s.accrue(ctx, account, through)
+		if err != nil {
+			s.logger.Errorf("failed to accrue interest of %s:%s", account.id, err)
+			report.Failed = append(report.Failed, account.id)
+		}
+
+		report.PostedEntries += posted
+		report.Interest[account.currency] += interest
+	}
+
+	s.logger.Infof("accrual through %s posted %v entries", report.Through, report.PostedEntries)
+
+	return report, nil
+}
+
+// accrue posts the interest of the days that aren't accrued yet
+func (s *Service) accrue(ctx context.Context, account interestBearing, through time.Time) (posted, interest int, err error) {
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: account.id})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	accruals, err := pendingAccruals(account, entries, through, s.cfg.Accrual.DayCount)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, accrual := range accruals {
+		err = s.postAccrual(ctx, account, accrual.Day, accrual.Amount)
+		if errors.Is(err, models.ErrEntryAlreadyPosted) { //начислил параллельный запуск
+			continue
+		}
+		if err != nil {
+			return posted, interest, err
+		}
+
+		posted++
+		interest += accrual.Amount
+	}
+
+	return posted, interest, nil
+}
+
+// DayAccrual is the interest of one day that isn't posted yet
+type DayAccrual struct {
+	Day    time.Time
+	Amount int
+}
+
+// CreditAccruals is the interest of the credit for the days up to through(inclusive) that the entries don't accrue yet
+func CreditAccruals(credit models.Credit, entries []models.JournalEntry, through time.Time, dayCount string) ([]DayAccrual, error) {
+	return pendingAccruals(creditInterest(credit), entries, through, dayCount)
+}
+
+// pendingAccruals walks the days from the start,each day earns interest on the principal at the end of the day
+// at the rate of that day.Interest is rounded on the running total,so rounding of single days doesn't add up
+func pendingAccruals(account interestBearing, entries []models.JournalEntry, through time.Time, dayCount string) ([]DayAccrual, error) {
+	if account.start.IsZero() {
+		return nil, nil //кредиты до учета даты выдачи
+	}
+
+	loanChanges := make(map[time.Time]int) //изменения основного долга по дням
+	accrued := make(map[time.Time]int)     //уже начисленные дни
+
+	for _, entry := range entries {
+		day := truncateDay(entry.ValueDate)
+		for _, line := range entry.Lines {
+			if line.CreditID != account.id {
+				continue
+			}
+			switch {
+			case line.Account == models.AccountLoan:
+				loanChanges[day] += line.Debit - line.Credit
+			case entry.Type == models.EntryAccrual && line.Account == models.AccountInterestReceivable:
+				accrued[day] += line.Debit
+			}
+		}
+	}
+
+	var accruals []DayAccrual
+	var principal, accruedTotal int
+	var exact float64
+
+	for day := truncateDay(account.start); !day.After(truncateDay(through)); day = day.AddDate(0, 0, 1) {
+		principal += loanChanges[day]
+
+		fraction, err := DayCountFraction(dayCount, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+
+		exact += float64(principal) * account.rateOn(day) / 100 * fraction
+
+		if amount, ok := accrued[day]; ok {
+			accruedTotal += amount
+			continue
+		}
+
+		amount := int(math.Round(exact)) - accruedTotal
+		if amount <= 0 {
+			continue
+		}
+
+		accruals = append(accruals, DayAccrual{Day: day, Amount: amount})
+		accruedTotal += amount
+	}
+
+	return accruals, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (s *Service) OutstandingByCurrency(ctx context.Context, filter models.AnalyticsFilter) ([]models.OutstandingByCurrency, error) {
+	s.logger.Info("received outstanding by currency req")
+
+	res, err := s.storage.OutstandingByCurrency(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get outstanding by currency:%s", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Service) WeightedAverageRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.WeightedAverageRate, error) {
+	s.logger.Info("received weighted average rate req")
+
+	res, err := s.storage.WeightedAverageRate(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get weighted average rate:%s", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Service) IssuedByMonth(ctx context.Context, filter models.AnalyticsFilter) ([]models.IssuedByMonth, error) {
+	s.logger.Info("received issued by month req")
+
+	res, err := s.storage.IssuedByMonth(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get issued by month:%s", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Service) MaturityProfile(ctx context.Context, filter models.AnalyticsFilter) ([]models.MaturityBucket, error) {
+	s.logger.Info("received maturity profile req")
+
+	res, err := s.storage.MaturityProfile(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get maturity profile:%s", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// TopBorrowers converts the exposure of every user to the base currency and returns the biggest ones
+func (s *Service) TopBorrowers(ctx context.Context, filter models.AnalyticsFilter, limit int) ([]models.BorrowerExposure, error) {
+	s.logger.Info("received top borrowers req")
+
+	exposures, err := s.storage.ExposureByUser(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get exposure by user:%s", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	byUser := make(map[int64]*models.BorrowerExposure)
+
+	for _, exposure := range exposures {
+		rate, err := s.exchangeRate(ctx, exposure.Currency, now)
+		if err != nil {
+			s.logger.Errorf("failed to get exchange rate:%s", err)
+			return nil, err
+		}
+
+		borrower, ok := byUser[exposure.UserID]
+		if !ok {
+			borrower = &models.BorrowerExposure{UserID: exposure.UserID, BaseCurrency: s.cfg.Exchange.BaseCurrency}
+			byUser[exposure.UserID] = borrower
+		}
+
+		borrower.Count += exposure.Count
+		borrower.Exposure += float64(exposure.OutstandingPrincipal) * rate.Rate
+	}
+
+	borrowers := make([]models.BorrowerExposure, 0, len(byUser))
+	for _, borrower := range byUser {
+		borrower.Exposure = roundMoney(borrower.Exposure)
+		borrowers = append(borrowers, *borrower)
+	}
+
+	sort.Slice(borrowers, func(i, j int) bool { return borrowers[i].Exposure > borrowers[j].Exposure })
+
+	if len(borrowers) > limit {
+		borrowers = borrowers[:limit]
+	}
+
+	return borrowers, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GetPendingApprovals is the queue of credits waiting for approval,only staff can see it
+func (s *Service) GetPendingApprovals(ctx context.Context) ([]models.Credit, error) {
+	s.logger.Info("received get pending approvals req")
+
+	if requestMetaFromContext(ctx).userID != 0 {
+		return nil, fmt.Errorf("%w:only staff can see the approval queue", models.ErrApprovalNotAllowed)
+	}
+
+	credits, err := s.storage.GetPendingApprovals(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get pending approvals:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("pending approvals got")
+
+	return credits, nil
+}
+
+// ApproveCredit disburses the credit waiting for approval.It's issued on the approval day,so its schedule starts then
+func (s *Service) ApproveCredit(ctx context.Context, creditID string, req models.ApprovalRequest) (models.Credit, error) {
+	s.logger.Info("received approve credit req")
+
+	var credit models.Credit
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		credit, err = s.decide(ctx, creditID, models.DecisionApprove, req.Comment)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to approve credit:%s", err)
+		return models.Credit{}, err
+	}
+
+	s.logger.Info("credit approved")
+
+	return credit, nil
+}
+
+// RejectCredit closes the application of the credit waiting for approval,nothing is disbursed
+func (s *Service) RejectCredit(ctx context.Context, creditID string, req models.ApprovalRequest) (models.Credit, error) {
+	s.logger.Info("received reject credit req")
+
+	if req.Comment == "" {
+		return models.Credit{}, &models.ValidationError{Fields: []models.FieldError{{
+			Field:   "Comment",
+			Message: "you must fill the 'Comment' value to reject the credit",
+		}}}
+	}
+
+	var credit models.Credit
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		credit, err = s.decide(ctx, creditID, models.DecisionReject, req.Comment)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to reject credit:%s", err)
+		return models.Credit{}, err
+	}
+
+	s.logger.Info("credit rejected")
+
+	return credit, nil
+}
+
+// decide records the decision of the approver in the credit.Approved credits are recalculated from today and disbursed
+func (s *Service) decide(ctx context.Context, creditID, decision, comment string) (models.Credit, error) {
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err != nil {
+		return models.Credit{}, err
+	}
+
+	if creditStatus(credit) != models.CreditStatusPending || credit.Approval == nil {
+		return models.Credit{}, fmt.Errorf("%w:%s is %s", models.ErrCreditNotPending, creditID, creditStatus(credit))
+	}
+
+	actor := requestMetaFromContext(ctx).actor
+
+	switch {
+	case requestMetaFromContext(ctx).userID != 0 || actor == "":
+		return models.Credit{}, fmt.Errorf("%w:only staff can decide on credits", models.ErrApprovalNotAllowed)
+	case actor == credit.Approval.RequestedBy:
+		return models.Credit{}, fmt.Errorf("%w:%s created credit %s", models.ErrApprovalNotAllowed, actor, creditID)
+	}
+
+	approval := *credit.Approval
+	approval.Decisions = append(slices.Clone(approval.Decisions), models.ApprovalDecision{
+		Decision:  decision,
+		Actor:     actor,
+		Comment:   comment,
+		DecidedAt: time.Now().UTC(),
+	})
+
+	decided := credit
+	decided.Approval = &approval
+	decided.Status = models.CreditStatusRejected
+	action := models.ActionReject
+
+	if decision == models.DecisionApprove {
+		decided.Status = models.CreditStatusActive
+		action = models.ActionApprove
+
+		decided.IssuedAt = time.Time{} //выдается в день одобрения
+		if err = calculateCredit(&decided); err != nil {
+			return models.Credit{}, err
+		}
+	}
+
+	decided.Outbox = []models.CreditEvent{newStatusChangedEvent(ctx, decided, models.CreditStatusPending)}
+
+	if err = s.storage.UpdateCreditApproval(ctx, decided); err != nil {
+		return models.Credit{}, err
+	}
+	decided.Outbox = nil
+
+	if decision == models.DecisionApprove {
+		if decided, err = s.storage.UpdateCredit(ctx, decided); err != nil {
+			return models.Credit{}, err
+		}
+
+		if err = s.postDisbursement(ctx, "disbursement:"+decided.ID, decided, decided.IssuedAt); err != nil {
+			return models.Credit{}, err
+		}
+
+		if err = s.issueDocuments(ctx, decided); err != nil {
+			return models.Credit{}, err
+		}
+	}
+
+	if err = s.recordHistory(ctx, credit.ID, action, credit, decided); err != nil {
+		return models.Credit{}, err
+	}
+
+	return decided, nil
+}
+
+// approvalThreshold is the amount in the currency above which credits of the product need approval
+func approvalThreshold(product models.Product, currency string, amount int) (int, bool) {
+	threshold, ok := product.ApprovalThresholds[currency]
+	return threshold, ok && amount > threshold
+}
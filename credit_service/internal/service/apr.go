@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"errors"
+	"math"
+)
+
+const (
+	irrIterations = 200
+	irrPrecision  = 1e-10
+)
+
+// EffectiveAnnualRate is the full cost of the credit in percent a year.
+// The borrower gets the amount minus one-off fees and pays the schedule back,
+// the monthly IRR of these cash flows is compounded to a year: (1+irr)^12-1
+func EffectiveAnnualRate(amount, oneOffFee int, schedule []models.Payment) (float64, error) {
+	if len(schedule) == 0 {
+		return 0, errors.New("empty schedule")
+	}
+
+	cashFlows := make([]float64, 0, len(schedule)+1)
+	cashFlows = append(cashFlows, float64(amount-oneOffFee))
+	for _, payment := range schedule {
+		cashFlows = append(cashFlows, -float64(payment.Payment))
+	}
+
+	monthlyRate, err := irr(cashFlows)
+	if err != nil {
+		return 0, err
+	}
+
+	return (math.Pow(1+monthlyRate, 12) - 1) * 100, nil
+}
+
+// irr finds the rate where npv of the cash flows is zero by bisection.
+// npv of a loan(one inflow,then outflows) grows with the rate,so the root is unique
+func irr(cashFlows []float64) (float64, error) {
+	low, high := -0.5, 1.0
+
+	if npv(low, cashFlows)*npv(high, cashFlows) > 0 {
+		return 0, errors.New("failed to find IRR for the cash flows")
+	}
+
+	for i := 0; i < irrIterations; i++ {
+		mid := (low + high) / 2
+		value := npv(mid, cashFlows)
+
+		if math.Abs(value) < irrPrecision || (high-low)/2 < irrPrecision {
+			return mid, nil
+		}
+
+		if value > 0 {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+func npv(rate float64, cashFlows []float64) float64 {
+	var value float64
+	for i, cashFlow := range cashFlows {
+		value += cashFlow / math.Pow(1+rate, float64(i))
+	}
+	return value
+}
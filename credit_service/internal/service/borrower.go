@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// applyBorrowers makes Credit.UserID the only primary borrower and checks the co-borrowers,
+// a credit without borrowers gets the primary one.All violations are returned as *models.ValidationError
+func (s *Service) applyBorrowers(ctx context.Context, credit *models.Credit) error {
+	validationErr := &models.ValidationError{}
+
+	var primary int
+	seen := make(map[int64]bool)
+
+	for i, borrower := range credit.Borrowers {
+		field := fmt.Sprintf("Borrowers[%v].UserID", i)
+
+		switch {
+		case seen[borrower.UserID]:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("borrower %v is listed twice", borrower.UserID),
+			})
+		case borrower.Role == models.BorrowerPrimary && borrower.UserID != credit.UserID:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: "the primary borrower must be the 'UserID' of the credit",
+			})
+		case borrower.Role == models.BorrowerCo && borrower.UserID == credit.UserID:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: "the primary borrower can't be a co-borrower",
+			})
+		case borrower.Role == models.BorrowerCo && !s.storage.IsUserExist(ctx, borrower.UserID):
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("user %v doesn't exist", borrower.UserID),
+			})
+		}
+
+		for _, guarantor := range credit.Guarantors {
+			if guarantor.UserID == borrower.UserID {
+				validationErr.Fields = append(validationErr.Fields, models.FieldError{
+					Field:   field,
+					Message: fmt.Sprintf("borrower %v can't be a guarantor", borrower.UserID),
+				})
+			}
+		}
+
+		if borrower.Role == models.BorrowerPrimary {
+			primary++
+		}
+		seen[borrower.UserID] = true
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+
+	if primary == 0 {
+		credit.Borrowers = append([]models.Borrower{{UserID: credit.UserID, Role: models.BorrowerPrimary}}, credit.Borrowers...)
+	}
+
+	return nil
+}
+
+// checkOwnership hides the credit from users who are not its borrowers,internal requests have no user
+func checkOwnership(ctx context.Context, credit models.Credit) error {
+	userID := requestMetaFromContext(ctx).userID
+	if userID != 0 && !credit.HasBorrower(userID) {
+		return fmt.Errorf("%w:%s", models.ErrCreditNotFound, credit.ID)
+	}
+	return nil
+}
+
+// checkActingUser lets a customer open credits and credit lines only for themselves,staff act for any user
+func checkActingUser(ctx context.Context, userID int64) error {
+	if meta := requestMetaFromContext(ctx); meta.userID != 0 && meta.userID != userID {
+		return fmt.Errorf("%w:user %v for user %v", models.ErrForbidden, meta.userID, userID)
+	}
+	return nil
+}
+
+// checkAffordability compares monthly payments of all credits of the borrowers with their combined income.
+// It's skipped when no borrower has declared income
+func (s *Service) checkAffordability(ctx context.Context, credit models.Credit) error {
+	var income int
+	for _, borrower := range credit.Borrowers {
+		income += borrower.MonthlyIncome
+	}
+
+	if income == 0 || s.cfg.Affordability.MaxDebtToIncome == 0 {
+		return nil
+	}
+
+	payments := float64(credit.MonthlyPayment)
+	counted := map[string]bool{credit.ID: true}
+	now := time.Now()
+
+	creditRate, err := s.exchangeRate(ctx, credit.Currency, now)
+	if err != nil {
+		return err
+	}
+
+	for _, borrower := range credit.Borrowers {
+		credits, err := s.storage.GetCreditsByUserId(ctx, borrower.UserID)
+		if errors.Is(err, models.ErrUserCreditsNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, other := range credits {
+			if counted[other.ID] || creditStatus(other) != models.CreditStatusActive {
+				continue
+			}
+			counted[other.ID] = true
+
+			rate, err := s.exchangeRate(ctx, other.Currency, now)
+			if err != nil {
+				return err
+			}
+
+			payments += float64(other.MonthlyPayment) * rate.Rate / creditRate.Rate //в валюте нового кредита
+		}
+	}
+
+	ratio := math.Round(payments/float64(income)*100*100) / 100
+	if ratio <= s.cfg.Affordability.MaxDebtToIncome {
+		return nil
+	}
+
+	return &models.ValidationError{Fields: []models.FieldError{{
+		Field: "Borrowers",
+		Message: fmt.Sprintf("monthly payments of %v %s are %v%% of the combined income %v,more than %v%%",
+			math.Round(payments), credit.Currency, ratio, income, s.cfg.Affordability.MaxDebtToIncome),
+	}}}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// applyCollateral computes the loan-to-value of the credit and checks it against the product limit,
+// credits of secured products must have collateral.All violations are returned as *models.ValidationError
+func applyCollateral(product models.Product, credit *models.Credit) error {
+	validationErr := &models.ValidationError{}
+
+	var value int
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	for i := range credit.Collateral {
+		item := &credit.Collateral[i]
+		if item.LienStatus == "" {
+			item.LienStatus = models.LienPending
+		}
+		if item.ValuationDate > today { //даты в формате YYYY-MM-DD сравниваются как строки
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   fmt.Sprintf("Collateral[%v].ValuationDate", i),
+				Message: "the 'ValuationDate' value can't be in the future",
+			})
+		}
+		value += item.AppraisedValue
+	}
+
+	credit.LTV = 0
+	if value > 0 {
+		credit.LTV = math.Round(float64(credit.Amount)/float64(value)*100*100) / 100
+	}
+
+	switch {
+	case product.MaxLTV > 0 && len(credit.Collateral) == 0:
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Collateral",
+			Message: fmt.Sprintf("product %s is secured,you must fill the 'Collateral' value", product.Name),
+		})
+	case product.MaxLTV > 0 && credit.LTV > product.MaxLTV:
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Collateral",
+			Message: fmt.Sprintf("loan-to-value %v%% exceeds %v%% of product %s", credit.LTV, product.MaxLTV, product.Name),
+		})
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+
+	return nil
+}
+
+// checkGuarantors checks that guarantors are known users other than the borrower and don't guarantee more than the whole debt
+func (s *Service) checkGuarantors(ctx context.Context, credit models.Credit) error {
+	validationErr := &models.ValidationError{}
+
+	var share float64
+	seen := make(map[int64]bool)
+
+	for i, guarantor := range credit.Guarantors {
+		field := fmt.Sprintf("Guarantors[%v].UserID", i)
+
+		switch {
+		case guarantor.UserID == credit.UserID:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: "the borrower can't be a guarantor",
+			})
+		case seen[guarantor.UserID]:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("guarantor %v is listed twice", guarantor.UserID),
+			})
+		case !s.storage.IsUserExist(ctx, guarantor.UserID):
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("user %v doesn't exist", guarantor.UserID),
+			})
+		}
+
+		seen[guarantor.UserID] = true
+		share += guarantor.Share
+	}
+
+	if share > 100 {
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Guarantors",
+			Message: fmt.Sprintf("guaranteed shares add up to %v%%,more than 100%%", share),
+		})
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+
+	return nil
+}
@@ -1,44 +1,138 @@
 package service
 
 import (
+	"bank/credit_service/internal/config"
 	"bank/credit_service/internal/domain/models"
 	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"math"
 	"time"
 )
 
 type Service struct {
-	logger  *logrus.Logger
-	storage Storage
+	logger    *logrus.Logger
+	storage   Storage
+	rates     RateProvider
+	documents DocumentRenderer
+	cfg       *config.Config
 }
 
-func NewService(logger *logrus.Logger, storage Storage) *Service {
+func NewService(logger *logrus.Logger, storage Storage, rates RateProvider, documents DocumentRenderer, cfg *config.Config) *Service {
 	return &Service{
-		logger:  logger,
-		storage: storage,
+		logger:    logger,
+		storage:   storage,
+		rates:     rates,
+		documents: documents,
+		cfg:       cfg,
 	}
 }
 
 func (s *Service) CreateCredit(ctx context.Context, credit models.Credit) (createdCredit models.Credit, err error) {
 	s.logger.Info("received create credit req")
 
-	credit.MonthlyPayment, credit.DateOfIssue, credit.MaturityDate = CalculateCreditParams(credit.Term, credit.Amount, credit.AnnualInterestRate)
+	if err = s.prepareCredit(ctx, &credit); err != nil {
+		return models.Credit{}, err
+	}
+
+	credit.Status = models.CreditStatusActive
+	if credit.Approval != nil { //выдача ждет решения второго сотрудника
+		credit.Status = models.CreditStatusPending
+		credit.Approval.RequestedBy = requestMetaFromContext(ctx).actor
+		credit.Approval.RequestedAt = time.Now().UTC()
+	}
+	credit.Outbox = []models.CreditEvent{newCreditEvent(ctx, models.EventCreditCreated, credit)}
 
-	createdCredit, err = s.storage.CreateCredit(ctx, credit)
+	//кредит,выдача и документы сохраняются вместе
+	err = s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		createdCredit, err = s.createCredit(ctx, credit)
+		return err
+	})
 	if err != nil {
 		s.logger.Errorf("failed to create credit:%s", err)
 		return models.Credit{}, err
 	}
 
+	if createdCredit.Status == models.CreditStatusPending {
+		s.logger.Info("credit created,waiting for approval")
+		return createdCredit, nil
+	}
+
 	s.logger.Info("credit created")
 
 	return createdCredit, nil
 }
 
+func (s *Service) createCredit(ctx context.Context, credit models.Credit) (models.Credit, error) {
+	createdCredit, err := s.storage.CreateCredit(ctx, credit)
+	if err != nil {
+		return models.Credit{}, err
+	}
+
+	if err = s.recordHistory(ctx, createdCredit.ID, models.ActionCreate, models.Credit{}, createdCredit); err != nil {
+		return models.Credit{}, err
+	}
+
+	if createdCredit.Status == models.CreditStatusPending {
+		return createdCredit, nil
+	}
+
+	if err = s.postDisbursement(ctx, "disbursement:"+createdCredit.ID, createdCredit, createdCredit.IssuedAt); err != nil {
+		s.logger.Errorf("failed to post disbursement:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = s.issueDocuments(ctx, createdCredit); err != nil {
+		s.logger.Errorf("failed to issue documents:%s", err)
+		return models.Credit{}, err
+	}
+
+	return createdCredit, nil
+}
+
+// prepareCredit applies the product and runs every check of a new credit
+func (s *Service) prepareCredit(ctx context.Context, credit *models.Credit) error {
+	if err := checkActingUser(ctx, credit.UserID); err != nil {
+		return err
+	}
+
+	if err := s.applyProduct(ctx, credit); err != nil {
+		s.logger.Errorf("failed to apply product:%s", err)
+		return err
+	}
+
+	if err := s.checkGuarantors(ctx, *credit); err != nil {
+		s.logger.Errorf("invalid guarantors:%s", err)
+		return err
+	}
+
+	if err := s.applyBorrowers(ctx, credit); err != nil {
+		s.logger.Errorf("invalid borrowers:%s", err)
+		return err
+	}
+
+	if err := calculateCredit(credit); err != nil {
+		s.logger.Errorf("failed to calculate credit:%s", err)
+		return err
+	}
+
+	if err := s.checkAffordability(ctx, *credit); err != nil {
+		s.logger.Errorf("credit is not affordable:%s", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *Service) GetCredits(ctx context.Context) ([]models.Credit, error) {
 	s.logger.Info("received get credits req")
 
+	if userID := requestMetaFromContext(ctx).userID; userID != 0 { //клиент видит только свои кредиты
+		return s.GetCreditsByUserId(ctx, userID)
+	}
+
 	credits, err := s.storage.GetCredits(ctx)
 	if err != nil {
 		s.logger.Errorf("failed to get credits:%s", err)
@@ -59,6 +153,11 @@ func (s *Service) GetCreditById(ctx context.Context, id string) (models.Credit,
 		return models.Credit{}, err
 	}
 
+	if err = checkOwnership(ctx, credit); err != nil {
+		s.logger.Errorf("failed to get credit by id:%s", err)
+		return models.Credit{}, err
+	}
+
 	s.logger.Info("credit by id got")
 
 	return credit, err
@@ -67,6 +166,10 @@ func (s *Service) GetCreditById(ctx context.Context, id string) (models.Credit,
 func (s *Service) GetCreditsByUserId(ctx context.Context, userID int64) ([]models.Credit, error) {
 	s.logger.Info("received get credit by userId req")
 
+	if meta := requestMetaFromContext(ctx); meta.userID != 0 && meta.userID != userID { //чужие кредиты не показываются
+		return nil, fmt.Errorf("%w:%v", models.ErrUserCreditsNotFound, userID)
+	}
+
 	credit, err := s.storage.GetCreditsByUserId(ctx, userID)
 	if err != nil {
 		s.logger.Errorf("failed to get credit by userId:%s", err)
@@ -81,7 +184,109 @@ func (s *Service) GetCreditsByUserId(ctx context.Context, userID int64) ([]model
 func (s *Service) UpdateCredit(ctx context.Context, credit models.Credit) (updatedCredit models.Credit, err error) {
 	s.logger.Info("received update credit req")
 
-	credit.MonthlyPayment, credit.DateOfIssue, credit.MaturityDate = CalculateCreditParams(credit.Term, credit.Amount, credit.AnnualInterestRate)
+	//проверка платежей и пересчет в одной транзакции,платеж между ними изменит кредит и вызовет конфликт
+	err = s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedCredit, err = s.updateCredit(ctx, credit)
+		return err
+	})
+	if err != nil {
+		return models.Credit{}, err
+	}
+
+	s.logger.Info("credit updated")
+
+	return updatedCredit, nil
+}
+
+func (s *Service) updateCredit(ctx context.Context, credit models.Credit) (updatedCredit models.Credit, err error) {
+	oldCredit, err := s.storage.GetCreditById(ctx, credit.ID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit before update:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = checkOwnership(ctx, oldCredit); err != nil {
+		s.logger.Errorf("failed to update credit:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = s.checkNoPayments(ctx, oldCredit.ID); err != nil {
+		s.logger.Errorf("failed to update credit:%s", err)
+		return models.Credit{}, err
+	}
+
+	if creditStatus(oldCredit) != models.CreditStatusActive { //не выданный кредит меняется новой заявкой
+		return models.Credit{}, fmt.Errorf("%w:%s is %s", models.ErrCreditNotActive, oldCredit.ID, creditStatus(oldCredit))
+	}
+
+	if oldCredit.Schedule != nil { //пересчет по условиям потерял бы график реструктуризации
+		return models.Credit{}, fmt.Errorf("%w:%s,change it with a new restructuring", models.ErrCreditRestructured, oldCredit.ID)
+	}
+
+	credit.ProductID = oldCredit.ProductID
+	credit.UserID = oldCredit.UserID
+
+	if credit.Collateral == nil {
+		credit.Collateral = oldCredit.Collateral
+	}
+	if credit.Guarantors == nil {
+		credit.Guarantors = oldCredit.Guarantors
+	}
+	if credit.Borrowers == nil {
+		credit.Borrowers = oldCredit.Borrowers
+	}
+
+	if credit.ProductID != "" {
+		if err = s.applyProduct(ctx, &credit); err != nil {
+			s.logger.Errorf("failed to apply product:%s", err)
+			return models.Credit{}, err
+		}
+	} else {
+		credit.AnnualInterestRate = oldCredit.AnnualInterestRate //кредиты,выданные до каталога продуктов
+		credit.Fees = oldCredit.Fees
+
+		if err = applyCollateral(models.Product{}, &credit); err != nil {
+			s.logger.Errorf("invalid collateral:%s", err)
+			return models.Credit{}, err
+		}
+	}
+
+	//одобрение покрывает изменения в пределах одобренной суммы
+	if credit.Approval != nil && (oldCredit.Approval == nil || credit.Amount > oldCredit.Amount) {
+		return models.Credit{}, fmt.Errorf("%w:amount %v is above the threshold %v,create a new credit for approval",
+			models.ErrApprovalRequired, credit.Amount, credit.Approval.Threshold)
+	}
+	credit.Approval = oldCredit.Approval
+
+	if err = s.checkGuarantors(ctx, credit); err != nil {
+		s.logger.Errorf("invalid guarantors:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = s.applyBorrowers(ctx, &credit); err != nil {
+		s.logger.Errorf("invalid borrowers:%s", err)
+		return models.Credit{}, err
+	}
+
+	if credit.Scheme == "" {
+		credit.Scheme = oldCredit.Scheme
+	}
+
+	credit.IssuedAt = oldCredit.IssuedAt
+	credit.Status = oldCredit.Status
+
+	if err = calculateCredit(&credit); err != nil {
+		s.logger.Errorf("failed to calculate credit:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = s.checkAffordability(ctx, credit); err != nil {
+		s.logger.Errorf("credit is not affordable:%s", err)
+		return models.Credit{}, err
+	}
+
+	credit.Outbox = []models.CreditEvent{newCreditEvent(ctx, models.EventCreditUpdated, credit)}
 
 	updatedCredit, err = s.storage.UpdateCredit(ctx, credit)
 	if err != nil {
@@ -89,7 +294,21 @@ func (s *Service) UpdateCredit(ctx context.Context, credit models.Credit) (updat
 		return models.Credit{}, err
 	}
 
-	s.logger.Info("credit updated")
+	if err = s.recordHistory(ctx, updatedCredit.ID, models.ActionUpdate, oldCredit, updatedCredit); err != nil {
+		return models.Credit{}, err
+	}
+
+	//старая выдача сторнируется и проводится заново на новых условиях
+	revision := primitive.NewObjectID().Hex()
+	if err = s.reverseEntries(ctx, "reversal:"+updatedCredit.ID+":"+revision, oldCredit, models.EntryDisbursement, models.EntryReversal); err != nil {
+		s.logger.Errorf("failed to reverse disbursement:%s", err)
+		return models.Credit{}, err
+	}
+
+	if err = s.postDisbursement(ctx, "disbursement:"+updatedCredit.ID+":"+revision, updatedCredit, time.Now().UTC()); err != nil {
+		s.logger.Errorf("failed to post disbursement:%s", err)
+		return models.Credit{}, err
+	}
 
 	return updatedCredit, err
 }
@@ -97,8 +316,10 @@ func (s *Service) UpdateCredit(ctx context.Context, credit models.Credit) (updat
 func (s *Service) DeleteCredit(ctx context.Context, id string) error {
 	s.logger.Info("received delete credit req")
 
-	if err := s.storage.DeleteCredit(ctx, id); err != nil {
-		s.logger.Errorf("failed to delete credit:%s", err)
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.deleteCredit(ctx, id)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -107,10 +328,91 @@ func (s *Service) DeleteCredit(ctx context.Context, id string) error {
 	return nil
 }
 
-func CalculateCreditParams(term, amount int, annualInterestRate float64) (monthlyPayment int, dateOfIssue, maturityDate string) {
+func (s *Service) deleteCredit(ctx context.Context, id string) error {
+	oldCredit, err := s.storage.GetCreditById(ctx, id)
+	if err != nil {
+		s.logger.Errorf("failed to get credit before delete:%s", err)
+		return err
+	}
+
+	if err = checkOwnership(ctx, oldCredit); err != nil {
+		s.logger.Errorf("failed to delete credit:%s", err)
+		return err
+	}
+
+	if err = s.checkNoPayments(ctx, id); err != nil {
+		s.logger.Errorf("failed to delete credit:%s", err)
+		return err
+	}
+
+	deletedAt := time.Now().UTC()
+
+	deletedCredit := oldCredit
+	deletedCredit.DeletedAt = &deletedAt
+	deletedCredit.Status = models.CreditStatusCancelled
+	deletedCredit.Outbox = []models.CreditEvent{newStatusChangedEvent(ctx, deletedCredit, creditStatus(oldCredit))}
+
+	if err = s.storage.DeleteCredit(ctx, deletedCredit); err != nil {
+		s.logger.Errorf("failed to delete credit:%s", err)
+		return err
+	}
+
+	if err = s.recordHistory(ctx, id, models.ActionDelete, oldCredit, deletedCredit); err != nil {
+		return err
+	}
+
+	//отмененный кредит не оставляет следов на счетах
+	if err = s.reverseEntries(ctx, "reversal:"+id, oldCredit, models.EntryDisbursement, models.EntryReversal, models.EntryAccrual, models.EntryPenalty, models.EntryCapitalization); err != nil {
+		s.logger.Errorf("failed to reverse credit entries:%s", err)
+		return err
+	}
+
+	return nil
+}
+
+// calculateCredit fills the payment,dates and APR of the credit from its amount,term,rate and fees
+func calculateCredit(credit *models.Credit) error {
+	if credit.Scheme == "" {
+		credit.Scheme = models.SchemeAnnuity
+	}
+
+	if credit.IssuedAt.IsZero() {
+		credit.IssuedAt = time.Now().UTC()
+	}
+
+	_, credit.DateOfIssue, credit.MaturityDate = CalculateCreditParams(credit.Term, credit.Amount, credit.AnnualInterestRate, credit.InitialPeriod)
+	credit.MaturesAt = credit.IssuedAt.AddDate(0, credit.Term, 0)
+	credit.OutstandingPrincipal = credit.Amount
+
+	credit.NextRateReset = nil
+	if credit.RateIndex != "" {
+		next := nextRateReset(credit.IssuedAt, credit.RateResetMonths, credit.IssuedAt)
+		credit.NextRateReset = &next
+	}
+
+	schedule, err := BuildSchedule(credit.Amount, credit.Term, credit.AnnualInterestRate, credit.Scheme, credit.Fees, credit.IssuedAt, credit.InitialPeriod)
+	if err != nil {
+		return err
+	}
+
+	first := schedule[credit.InitialPeriod.Months()] //первый платеж с погашением основного долга
+	credit.MonthlyPayment = first.Principal + first.Interest
+
+	credit.APR, err = EffectiveAnnualRate(credit.Amount, oneOffFees(credit.Amount, credit.Fees), schedule)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CalculateCreditParams returns the annuity payment after the initial period and the issue and maturity dates.
+// The initial period is part of the term,grace months grow the principal by their interest
+func CalculateCreditParams(term, amount int, annualInterestRate float64, initial models.InitialPeriod) (monthlyPayment int, dateOfIssue, maturityDate string) {
 	monthlyInterestRate := annualInterestRate / 100 / 12 //месячная % ставка
-	numerator := monthlyInterestRate * float64(amount)
-	denominator := 1 - math.Pow(1+monthlyInterestRate, -float64(term))
+	principal := float64(amount) * math.Pow(1+monthlyInterestRate, float64(initial.GraceMonths))
+	numerator := monthlyInterestRate * principal
+	denominator := 1 - math.Pow(1+monthlyInterestRate, -float64(term-initial.Months()))
 	monthlyPayment = int(numerator / denominator) //формула аннуитетного платежа
 	dateOfIssue = time.Now().Format("1 January 2024")
 	maturityDate = time.Now().AddDate(0, term, 0).Format("1 January 2024")
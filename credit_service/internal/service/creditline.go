@@ -0,0 +1,361 @@
+package service
+
+import (
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"time"
+)
+
+func (s *Service) CreateCreditLine(ctx context.Context, line models.CreditLine) (models.CreditLine, error) {
+	s.logger.Info("received create credit line req")
+
+	if err := checkActingUser(ctx, line.UserID); err != nil {
+		return models.CreditLine{}, err
+	}
+
+	line.OpenedAt = time.Now().UTC()
+	line.Status = models.CreditLineStatusActive
+	line.Drawn = 0
+	line.ClosedAt = nil
+	if line.BillingDay == 0 {
+		line.BillingDay = min(line.OpenedAt.Day(), 28) //в каждом месяце есть 28-е число
+	}
+
+	createdLine, err := s.storage.CreateCreditLine(ctx, line)
+	if err != nil {
+		s.logger.Errorf("failed to create credit line:%s", err)
+		return models.CreditLine{}, err
+	}
+
+	createdLine.Available = createdLine.Limit
+
+	s.logger.Info("credit line created")
+
+	return createdLine, nil
+}
+
+func (s *Service) GetCreditLineById(ctx context.Context, id string) (models.CreditLine, error) {
+	s.logger.Info("received get credit line by id req")
+
+	line, err := s.storage.GetCreditLineById(ctx, id)
+	if err == nil {
+		err = checkLineOwnership(ctx, line)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get credit line by id:%s", err)
+		return models.CreditLine{}, err
+	}
+
+	line.Available = line.Limit - line.Drawn
+
+	s.logger.Info("credit line by id got")
+
+	return line, nil
+}
+
+// Drawdown reserves the amount within the limit first and then posts it,the reservation is released if posting fails
+func (s *Service) Drawdown(ctx context.Context, lineID string, drawdown models.Drawdown) (models.DrawdownResult, error) {
+	s.logger.Info("received drawdown req")
+
+	line, err := s.activeCreditLine(ctx, lineID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit line for drawdown:%s", err)
+		return models.DrawdownResult{}, err
+	}
+
+	if drawdown.DrawdownID == "" {
+		drawdown.DrawdownID = primitive.NewObjectID().Hex()
+	}
+
+	if err = s.storage.ReserveDrawdown(ctx, line.ID, drawdown.Amount); err != nil {
+		s.logger.Errorf("failed to reserve drawdown:%s", err)
+		return models.DrawdownResult{}, err
+	}
+
+	entry := models.JournalEntry{
+		ID:          "drawdown:" + drawdown.DrawdownID,
+		Type:        models.EntryDrawdown,
+		CreditID:    line.ID,
+		Currency:    line.Currency,
+		ValueDate:   time.Now().UTC(),
+		Description: fmt.Sprintf("drawdown of %v %s", drawdown.Amount, line.Currency),
+		Lines: creditLines(line.ID,
+			models.EntryLine{Account: models.AccountLoan, Debit: drawdown.Amount},
+			models.EntryLine{Account: models.AccountCash, Credit: drawdown.Amount},
+		),
+	}
+
+	if err = s.postEntry(ctx, entry); err != nil {
+		s.logger.Errorf("failed to post drawdown:%s", err)
+		if releaseErr := s.storage.ChangeDrawn(ctx, line.ID, -drawdown.Amount); releaseErr != nil {
+			s.logger.Errorf("failed to release drawdown reservation:%s", releaseErr)
+		}
+		return models.DrawdownResult{}, err
+	}
+
+	line, err = s.GetCreditLineById(ctx, line.ID)
+	if err != nil {
+		return models.DrawdownResult{}, err
+	}
+
+	s.logger.Info("drawdown posted")
+
+	return models.DrawdownResult{EntryID: entry.ID, Drawn: line.Drawn, Available: line.Available}, nil
+}
+
+// RepayCreditLine allocates the payment to accrued interest,then principal.The repaid principal is available again.
+// The debt check,the posting and the limit change are done in one transaction,so parallel payments can't overpay the line
+func (s *Service) RepayCreditLine(ctx context.Context, lineID string, repayment models.Repayment) (models.RepaymentResult, error) {
+	s.logger.Info("received credit line repayment req")
+
+	if repayment.QuoteID != "" {
+		return models.RepaymentResult{}, fmt.Errorf("%w:credit lines have no payoff quotes", models.ErrInvalidRequest)
+	}
+
+	valueDate := time.Now().UTC()
+	if repayment.Date != "" {
+		var err error
+		if valueDate, err = time.Parse(time.DateOnly, repayment.Date); err != nil {
+			return models.RepaymentResult{}, fmt.Errorf("%w:invalid date,use YYYY-MM-DD:%s", models.ErrInvalidRequest, err)
+		}
+	}
+
+	if repayment.PaymentID == "" {
+		repayment.PaymentID = primitive.NewObjectID().Hex() //до транзакции,чтобы повтор не создал второй платеж
+	}
+
+	var result models.RepaymentResult
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.repayCreditLine(ctx, lineID, repayment, valueDate)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to repay credit line:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	s.logger.Info("credit line repayment posted")
+
+	return result, nil
+}
+
+func (s *Service) repayCreditLine(ctx context.Context, lineID string, repayment models.Repayment, valueDate time.Time) (models.RepaymentResult, error) {
+	line, err := s.activeCreditLine(ctx, lineID)
+	if err != nil {
+		return models.RepaymentResult{}, err
+	}
+
+	balances, err := s.storage.GetCreditBalances(ctx, line.ID)
+	if err != nil {
+		return models.RepaymentResult{}, err
+	}
+
+	debt := balances[models.AccountInterestReceivable] + balances[models.AccountLoan]
+	if repayment.Amount > debt {
+		return models.RepaymentResult{}, fmt.Errorf("%w:paid %v,owed %v", models.ErrPaymentExceedsDebt, repayment.Amount, debt)
+	}
+
+	rest := repayment.Amount
+	result := models.RepaymentResult{EntryID: "repayment:" + repayment.PaymentID, Status: line.Status}
+	result.Interest, rest = allocate(rest, balances[models.AccountInterestReceivable])
+	result.Principal, _ = allocate(rest, balances[models.AccountLoan])
+
+	entry := models.JournalEntry{
+		ID:          result.EntryID,
+		Type:        models.EntryRepayment,
+		CreditID:    line.ID,
+		Currency:    line.Currency,
+		ValueDate:   valueDate,
+		Description: fmt.Sprintf("repayment %s", repayment.PaymentID),
+		Lines: creditLines(line.ID,
+			models.EntryLine{Account: models.AccountCash, Debit: repayment.Amount},
+			models.EntryLine{Account: models.AccountInterestReceivable, Credit: result.Interest},
+			models.EntryLine{Account: models.AccountLoan, Credit: result.Principal},
+		),
+	}
+
+	if err = s.postEntry(ctx, entry); err != nil {
+		return models.RepaymentResult{}, err
+	}
+
+	//меняет версию линии даже при нулевом долге,параллельная транзакция получит write conflict и перечитает балансы
+	if err = s.storage.ChangeDrawn(ctx, line.ID, -result.Principal); err != nil {
+		return models.RepaymentResult{}, err
+	}
+
+	result.OutstandingPrincipal = balances[models.AccountLoan] - result.Principal
+
+	return result, nil
+}
+
+// CloseCreditLine closes the line when nothing is owed on it,interest included
+func (s *Service) CloseCreditLine(ctx context.Context, lineID string) error {
+	s.logger.Info("received close credit line req")
+
+	line, err := s.activeCreditLine(ctx, lineID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit line for closing:%s", err)
+		return err
+	}
+
+	balances, err := s.storage.GetCreditBalances(ctx, line.ID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit line balances:%s", err)
+		return err
+	}
+
+	if debt := balances[models.AccountInterestReceivable] + balances[models.AccountLoan]; debt != 0 {
+		return fmt.Errorf("%w:%v %s owed", models.ErrCreditLineHasDebt, debt, line.Currency)
+	}
+
+	if err = s.storage.CloseCreditLine(ctx, line.ID, time.Now().UTC()); err != nil {
+		s.logger.Errorf("failed to close credit line:%s", err)
+		return err
+	}
+
+	s.logger.Info("credit line closed")
+
+	return nil
+}
+
+func (s *Service) GetCreditLineStatements(ctx context.Context, lineID string) ([]models.CreditLineStatement, error) {
+	s.logger.Info("received get credit line statements req")
+
+	line, err := s.storage.GetCreditLineById(ctx, lineID)
+	if err == nil {
+		err = checkLineOwnership(ctx, line)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get credit line:%s", err)
+		return nil, err
+	}
+
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: line.ID})
+	if err != nil {
+		s.logger.Errorf("failed to get credit line entries:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("credit line statements got")
+
+	return BillingStatements(line, entries, time.Now().UTC(), s.cfg.CreditLine), nil
+}
+
+// BillingStatements splits the credit line postings into billing cycles that ended by asOf.
+// The minimum payment is a part of the principal,but not less than the floor,plus the interest of the cycle
+func BillingStatements(line models.CreditLine, entries []models.JournalEntry, asOf time.Time, rules config.CreditLine) []models.CreditLineStatement {
+	asOf = truncateDay(asOf)
+	from := truncateDay(line.OpenedAt)
+
+	billingDay := line.BillingDay
+	if billingDay == 0 {
+		billingDay = min(from.Day(), 28)
+	}
+
+	var statements []models.CreditLineStatement
+	var balance, principal int
+
+	for to := nextBillingDate(from, billingDay); !to.After(asOf); from, to = to, to.AddDate(0, 1, 0) {
+		statement := models.CreditLineStatement{
+			From:           from,
+			To:             to,
+			OpeningBalance: balance,
+			DueDate:        to.AddDate(0, 0, rules.PaymentDueDays),
+		}
+
+		for _, entry := range entries {
+			day := truncateDay(entry.ValueDate)
+			loan, interest := lineChanges(entry, line.ID)
+
+			if entry.Type == models.EntryRepayment && !day.Before(to) && !day.After(statement.DueDate) {
+				statement.PaidByDueDate -= loan + interest
+			}
+
+			if day.Before(from) || !day.Before(to) {
+				continue
+			}
+
+			switch entry.Type {
+			case models.EntryDrawdown:
+				statement.Drawdowns += loan
+			case models.EntryRepayment:
+				statement.Repayments -= loan + interest
+			case models.EntryAccrual:
+				statement.InterestCharged += interest
+			}
+
+			principal += loan
+			balance += loan + interest
+		}
+
+		statement.ClosingBalance = balance
+		statement.ClosingPrincipal = principal
+
+		if balance > 0 {
+			minimum := max(rules.MinPaymentAmount, int(math.Round(float64(principal)*rules.MinPaymentPercent/100)))
+			statement.MinimumPayment = min(minimum+statement.InterestCharged, balance)
+		}
+
+		statement.Overdue = asOf.After(statement.DueDate) && statement.PaidByDueDate < statement.MinimumPayment
+
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
+
+// nextBillingDate is the first billing day after the date
+func nextBillingDate(date time.Time, billingDay int) time.Time {
+	next := time.Date(date.Year(), date.Month(), billingDay, 0, 0, 0, 0, time.UTC)
+	if !next.After(date) {
+		next = next.AddDate(0, 1, 0)
+	}
+	return next
+}
+
+// lineChanges returns how the entry changes the principal and the interest owed on the credit line
+func lineChanges(entry models.JournalEntry, lineID string) (loan, interest int) {
+	for _, line := range entry.Lines {
+		if line.CreditID != lineID {
+			continue
+		}
+		switch line.Account {
+		case models.AccountLoan:
+			loan += line.Debit - line.Credit
+		case models.AccountInterestReceivable:
+			interest += line.Debit - line.Credit
+		}
+	}
+	return loan, interest
+}
+
+func (s *Service) activeCreditLine(ctx context.Context, lineID string) (models.CreditLine, error) {
+	line, err := s.storage.GetCreditLineById(ctx, lineID)
+	if err != nil {
+		return models.CreditLine{}, err
+	}
+
+	if err = checkLineOwnership(ctx, line); err != nil {
+		return models.CreditLine{}, err
+	}
+
+	if line.Status != models.CreditLineStatusActive {
+		return models.CreditLine{}, fmt.Errorf("%w:%s is %s", models.ErrCreditLineNotActive, lineID, line.Status)
+	}
+
+	return line, nil
+}
+
+// checkLineOwnership hides the credit line from other customers like checkOwnership does for credits
+func checkLineOwnership(ctx context.Context, line models.CreditLine) error {
+	if userID := requestMetaFromContext(ctx).userID; userID != 0 && userID != line.UserID {
+		return fmt.Errorf("%w:%s", models.ErrCreditLineNotFound, line.ID)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"time"
+)
+
+// DayCountFraction is the part of a year between start and end under the day count convention
+func DayCountFraction(convention string, start, end time.Time) (float64, error) {
+	start, end = truncateDay(start), truncateDay(end)
+
+	switch convention {
+	case models.DayCount30360:
+		return float64(days30360(start, end)) / 360, nil
+	case models.DayCountACT365:
+		return end.Sub(start).Hours() / 24 / 365, nil
+	case models.DayCountACTACT:
+		return actAct(start, end), nil
+	default:
+		return 0, fmt.Errorf("%w:%s", models.ErrUnknownDayCount, convention)
+	}
+}
+
+// days30360 counts every month as 30 days(30/360 US,the 31st counts as the 30th)
+func days30360(start, end time.Time) int {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+
+	return 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+}
+
+// actAct splits the period by calendar years,days of leap years are divided by 366(ACT/ACT ISDA)
+func actAct(start, end time.Time) float64 {
+	var fraction float64
+
+	for start.Before(end) {
+		nextYear := time.Date(start.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		periodEnd := end
+		if nextYear.Before(end) {
+			periodEnd = nextYear
+		}
+
+		daysInYear := nextYear.Sub(time.Date(start.Year(), 1, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24
+		fraction += periodEnd.Sub(start).Hours() / 24 / daysInYear
+
+		start = periodEnd
+	}
+
+	return fraction
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
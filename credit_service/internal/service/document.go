@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+func (s *Service) GetDocuments(ctx context.Context, creditID string) ([]models.Document, error) {
+	s.logger.Info("received get documents req")
+
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err == nil {
+		err = checkOwnership(ctx, credit)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get documents:%s", err)
+		return nil, err
+	}
+
+	documents, err := s.storage.GetDocuments(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get documents:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("documents got")
+
+	return documents, nil
+}
+
+// GetDocument returns the document with its content after checking the content against the saved hash
+func (s *Service) GetDocument(ctx context.Context, creditID, documentID string) (models.Document, error) {
+	s.logger.Info("received get document req")
+
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err == nil {
+		err = checkOwnership(ctx, credit)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get document:%s", err)
+		return models.Document{}, err
+	}
+
+	document, err := s.storage.GetDocument(ctx, creditID, documentID)
+	if err != nil {
+		s.logger.Errorf("failed to get document:%s", err)
+		return models.Document{}, err
+	}
+
+	if hash := contentHash(document.Content); hash != document.SHA256 {
+		err = fmt.Errorf("%w:%s has hash %s,%s was saved", models.ErrDocumentTampered, documentID, hash, document.SHA256)
+		s.logger.Errorf("failed to get document:%s", err)
+		return models.Document{}, err
+	}
+
+	s.logger.Info("document got")
+
+	return document, nil
+}
+
+// issueDocuments generates the agreement and the repayment schedule of the issued credit from the latest templates
+func (s *Service) issueDocuments(ctx context.Context, credit models.Credit) error {
+	schedule, err := creditSchedule(credit)
+	if err != nil {
+		return err
+	}
+
+	data := models.DocumentData{
+		Credit:      credit,
+		Schedule:    schedule,
+		OneOffFees:  oneOffFees(credit.Amount, credit.Fees),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, payment := range schedule {
+		data.TotalPayment += payment.Payment
+		data.TotalInterest += payment.Interest
+	}
+
+	for _, docType := range []string{models.DocumentAgreement, models.DocumentSchedule} {
+		content, version, err := s.documents.Render(docType, data)
+		if err != nil {
+			return err
+		}
+
+		document := models.Document{
+			ID:              primitive.NewObjectID().Hex(),
+			CreditID:        credit.ID,
+			Type:            docType,
+			TemplateVersion: version,
+			ContentType:     s.documents.ContentType(),
+			SHA256:          contentHash(content),
+			Size:            len(content),
+			CreatedAt:       data.GeneratedAt,
+			Content:         content,
+		}
+
+		if err = s.storage.SaveDocument(ctx, document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// newCreditEvent builds the event from the state of the credit after the change.
+// The event is saved to the credit outbox by the same storage call that saves the change
+func newCreditEvent(ctx context.Context, eventType string, credit models.Credit) models.CreditEvent {
+	return models.CreditEvent{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		Version:    models.EventSchemaVersion,
+		CreditID:   credit.ID, //пустой при создании,storage подставит id документа
+		UserID:     credit.UserID,
+		Actor:      requestMetaFromContext(ctx).actor,
+		OccurredAt: time.Now().UTC(),
+		Data: models.CreditEventData{
+			ProductID:            credit.ProductID,
+			Amount:               credit.Amount,
+			Currency:             credit.Currency,
+			Term:                 credit.Term,
+			AnnualInterestRate:   credit.AnnualInterestRate,
+			MonthlyPayment:       credit.MonthlyPayment,
+			OutstandingPrincipal: credit.OutstandingPrincipal,
+			MaturesAt:            credit.MaturesAt,
+			Status:               creditStatus(credit),
+			Schedule:             scheduledPayments(credit),
+		},
+	}
+}
+
+// scheduledPayments is the due dates and amounts of the current schedule of the credit,
+// consumers don't have to rebuild it from the terms
+func scheduledPayments(credit models.Credit) []models.ScheduledPayment {
+	schedule, err := creditSchedule(credit)
+	if err != nil {
+		return nil //без графика получатели считают платежи по условиям
+	}
+
+	payments := make([]models.ScheduledPayment, 0, len(schedule))
+	for _, payment := range schedule {
+		dueDate, err := time.Parse(scheduleDateLayout, payment.Date)
+		if err != nil {
+			return nil
+		}
+
+		payments = append(payments, models.ScheduledPayment{Number: payment.Number, DueDate: dueDate, Amount: payment.Payment})
+	}
+
+	return payments
+}
+
+// newStatusChangedEvent is CreditStatusChanged for the credit that moved from previousStatus to its current status
+func newStatusChangedEvent(ctx context.Context, credit models.Credit, previousStatus string) models.CreditEvent {
+	event := newCreditEvent(ctx, models.EventCreditStatusChanged, credit)
+	event.Data.PreviousStatus = previousStatus
+	return event
+}
+
+// creditStatus treats credits created before statuses were introduced as active
+func creditStatus(credit models.Credit) string {
+	if credit.Status == "" {
+		return models.CreditStatusActive
+	}
+	return credit.Status
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+func (s *Service) GetSummary(ctx context.Context, userID int64) (models.Summary, error) {
+	s.logger.Info("received get summary req")
+
+	if meta := requestMetaFromContext(ctx); meta.userID != 0 { //клиент видит только свои итоги
+		if userID != 0 && userID != meta.userID {
+			return models.Summary{}, fmt.Errorf("%w:%v", models.ErrUserCreditsNotFound, userID)
+		}
+		userID = meta.userID
+	}
+
+	totals, err := s.storage.GetTotalsByCurrency(ctx, userID)
+	if err != nil {
+		s.logger.Errorf("failed to get totals by currency:%s", err)
+		return models.Summary{}, err
+	}
+
+	now := time.Now()
+
+	summary := models.Summary{
+		UserID:       userID,
+		BaseCurrency: s.cfg.Exchange.BaseCurrency,
+		Date:         now.Format(dateLayout),
+	}
+
+	for _, total := range totals {
+		rate, err := s.exchangeRate(ctx, total.Currency, now)
+		if err != nil {
+			s.logger.Errorf("failed to get exchange rate:%s", err)
+			return models.Summary{}, err
+		}
+
+		total.Rate = rate.Rate
+		total.RateDate = rate.Date
+		total.AmountInBase = roundMoney(float64(total.Amount) * rate.Rate)
+
+		summary.Totals = append(summary.Totals, total)
+		summary.TotalInBase += total.AmountInBase
+	}
+
+	summary.TotalInBase = roundMoney(summary.TotalInBase)
+
+	s.logger.Info("summary got")
+
+	return summary, nil
+}
+
+func (s *Service) GetExchangeRateHistory(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	s.logger.Info("received get exchange rate history req")
+
+	rates, err := s.storage.GetExchangeRateHistory(ctx, currency, s.cfg.Exchange.BaseCurrency)
+	if err != nil {
+		s.logger.Errorf("failed to get exchange rate history:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("exchange rate history got")
+
+	return rates, nil
+}
+
+// exchangeRate returns the rate of currency to the base currency.Rates got from the provider are cached in mongo,so the history is kept
+func (s *Service) exchangeRate(ctx context.Context, currency string, date time.Time) (models.ExchangeRate, error) {
+	base := s.cfg.Exchange.BaseCurrency
+	asOf := date.Format(dateLayout)
+
+	if currency == base {
+		return models.ExchangeRate{Currency: currency, Base: base, Rate: 1, Date: asOf, AsOf: asOf}, nil
+	}
+
+	if rate, err := s.storage.GetExchangeRate(ctx, currency, base, asOf); err == nil {
+		return rate, nil
+	}
+
+	rate, err := s.rates.Rate(ctx, currency, base, date)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	rate.AsOf = asOf
+
+	if err = s.storage.SaveExchangeRate(ctx, rate); err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	return rate, nil
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// SetIndexValue saves the value of the rate index,credits pick it up at their next rate reset
+func (s *Service) SetIndexValue(ctx context.Context, value models.IndexValue) error {
+	s.logger.Info("received set index value req")
+
+	if err := s.storage.SaveIndexValue(ctx, value); err != nil {
+		s.logger.Errorf("failed to save index value:%s", err)
+		return err
+	}
+
+	s.logger.Info("index value saved")
+
+	return nil
+}
+
+func (s *Service) GetIndexValues(ctx context.Context, index string) ([]models.IndexValue, error) {
+	s.logger.Info("received get index values req")
+
+	values, err := s.storage.GetIndexValues(ctx, index)
+	if err != nil {
+		s.logger.Errorf("failed to get index values:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("index values got")
+
+	return values, nil
+}
+
+// RunRateResets resets the rate of every floating-rate credit whose reset date came by the date.
+// Each credit is reset in its own transaction,so one failure doesn't stop the others
+func (s *Service) RunRateResets(ctx context.Context, date time.Time) (models.RateResetReport, error) {
+	s.logger.Info("received run rate resets req")
+
+	date = truncateDay(date)
+
+	credits, err := s.storage.GetActiveCredits(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get active credits:%s", err)
+		return models.RateResetReport{}, err
+	}
+
+	report := models.RateResetReport{Date: date.Format(time.DateOnly)}
+
+	for _, credit := range credits {
+		if credit.RateIndex == "" || credit.NextRateReset == nil || truncateDay(*credit.NextRateReset).After(date) {
+			continue
+		}
+		report.Credits++
+
+		var reset models.RateReset
+
+		err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			reset, err = s.resetRate(ctx, credit)
+			return err
+		})
+		if err != nil {
+			s.logger.Errorf("failed to reset rate of %s:%s", credit.ID, err)
+			report.Failed = append(report.Failed, credit.ID)
+			continue
+		}
+
+		report.Resets = append(report.Resets, reset)
+	}
+
+	s.logger.Infof("rate resets on %s reset %v credits", report.Date, len(report.Resets))
+
+	return report, nil
+}
+
+// resetRate sets the rate of the credit from the index on its reset date and reprices the payments after it
+func (s *Service) resetRate(ctx context.Context, credit models.Credit) (models.RateReset, error) {
+	resetDate := truncateDay(*credit.NextRateReset)
+
+	rate, index, err := s.floatingRate(ctx, credit.RateIndex, credit.RateMargin, resetDate)
+	if err != nil {
+		return models.RateReset{}, err
+	}
+
+	//проценты до даты пересмотра начисляются по старой ставке
+	if _, _, err = s.accrue(ctx, creditInterest(credit), resetDate.AddDate(0, 0, -1)); err != nil {
+		return models.RateReset{}, err
+	}
+
+	original, err := creditSchedule(credit)
+	if err != nil {
+		return models.RateReset{}, err
+	}
+
+	schedule, err := RepriceSchedule(original, credit.IssuedAt, resetDate, rate, credit.Scheme)
+	if err != nil {
+		return models.RateReset{}, err
+	}
+
+	next := nextRateReset(credit.IssuedAt, credit.RateResetMonths, resetDate)
+
+	reset := credit
+	reset.AnnualInterestRate = rate
+	reset.RateHistory = withRatePeriod(credit, resetDate)
+	reset.NextRateReset = &next
+	reset.Schedule = schedule
+
+	_, remaining := splitSchedule(schedule, resetDate.AddDate(0, 0, 1))
+	for _, payment := range remaining {
+		if payment.Principal > 0 { //первый платеж с погашением основного долга
+			reset.MonthlyPayment = payment.Principal + payment.Interest
+			break
+		}
+	}
+
+	reset.Outbox = []models.CreditEvent{newCreditEvent(ctx, models.EventCreditUpdated, reset)}
+
+	if reset, err = s.storage.UpdateCredit(ctx, reset); err != nil {
+		return models.RateReset{}, err
+	}
+
+	if err = s.recordHistory(ctx, credit.ID, models.ActionRateReset, credit, reset); err != nil {
+		return models.RateReset{}, err
+	}
+
+	return models.RateReset{
+		CreditID:       credit.ID,
+		IndexDate:      index.Date,
+		OldRate:        credit.AnnualInterestRate,
+		NewRate:        rate,
+		MonthlyPayment: reset.MonthlyPayment,
+		NextRateReset:  next,
+	}, nil
+}
+
+// RepriceSchedule keeps the payments due on or before the reset date and the payment holiday that follows them,
+// the payments after them are rebuilt with the new rate from the principal the schedule expects.
+// Interest-only months left stay interest-only
+func RepriceSchedule(schedule []models.Payment, issuedAt, resetDate time.Time, annualInterestRate float64, scheme string) ([]models.Payment, error) {
+	kept, remaining := splitSchedule(schedule, resetDate.AddDate(0, 0, 1))
+	for len(remaining) > 0 && remaining[0].Payment == 0 { //каникулы не прерываются пересмотром ставки
+		kept, remaining = schedule[:len(kept)+1], remaining[1:]
+	}
+
+	if len(remaining) == 0 {
+		return schedule, nil
+	}
+
+	return RescheduleCredit(kept, Reschedule{
+		Principal:          remaining[0].Balance + remaining[0].Principal,
+		Term:               len(remaining),
+		InterestOnlyMonths: interestOnlyMonths(remaining),
+		AnnualInterestRate: annualInterestRate,
+		Scheme:             scheme,
+		MonthlyFee:         remaining[0].Fees,
+		Start:              issuedAt.AddDate(0, len(kept), 0),
+	})
+}
+
+// withRatePeriod closes the current rate of the credit on the day the new rate starts
+func withRatePeriod(credit models.Credit, until time.Time) []models.RatePeriod {
+	return append(slices.Clone(credit.RateHistory), models.RatePeriod{AnnualInterestRate: credit.AnnualInterestRate, Until: until})
+}
+
+// floatingRate is the index value in effect on the date plus the margin
+func (s *Service) floatingRate(ctx context.Context, index string, margin float64, date time.Time) (float64, models.IndexValue, error) {
+	value, err := s.storage.GetIndexValue(ctx, index, date.Format(time.DateOnly))
+	if err != nil {
+		return 0, models.IndexValue{}, err
+	}
+
+	return math.Round((value.Rate+margin)*10000) / 10000, value, nil //без хвостов сложения float
+}
+
+// nextRateReset is the first reset date after the day.Resets are counted from the issue date,so month ends don't drift
+func nextRateReset(issuedAt time.Time, months int, after time.Time) time.Time {
+	months = max(months, 1) //нулевой период зациклил бы поиск
+	next := issuedAt
+	for i := 1; !truncateDay(next).After(truncateDay(after)); i++ {
+		next = issuedAt.AddDate(0, i*months, 0)
+	}
+	return next
+}
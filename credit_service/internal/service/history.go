@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type requestMetaKey struct{}
+
+type requestMeta struct {
+	actor     string
+	requestID string
+	userID    int64 //пользователь,от имени которого запрос,0 - внутренний запрос
+}
+
+// WithRequestMeta puts into ctx who made the request and its id,they are written to the credit history.
+// With a non-zero userID only borrowers of a credit can access it
+func WithRequestMeta(ctx context.Context, actor, requestID string, userID int64) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, requestMeta{actor: actor, requestID: requestID, userID: userID})
+}
+
+func requestMetaFromContext(ctx context.Context) requestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(requestMeta)
+	return meta
+}
+
+// IsStaff reports whether the request is made by staff or internally,not on behalf of a customer
+func IsStaff(ctx context.Context) bool {
+	return requestMetaFromContext(ctx).userID == 0
+}
+
+func (s *Service) GetCreditHistory(ctx context.Context, creditID string) ([]models.CreditHistory, error) {
+	s.logger.Info("received get credit history req")
+
+	if requestMetaFromContext(ctx).userID != 0 { //история удаленных кредитов доступна только внутренним запросам
+		credit, err := s.storage.GetCreditById(ctx, creditID)
+		if err == nil {
+			err = checkOwnership(ctx, credit)
+		}
+		if err != nil {
+			s.logger.Errorf("failed to get credit history:%s", err)
+			return nil, err
+		}
+	}
+
+	history, err := s.storage.GetCreditHistory(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit history:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("credit history got")
+
+	return history, nil
+}
+
+func (s *Service) recordHistory(ctx context.Context, creditID, action string, oldCredit, newCredit models.Credit) error {
+	meta := requestMetaFromContext(ctx)
+
+	changes, err := diffCredits(oldCredit, newCredit)
+	if err != nil {
+		return err
+	}
+
+	record := models.CreditHistory{
+		CreditID:  creditID,
+		Action:    action,
+		Actor:     meta.actor,
+		RequestID: meta.requestID,
+		Timestamp: time.Now().UTC(),
+		Changes:   changes,
+	}
+
+	if err = s.storage.AddCreditHistory(ctx, record); err != nil {
+		s.logger.Errorf("failed to record credit history:%s", err)
+		return err
+	}
+
+	return nil
+}
+
+// diffCredits compares credits field by field using their bson representation,so new fields of models.Credit are tracked automatically
+func diffCredits(oldCredit, newCredit models.Credit) ([]models.FieldChange, error) {
+	oldFields, err := toBsonM(oldCredit)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := toBsonM(newCredit)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{})
+	for k := range oldFields {
+		keys[k] = struct{}{}
+	}
+	for k := range newFields {
+		keys[k] = struct{}{}
+	}
+
+	var changes []models.FieldChange
+
+	for k := range keys {
+		if k == "_id" || k == "operationtype" { //служебные поля
+			continue
+		}
+		if reflect.DeepEqual(oldFields[k], newFields[k]) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{
+			Field:    k,
+			OldValue: oldFields[k],
+			NewValue: newFields[k],
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+func toBsonM(credit models.Credit) (bson.M, error) {
+	credit.Outbox = nil
+
+	data, err := bson.Marshal(credit)
+	if err != nil {
+		return nil, fmt.Errorf("marshal credit failed:%s", err)
+	}
+
+	var fields bson.M
+	if err = bson.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal credit failed:%s", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+)
+
+// ImportCredit creates a credit of a bulk import through CreateCredit.
+// In a dry run it only runs the same checks and returns the calculated credit without saving it
+func (s *Service) ImportCredit(ctx context.Context, credit models.Credit, dryRun bool) (models.Credit, error) {
+	if requestMetaFromContext(ctx).userID != 0 {
+		return models.Credit{}, fmt.Errorf("%w:customers can't import credits", models.ErrStaffOnly)
+	}
+
+	if !dryRun {
+		return s.CreateCredit(ctx, credit)
+	}
+
+	if err := s.prepareCredit(ctx, &credit); err != nil {
+		return models.Credit{}, err
+	}
+
+	if err := s.storage.CheckNewCredit(ctx, credit); err != nil {
+		return models.Credit{}, err
+	}
+
+	return credit, nil
+}
+
+// ExportCredits passes every credit,deleted ones too,to fn,so they can be written without loading them all
+func (s *Service) ExportCredits(ctx context.Context, fn func(models.Credit) error) error {
+	s.logger.Info("received export credits req")
+
+	if requestMetaFromContext(ctx).userID != 0 {
+		return fmt.Errorf("%w:customers can't export credits", models.ErrStaffOnly)
+	}
+
+	if err := s.storage.ExportCredits(ctx, fn); err != nil {
+		s.logger.Errorf("failed to export credits:%s", err)
+		return err
+	}
+
+	s.logger.Info("credits exported")
+
+	return nil
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"slices"
+	"time"
+)
+
+// Repay posts the payment and allocates it to penalties,then accrued interest,then principal.
+// The credit is closed when nothing is owed after the payment.A payment with a payoff quote pays the early payoff fee too
+func (s *Service) Repay(ctx context.Context, creditID string, repayment models.Repayment) (models.RepaymentResult, error) {
+	s.logger.Info("received repayment req")
+
+	var result models.RepaymentResult
+
+	//долг проверяется в той же транзакции,параллельный платеж изменит кредит,получит конфликт и перечитает балансы
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.repay(ctx, creditID, repayment)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to repay credit:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	s.logger.Info("repayment posted")
+
+	return result, nil
+}
+
+func (s *Service) repay(ctx context.Context, creditID string, repayment models.Repayment) (models.RepaymentResult, error) {
+	credit, err := s.activeCredit(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit for repayment:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	valueDate := time.Now().UTC()
+	if repayment.Date != "" {
+		if valueDate, err = time.Parse(time.DateOnly, repayment.Date); err != nil {
+			return models.RepaymentResult{}, fmt.Errorf("%w:invalid date,use YYYY-MM-DD:%s", models.ErrInvalidRequest, err)
+		}
+	}
+
+	var fee int
+
+	if repayment.QuoteID != "" {
+		quote, err := s.usePayoffQuote(ctx, credit, repayment)
+		if err != nil {
+			s.logger.Errorf("failed to use payoff quote:%s", err)
+			return models.RepaymentResult{}, err
+		}
+
+		valueDate, _ = time.Parse(time.DateOnly, quote.Date)
+		fee = quote.Fees
+		if repayment.PaymentID == "" {
+			repayment.PaymentID = "payoff:" + quote.ID //повтор того же досрочного погашения не проводится
+		}
+	}
+
+	balances, err := s.creditBalances(ctx, credit)
+	if err != nil {
+		s.logger.Errorf("failed to get credit balances:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	debt := balances[models.AccountPenaltyReceivable] + fee + balances[models.AccountInterestReceivable] + balances[models.AccountLoan]
+	if repayment.Amount > debt {
+		return models.RepaymentResult{}, fmt.Errorf("%w:paid %v,owed %v", models.ErrPaymentExceedsDebt, repayment.Amount, debt)
+	}
+
+	if repayment.PaymentID == "" {
+		repayment.PaymentID = primitive.NewObjectID().Hex()
+	}
+
+	rest := repayment.Amount
+	result := models.RepaymentResult{EntryID: "repayment:" + repayment.PaymentID}
+	result.Penalty, rest = allocate(rest, balances[models.AccountPenaltyReceivable])
+	result.Fee, rest = allocate(rest, fee)
+	result.Interest, rest = allocate(rest, balances[models.AccountInterestReceivable])
+	result.Principal, _ = allocate(rest, balances[models.AccountLoan])
+
+	entry := models.JournalEntry{
+		ID:          result.EntryID,
+		Type:        models.EntryRepayment,
+		CreditID:    credit.ID,
+		Currency:    credit.Currency,
+		ValueDate:   valueDate,
+		Description: fmt.Sprintf("repayment %s", repayment.PaymentID),
+		Lines: creditLines(credit.ID,
+			models.EntryLine{Account: models.AccountCash, Debit: repayment.Amount},
+			models.EntryLine{Account: models.AccountPenaltyReceivable, Credit: result.Penalty},
+			models.EntryLine{Account: models.AccountFeeIncome, Credit: result.Fee},
+			models.EntryLine{Account: models.AccountInterestReceivable, Credit: result.Interest},
+			models.EntryLine{Account: models.AccountLoan, Credit: result.Principal},
+		),
+	}
+
+	if err = s.postEntry(ctx, entry); err != nil {
+		s.logger.Errorf("failed to post repayment:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	paidCredit := credit
+	paidCredit.Status = creditStatus(credit)
+	paidCredit.OutstandingPrincipal = balances[models.AccountLoan] - result.Principal
+
+	event := newCreditEvent(ctx, models.EventPaymentReceived, paidCredit)
+	event.Data.PaymentAmount = repayment.Amount
+	paidCredit.Outbox = []models.CreditEvent{event}
+
+	if repayment.Amount == debt {
+		paidCredit.Status = models.CreditStatusClosed
+		paidCredit.Outbox = append(paidCredit.Outbox,
+			newStatusChangedEvent(ctx, paidCredit, models.CreditStatusActive),
+			newCreditEvent(ctx, models.EventCreditClosed, paidCredit),
+		)
+	}
+
+	if err = s.storage.UpdateCreditBalance(ctx, paidCredit); err != nil {
+		s.logger.Errorf("failed to update credit after repayment:%s", err)
+		return models.RepaymentResult{}, err
+	}
+
+	if err = s.recordHistory(ctx, credit.ID, models.ActionRepay, credit, paidCredit); err != nil {
+		return models.RepaymentResult{}, err
+	}
+
+	result.OutstandingPrincipal = paidCredit.OutstandingPrincipal
+	result.Status = paidCredit.Status
+
+	return result, nil
+}
+
+// ChargePenalty posts a penalty receivable that the next payments cover first
+func (s *Service) ChargePenalty(ctx context.Context, creditID string, penalty models.Penalty) (models.JournalEntry, error) {
+	s.logger.Info("received charge penalty req")
+
+	credit, err := s.activeCredit(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit for penalty:%s", err)
+		return models.JournalEntry{}, err
+	}
+
+	entry := models.JournalEntry{
+		ID:          "penalty:" + primitive.NewObjectID().Hex(),
+		Type:        models.EntryPenalty,
+		CreditID:    credit.ID,
+		Currency:    credit.Currency,
+		ValueDate:   time.Now().UTC(),
+		Description: penalty.Reason,
+		Lines: creditLines(credit.ID,
+			models.EntryLine{Account: models.AccountPenaltyReceivable, Debit: penalty.Amount},
+			models.EntryLine{Account: models.AccountPenaltyIncome, Credit: penalty.Amount},
+		),
+	}
+
+	if err = s.postEntry(ctx, entry); err != nil {
+		s.logger.Errorf("failed to post penalty:%s", err)
+		return models.JournalEntry{}, err
+	}
+
+	s.logger.Info("penalty posted")
+
+	return entry, nil
+}
+
+// postAccrual posts the interest for one day,a day is accrued only once
+func (s *Service) postAccrual(ctx context.Context, account interestBearing, day time.Time, amount int) error {
+	entry := models.JournalEntry{
+		ID:          fmt.Sprintf("accrual:%s:%s", account.id, day.Format(time.DateOnly)),
+		Type:        models.EntryAccrual,
+		CreditID:    account.id,
+		Currency:    account.currency,
+		ValueDate:   day,
+		Description: fmt.Sprintf("interest for %s", day.Format(time.DateOnly)),
+		Lines: creditLines(account.id,
+			models.EntryLine{Account: models.AccountInterestReceivable, Debit: amount},
+			models.EntryLine{Account: models.AccountInterestIncome, Credit: amount},
+		),
+	}
+
+	return s.postEntry(ctx, entry)
+}
+
+func (s *Service) GetTrialBalance(ctx context.Context, asOf time.Time) (models.TrialBalance, error) {
+	s.logger.Info("received get trial balance req")
+
+	balances, err := s.storage.GetAccountBalances(ctx, asOf)
+	if err != nil {
+		s.logger.Errorf("failed to get account balances:%s", err)
+		return models.TrialBalance{}, err
+	}
+
+	trialBalance := models.TrialBalance{
+		AsOf:        asOf,
+		Accounts:    balances,
+		TotalDebit:  make(map[string]int),
+		TotalCredit: make(map[string]int),
+		Balanced:    true,
+	}
+
+	for _, balance := range balances {
+		trialBalance.TotalDebit[balance.Currency] += balance.Debit
+		trialBalance.TotalCredit[balance.Currency] += balance.Credit
+	}
+
+	for currency, debit := range trialBalance.TotalDebit {
+		if debit != trialBalance.TotalCredit[currency] {
+			trialBalance.Balanced = false
+		}
+	}
+
+	s.logger.Info("trial balance got")
+
+	return trialBalance, nil
+}
+
+// GetAccountStatement lists postings of the account in [From,To) with a running balance.
+// Bank-wide statements mix credits,so they need a currency
+func (s *Service) GetAccountStatement(ctx context.Context, filter models.LedgerFilter) (models.AccountStatement, error) {
+	s.logger.Info("received get account statement req")
+
+	if !slices.Contains(models.Accounts, filter.Account) {
+		return models.AccountStatement{}, fmt.Errorf("%w:%s", models.ErrUnknownAccount, filter.Account)
+	}
+
+	if filter.CreditID == "" && filter.Currency == "" {
+		return models.AccountStatement{}, fmt.Errorf("%w:currency is required without creditID", models.ErrInvalidRequest)
+	}
+
+	from := filter.From
+	filter.From = time.Time{} //проводки до from нужны для входящего остатка
+
+	entries, err := s.storage.GetEntries(ctx, filter)
+	if err != nil {
+		s.logger.Errorf("failed to get journal entries:%s", err)
+		return models.AccountStatement{}, err
+	}
+
+	statement := models.AccountStatement{
+		Account:  filter.Account,
+		CreditID: filter.CreditID,
+		Currency: filter.Currency,
+		From:     from,
+		To:       filter.To,
+	}
+
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			if line.Account != filter.Account || (filter.CreditID != "" && line.CreditID != filter.CreditID) {
+				continue
+			}
+
+			statement.ClosingBalance += line.Debit - line.Credit
+
+			if entry.ValueDate.Before(from) {
+				statement.OpeningBalance = statement.ClosingBalance
+				continue
+			}
+
+			statement.Lines = append(statement.Lines, models.StatementLine{
+				EntryID:     entry.ID,
+				Type:        entry.Type,
+				ValueDate:   entry.ValueDate,
+				Description: entry.Description,
+				Debit:       line.Debit,
+				Credit:      line.Credit,
+				Balance:     statement.ClosingBalance,
+			})
+		}
+	}
+
+	s.logger.Info("account statement got")
+
+	return statement, nil
+}
+
+// CheckLedger verifies the invariant that every stored entry balances
+func (s *Service) CheckLedger(ctx context.Context) (models.LedgerCheck, error) {
+	s.logger.Info("received check ledger req")
+
+	unbalanced, err := s.storage.GetUnbalancedEntries(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to check ledger:%s", err)
+		return models.LedgerCheck{}, err
+	}
+
+	if len(unbalanced) != 0 {
+		s.logger.Errorf("ledger has %v unbalanced entries", len(unbalanced))
+	}
+
+	return models.LedgerCheck{
+		Balanced:          len(unbalanced) == 0,
+		UnbalancedEntries: unbalanced,
+	}, nil
+}
+
+// postDisbursement moves the amount from cash to the loan account,one-off fees are withheld as fee income
+func (s *Service) postDisbursement(ctx context.Context, id string, credit models.Credit, valueDate time.Time) error {
+	fees := oneOffFees(credit.Amount, credit.Fees)
+
+	entry := models.JournalEntry{
+		ID:          id,
+		Type:        models.EntryDisbursement,
+		CreditID:    credit.ID,
+		Currency:    credit.Currency,
+		ValueDate:   valueDate,
+		Description: fmt.Sprintf("disbursement of %v %s", credit.Amount, credit.Currency),
+		Lines: creditLines(credit.ID,
+			models.EntryLine{Account: models.AccountLoan, Debit: credit.Amount},
+			models.EntryLine{Account: models.AccountCash, Credit: credit.Amount - fees},
+			models.EntryLine{Account: models.AccountFeeIncome, Credit: fees},
+		),
+	}
+
+	return s.postEntry(ctx, entry)
+}
+
+// postCapitalization moves accrued interest to the principal,so interest is charged on it further
+func (s *Service) postCapitalization(ctx context.Context, id string, credit models.Credit, interest int) error {
+	if interest == 0 {
+		return nil
+	}
+
+	entry := models.JournalEntry{
+		ID:          id,
+		Type:        models.EntryCapitalization,
+		CreditID:    credit.ID,
+		Currency:    credit.Currency,
+		ValueDate:   time.Now().UTC(),
+		Description: fmt.Sprintf("capitalization of %v %s interest", interest, credit.Currency),
+		Lines: creditLines(credit.ID,
+			models.EntryLine{Account: models.AccountLoan, Debit: interest},
+			models.EntryLine{Account: models.AccountInterestReceivable, Credit: interest},
+		),
+	}
+
+	return s.postEntry(ctx, entry)
+}
+
+// reverseEntries posts the mirror of the credit entries of the given types,so their accounts net to zero
+func (s *Service) reverseEntries(ctx context.Context, id string, credit models.Credit, types ...string) error {
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: credit.ID})
+	if err != nil {
+		return err
+	}
+
+	type accountKey struct {
+		account  string
+		creditID string
+	}
+
+	net := make(map[accountKey]int)
+	currency := credit.Currency
+
+	for _, entry := range entries {
+		if !slices.Contains(types, entry.Type) {
+			continue
+		}
+		currency = entry.Currency
+		for _, line := range entry.Lines {
+			net[accountKey{line.Account, line.CreditID}] += line.Debit - line.Credit
+		}
+	}
+
+	var lines []models.EntryLine
+
+	for _, account := range models.Accounts { //порядок плана счетов,чтобы проводка была детерминированной
+		for key, balance := range net {
+			if key.account != account || balance == 0 {
+				continue
+			}
+			line := models.EntryLine{Account: key.account, CreditID: key.creditID}
+			if balance > 0 {
+				line.Credit = balance
+			} else {
+				line.Debit = -balance
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return s.postEntry(ctx, models.JournalEntry{
+		ID:          id,
+		Type:        models.EntryReversal,
+		CreditID:    credit.ID,
+		Currency:    currency,
+		ValueDate:   time.Now().UTC(),
+		Description: fmt.Sprintf("reversal of %v entries", types),
+		Lines:       lines,
+	})
+}
+
+// checkNoPayments forbids changing terms of credits that were already repaid,that's a restructuring
+func (s *Service) checkNoPayments(ctx context.Context, creditID string) error {
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: creditID})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Type == models.EntryRepayment {
+			return fmt.Errorf("%w:%s", models.ErrCreditHasPayments, creditID)
+		}
+	}
+
+	return nil
+}
+
+// postEntry stores the entry only if it balances and uses accounts of the chart
+func (s *Service) postEntry(ctx context.Context, entry models.JournalEntry) error {
+	entry.PostedAt = time.Now().UTC()
+
+	if !entry.Balanced() {
+		return fmt.Errorf("%w:%s", models.ErrUnbalancedEntry, entry.ID)
+	}
+
+	for _, line := range entry.Lines {
+		if !slices.Contains(models.Accounts, line.Account) {
+			return fmt.Errorf("%w:%s", models.ErrUnknownAccount, line.Account)
+		}
+	}
+
+	return s.storage.PostEntry(ctx, entry)
+}
+
+func (s *Service) activeCredit(ctx context.Context, creditID string) (models.Credit, error) {
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err != nil {
+		return models.Credit{}, err
+	}
+
+	if err = checkOwnership(ctx, credit); err != nil {
+		return models.Credit{}, err
+	}
+
+	if creditStatus(credit) != models.CreditStatusActive {
+		return models.Credit{}, fmt.Errorf("%w:%s is %s", models.ErrCreditNotActive, creditID, credit.Status)
+	}
+
+	return credit, nil
+}
+
+// creditBalances returns balances of the credit accounts,credits issued before the ledger get their disbursement posted first
+func (s *Service) creditBalances(ctx context.Context, credit models.Credit) (map[string]int, error) {
+	balances, err := s.storage.GetCreditBalances(ctx, credit.ID)
+	if err != nil || len(balances) != 0 {
+		return balances, err
+	}
+
+	valueDate := credit.IssuedAt
+	if valueDate.IsZero() {
+		valueDate = time.Now().UTC()
+	}
+
+	err = s.postDisbursement(ctx, "disbursement:"+credit.ID, credit, valueDate)
+	if err != nil && !errors.Is(err, models.ErrEntryAlreadyPosted) {
+		return nil, err
+	}
+
+	return s.storage.GetCreditBalances(ctx, credit.ID)
+}
+
+// creditLines drops empty lines and sets the credit on the lines of credit accounts
+func creditLines(creditID string, lines ...models.EntryLine) []models.EntryLine {
+	res := make([]models.EntryLine, 0, len(lines))
+
+	for _, line := range lines {
+		if line.Debit == 0 && line.Credit == 0 {
+			continue
+		}
+		if slices.Contains(models.CreditAccounts, line.Account) {
+			line.CreditID = creditID
+		}
+		res = append(res, line)
+	}
+
+	return res
+}
+
+// allocate takes from amount what's owed on one account and returns the taken part and the rest
+func allocate(amount, owed int) (taken, rest int) {
+	if owed <= 0 {
+		return 0, amount
+	}
+	taken = min(amount, owed)
+	return taken, amount - taken
+}
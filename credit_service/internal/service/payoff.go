@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"time"
+)
+
+// GetPayoffQuote calculates the amount that closes the credit on the date and saves it,
+// so the payoff payment can refer to the quote while it's valid
+func (s *Service) GetPayoffQuote(ctx context.Context, creditID string, date time.Time) (models.PayoffQuote, error) {
+	s.logger.Info("received get payoff quote req")
+
+	date = truncateDay(date)
+	if date.Before(truncateDay(time.Now())) {
+		return models.PayoffQuote{}, fmt.Errorf("%w:payoff date can't be in the past", models.ErrInvalidRequest)
+	}
+
+	credit, err := s.activeCredit(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get credit for payoff quote:%s", err)
+		return models.PayoffQuote{}, err
+	}
+
+	quote, err := s.payoffAmounts(ctx, credit, date)
+	if err != nil {
+		s.logger.Errorf("failed to calculate payoff:%s", err)
+		return models.PayoffQuote{}, err
+	}
+
+	quote.ID = primitive.NewObjectID().Hex()
+	quote.CreatedAt = time.Now().UTC()
+	quote.ExpiresAt = quote.CreatedAt.Add(s.cfg.Payoff.QuoteTTL)
+
+	if err = s.storage.SavePayoffQuote(ctx, quote); err != nil {
+		s.logger.Errorf("failed to save payoff quote:%s", err)
+		return models.PayoffQuote{}, err
+	}
+
+	s.logger.Info("payoff quote got")
+
+	return quote, nil
+}
+
+// payoffAmounts is the debt on the date,interest of the days before the date that isn't accrued yet is added
+func (s *Service) payoffAmounts(ctx context.Context, credit models.Credit, date time.Time) (models.PayoffQuote, error) {
+	balances, err := s.creditBalances(ctx, credit)
+	if err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: credit.ID})
+	if err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	accruals, err := CreditAccruals(credit, entries, date.AddDate(0, 0, -1), s.cfg.Accrual.DayCount)
+	if err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	quote := models.PayoffQuote{
+		CreditID:        credit.ID,
+		Currency:        credit.Currency,
+		Date:            date.Format(time.DateOnly),
+		Principal:       balances[models.AccountLoan],
+		AccruedInterest: balances[models.AccountInterestReceivable],
+		Penalties:       balances[models.AccountPenaltyReceivable],
+		Fees:            int(math.Round(float64(balances[models.AccountLoan]) * s.cfg.Payoff.FeePercent / 100)),
+	}
+
+	for _, accrual := range accruals {
+		quote.AccruedInterest += accrual.Amount
+	}
+
+	quote.Total = quote.Principal + quote.AccruedInterest + quote.Penalties + quote.Fees
+
+	return quote, nil
+}
+
+// usePayoffQuote checks the payment against the quote and accrues interest up to the payoff date,
+// after that the credit balances plus the fee are exactly the quote total
+func (s *Service) usePayoffQuote(ctx context.Context, credit models.Credit, repayment models.Repayment) (models.PayoffQuote, error) {
+	quote, err := s.storage.GetPayoffQuote(ctx, repayment.QuoteID)
+	if err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	if quote.CreditID != credit.ID {
+		return models.PayoffQuote{}, fmt.Errorf("%w:%s", models.ErrPayoffQuoteNotFound, repayment.QuoteID)
+	}
+
+	if time.Now().After(quote.ExpiresAt) {
+		return models.PayoffQuote{}, fmt.Errorf("%w:%s expired at %s", models.ErrPayoffQuoteExpired, quote.ID, quote.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if repayment.Date != "" && repayment.Date != quote.Date {
+		return models.PayoffQuote{}, fmt.Errorf("%w:payment date %s,quote date %s", models.ErrPayoffAmountMismatch, repayment.Date, quote.Date)
+	}
+
+	if repayment.Amount != quote.Total {
+		return models.PayoffQuote{}, fmt.Errorf("%w:paid %v,quoted %v", models.ErrPayoffAmountMismatch, repayment.Amount, quote.Total)
+	}
+
+	date, err := time.Parse(time.DateOnly, quote.Date)
+	if err != nil {
+		return models.PayoffQuote{}, fmt.Errorf("invalid payoff quote date:%s", err)
+	}
+
+	current, err := s.payoffAmounts(ctx, credit, date)
+	if err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	if current.Total != quote.Total {
+		return models.PayoffQuote{}, fmt.Errorf("%w:quoted %v,owed %v", models.ErrPayoffQuoteOutdated, quote.Total, current.Total)
+	}
+
+	if _, _, err = s.accrue(ctx, creditInterest(credit), date.AddDate(0, 0, -1)); err != nil {
+		return models.PayoffQuote{}, err
+	}
+
+	return quote, nil
+}
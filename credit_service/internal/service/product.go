@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (s *Service) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	s.logger.Info("received create product req")
+
+	if err := validateProduct(product); err != nil {
+		s.logger.Errorf("invalid product:%s", err)
+		return models.Product{}, err
+	}
+
+	createdProduct, err := s.storage.CreateProduct(ctx, product)
+	if err != nil {
+		s.logger.Errorf("failed to create product:%s", err)
+		return models.Product{}, err
+	}
+
+	s.logger.Info("product created")
+
+	return createdProduct, nil
+}
+
+func (s *Service) GetProducts(ctx context.Context) ([]models.Product, error) {
+	s.logger.Info("received get products req")
+
+	products, err := s.storage.GetProducts(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get products:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("products got")
+
+	return products, nil
+}
+
+func (s *Service) GetProductById(ctx context.Context, id string) (models.Product, error) {
+	s.logger.Info("received get product by id req")
+
+	product, err := s.storage.GetProductById(ctx, id)
+	if err != nil {
+		s.logger.Errorf("failed to get product by id:%s", err)
+		return models.Product{}, err
+	}
+
+	s.logger.Info("product by id got")
+
+	return product, nil
+}
+
+func (s *Service) UpdateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	s.logger.Info("received update product req")
+
+	if err := validateProduct(product); err != nil {
+		s.logger.Errorf("invalid product:%s", err)
+		return models.Product{}, err
+	}
+
+	updatedProduct, err := s.storage.UpdateProduct(ctx, product)
+	if err != nil {
+		s.logger.Errorf("failed to update product:%s", err)
+		return models.Product{}, err
+	}
+
+	s.logger.Info("product updated")
+
+	return updatedProduct, nil
+}
+
+func (s *Service) DeleteProduct(ctx context.Context, id string) error {
+	s.logger.Info("received delete product req")
+
+	if err := s.storage.DeleteProduct(ctx, id); err != nil {
+		s.logger.Errorf("failed to delete product:%s", err)
+		return err
+	}
+
+	s.logger.Info("product deleted")
+
+	return nil
+}
+
+func validateProduct(product models.Product) error {
+	if product.MinAmount <= 0 || product.MinAmount > product.MaxAmount {
+		return fmt.Errorf("%w:amount bounds %v-%v", models.ErrInvalidProduct, product.MinAmount, product.MaxAmount)
+	}
+
+	if product.MinTerm <= 0 || product.MinTerm > product.MaxTerm {
+		return fmt.Errorf("%w:term bounds %v-%v", models.ErrInvalidProduct, product.MinTerm, product.MaxTerm)
+	}
+
+	for i, entry := range product.RateGrid {
+		if entry.MinTerm > entry.MaxTerm || entry.MinAmount > entry.MaxAmount {
+			return fmt.Errorf("%w:rate grid entry %v has wrong bounds", models.ErrInvalidProduct, i)
+		}
+		if entry.AnnualInterestRate <= 0 {
+			return fmt.Errorf("%w:rate grid entry %v must have a positive rate", models.ErrInvalidProduct, i)
+		}
+	}
+
+	if product.GraceMonths < 0 || product.InterestOnlyMonths < 0 || product.Months() >= product.MinTerm {
+		return fmt.Errorf("%w:initial period of %v months must be shorter than the min term", models.ErrInvalidProduct, product.Months())
+	}
+
+	for _, fee := range product.Fees {
+		if fee.Type != models.FeeOneOff && fee.Type != models.FeeMonthly {
+			return fmt.Errorf("%w:fee '%s' has unknown type '%s'", models.ErrInvalidProduct, fee.Name, fee.Type)
+		}
+		if fee.Amount < 0 || fee.Percent < 0 { //отрицательная комиссия уменьшила бы долг
+			return fmt.Errorf("%w:fee '%s' must not be negative", models.ErrInvalidProduct, fee.Name)
+		}
+	}
+
+	return nil
+}
+
+// productRate checks the credit against the product limits and picks the rate from the product rate grid.
+// All limit violations are returned at once as *models.ValidationError
+func productRate(product models.Product, currency string, amount, term int) (float64, error) {
+	validationErr := &models.ValidationError{}
+
+	if !slices.Contains(product.Currencies, currency) {
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Currency",
+			Message: fmt.Sprintf("the 'Currency' value must be one of %s for product %s", strings.Join(product.Currencies, ","), product.Name),
+		})
+	}
+
+	if amount < product.MinAmount || amount > product.MaxAmount {
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Amount",
+			Message: fmt.Sprintf("the 'Amount' value must be between %v and %v for product %s", product.MinAmount, product.MaxAmount, product.Name),
+		})
+	}
+
+	if term < product.MinTerm || term > product.MaxTerm {
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   "Term",
+			Message: fmt.Sprintf("the 'Term' value must be between %v and %v for product %s", product.MinTerm, product.MaxTerm, product.Name),
+		})
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return 0, validationErr
+	}
+
+	for _, entry := range product.RateGrid {
+		if term >= entry.MinTerm && term <= entry.MaxTerm && amount >= entry.MinAmount && amount <= entry.MaxAmount {
+			return entry.AnnualInterestRate, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w:no rate for amount %v and term %v", models.ErrProductMismatch, amount, term)
+}
+
+// applyProduct sets the interest rate and fees of the credit from its product and checks its collateral.
+// A floating rate is the current index value plus the margin from the rate grid.
+// Credits above the approval threshold of the product get a non-nil Approval
+func (s *Service) applyProduct(ctx context.Context, credit *models.Credit) error {
+	product, err := s.storage.GetProductById(ctx, credit.ProductID)
+	if err != nil {
+		return err
+	}
+
+	rate, err := productRate(product, credit.Currency, credit.Amount, credit.Term)
+	if err != nil {
+		return err
+	}
+
+	credit.AnnualInterestRate = rate
+	credit.RateIndex, credit.RateMargin, credit.RateResetMonths = "", 0, 0
+
+	if product.RateIndex != "" { //ставка сетки - маржа к индексу
+		credit.RateIndex = product.RateIndex
+		credit.RateMargin = rate
+		credit.RateResetMonths = product.RateResetMonths
+		if credit.RateResetMonths == 0 {
+			credit.RateResetMonths = s.cfg.FloatingRate.ResetMonths
+		}
+
+		if credit.AnnualInterestRate, _, err = s.floatingRate(ctx, product.RateIndex, rate, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	credit.Fees = product.Fees
+	credit.InitialPeriod = product.InitialPeriod
+
+	credit.Approval = nil
+	if threshold, ok := approvalThreshold(product, credit.Currency, credit.Amount); ok {
+		credit.Approval = &models.Approval{Threshold: threshold}
+	}
+
+	return applyCollateral(product, credit)
+}
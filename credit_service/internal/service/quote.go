@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"time"
+)
+
+// Quote calculates the credit for the product without saving anything,so the user doesn't have to exist
+func (s *Service) Quote(ctx context.Context, req models.QuoteRequest) (models.Quote, error) {
+	s.logger.Info("received quote req")
+
+	product, err := s.storage.GetProductById(ctx, req.ProductID)
+	if err != nil {
+		s.logger.Errorf("failed to get product for quote:%s", err)
+		return models.Quote{}, err
+	}
+
+	rate, err := productRate(product, req.Currency, req.Amount, req.Term)
+	if err != nil {
+		s.logger.Errorf("failed to get rate for quote:%s", err)
+		return models.Quote{}, err
+	}
+
+	if product.RateIndex != "" { //расчет по текущему значению индекса
+		if rate, _, err = s.floatingRate(ctx, product.RateIndex, rate, time.Now().UTC()); err != nil {
+			s.logger.Errorf("failed to get floating rate for quote:%s", err)
+			return models.Quote{}, err
+		}
+	}
+
+	if req.Scheme == "" {
+		req.Scheme = models.SchemeAnnuity
+	}
+
+	schedule, err := BuildSchedule(req.Amount, req.Term, rate, req.Scheme, product.Fees, time.Now(), product.InitialPeriod)
+	if err != nil {
+		s.logger.Errorf("failed to build schedule for quote:%s", err)
+		return models.Quote{}, err
+	}
+
+	quote := models.Quote{
+		ProductID:          req.ProductID,
+		Amount:             req.Amount,
+		Currency:           req.Currency,
+		Term:               req.Term,
+		Scheme:             req.Scheme,
+		AnnualInterestRate: rate,
+		MonthlyPayment:     schedule[product.Months()].Payment,
+		TotalFees:          oneOffFees(req.Amount, product.Fees),
+		TotalPayment:       oneOffFees(req.Amount, product.Fees),
+		Schedule:           schedule,
+		InitialPeriod:      product.InitialPeriod,
+	}
+
+	for _, payment := range schedule {
+		quote.TotalPayment += payment.Payment
+		quote.TotalFees += payment.Fees
+	}
+
+	quote.TotalInterest = quote.TotalPayment - req.Amount - quote.TotalFees //с процентами льготного периода,вошедшими в основной долг
+
+	quote.APR, err = EffectiveAnnualRate(req.Amount, oneOffFees(req.Amount, product.Fees), schedule)
+	if err != nil {
+		s.logger.Errorf("failed to calculate APR for quote:%s", err)
+		return models.Quote{}, err
+	}
+
+	s.logger.Info("quote calculated")
+
+	return quote, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Refinance opens a new credit and pays off the old credits of the user with it in one transaction,
+// so a half-done consolidation is never saved.The credits are linked both ways
+func (s *Service) Refinance(ctx context.Context, req models.RefinanceRequest) (models.Refinancing, error) {
+	s.logger.Info("received refinance req")
+
+	if err := checkActingUser(ctx, req.UserID); err != nil {
+		return models.Refinancing{}, err
+	}
+
+	var result models.Refinancing
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.refinance(ctx, req)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to refinance:%s", err)
+		return models.Refinancing{}, err
+	}
+
+	s.logger.Info("credits refinanced")
+
+	return result, nil
+}
+
+func (s *Service) refinance(ctx context.Context, req models.RefinanceRequest) (models.Refinancing, error) {
+	today := time.Now().UTC()
+	validationErr := &models.ValidationError{}
+	seen := make(map[string]bool)
+
+	var quotes []models.PayoffQuote
+	var result models.Refinancing
+
+	for i, creditID := range req.CreditIDs {
+		field := fmt.Sprintf("CreditIDs[%v]", i)
+
+		credit, err := s.activeCredit(ctx, creditID)
+		if err != nil {
+			return models.Refinancing{}, err
+		}
+
+		switch {
+		case seen[creditID]:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("credit %s is listed twice", creditID),
+			})
+			continue
+		case credit.UserID != req.UserID:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("user %v is not the primary borrower of credit %s", req.UserID, creditID),
+			})
+			continue
+		case credit.Currency != req.Currency:
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("credit %s is in %s,not in %s", creditID, credit.Currency, req.Currency),
+			})
+			continue
+		}
+		seen[creditID] = true
+
+		quote, err := s.GetPayoffQuote(ctx, creditID, today)
+		if err != nil {
+			return models.Refinancing{}, err
+		}
+
+		quotes = append(quotes, quote)
+		result.PayoffTotal += quote.Total
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return models.Refinancing{}, validationErr
+	}
+
+	product, err := s.storage.GetProductById(ctx, req.ProductID)
+	if err != nil {
+		return models.Refinancing{}, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = grossUp(result.PayoffTotal, product.Fees)
+	}
+
+	if cash := amount - oneOffFees(amount, product.Fees); cash < result.PayoffTotal {
+		return models.Refinancing{}, &models.ValidationError{Fields: []models.FieldError{{
+			Field:   "Amount",
+			Message: fmt.Sprintf("the 'Amount' value pays out %v after fees,less than the payoff amount %v", cash, result.PayoffTotal),
+		}}}
+	}
+
+	result.Credit, err = s.CreateCredit(ctx, models.Credit{
+		UserID:         req.UserID,
+		ProductID:      req.ProductID,
+		Amount:         amount,
+		Currency:       req.Currency,
+		Term:           req.Term,
+		Scheme:         req.Scheme,
+		RefinancedFrom: req.CreditIDs,
+	})
+	if err != nil {
+		return models.Refinancing{}, err
+	}
+
+	if result.Credit.Status == models.CreditStatusPending { //старые кредиты погашаются только выданным кредитом
+		return models.Refinancing{}, fmt.Errorf("%w:amount %v is above the threshold %v of the product",
+			models.ErrApprovalRequired, result.Credit.Amount, result.Credit.Approval.Threshold)
+	}
+
+	for _, quote := range quotes {
+		repayment := models.Repayment{
+			PaymentID: fmt.Sprintf("refinance:%s:%s", result.Credit.ID, quote.CreditID),
+			QuoteID:   quote.ID,
+			Amount:    quote.Total,
+		}
+
+		if _, err = s.Repay(ctx, quote.CreditID, repayment); err != nil {
+			return models.Refinancing{}, err
+		}
+
+		if err = s.storage.SetRefinancedBy(ctx, quote.CreditID, result.Credit.ID); err != nil {
+			return models.Refinancing{}, err
+		}
+
+		result.Closed = append(result.Closed, models.RefinancedCredit{
+			CreditID:     quote.CreditID,
+			QuoteID:      quote.ID,
+			PayoffAmount: quote.Total,
+		})
+	}
+
+	result.CashOut = result.Credit.Amount - oneOffFees(result.Credit.Amount, result.Credit.Fees) - result.PayoffTotal
+
+	return result, nil
+}
+
+// grossUp is the smallest credit amount that pays out the sum after one-off fees are withheld
+func grossUp(sum int, fees []models.Fee) int {
+	amount := sum
+	for i := 0; i < 100 && amount-oneOffFees(amount, fees) < sum; i++ { //комиссия от суммы растет вместе с суммой
+		amount += sum - (amount - oneOffFees(amount, fees))
+	}
+	return amount
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"slices"
+	"time"
+)
+
+// Reschedule is how the payments of a credit that aren't due yet are rebuilt
+type Reschedule struct {
+	Principal          int       //основной долг на дату изменения
+	Term               int       //количество платежей после каникул
+	AnnualInterestRate float64   //годовая % ставка
+	Scheme             string    //annuity,differentiated
+	MonthlyFee         int       //ежемесячная комиссия,на время каникул не берется
+	HolidayMonths      int       //месяцы без платежей
+	InterestOnlyMonths int       //оставшиеся месяцы льготного периода после каникул
+	InterestTreatment  string    //capitalize,defer
+	Start              time.Time //дата последнего наступившего платежа или выдачи,от нее отсчитываются новые платежи
+}
+
+// GrantPaymentHoliday suspends payments of the credit for the months of the holiday,the remaining payments move after it.
+// Interest of the holiday is added to the principal or spread over the payments after the holiday
+func (s *Service) GrantPaymentHoliday(ctx context.Context, creditID string, holiday models.PaymentHoliday) (models.Restructuring, error) {
+	s.logger.Info("received payment holiday req")
+
+	restructuring := models.Restructuring{
+		Type:              models.RestructuringHoliday,
+		HolidayMonths:     holiday.Months,
+		InterestTreatment: holiday.InterestTreatment,
+		Reason:            holiday.Reason,
+	}
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		restructuring, err = s.restructure(ctx, creditID, restructuring)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to grant payment holiday:%s", err)
+		return models.Restructuring{}, err
+	}
+
+	s.logger.Info("payment holiday granted")
+
+	return restructuring, nil
+}
+
+// RestructureCredit rebuilds the payments of the credit that aren't due yet with the new term or rate
+func (s *Service) RestructureCredit(ctx context.Context, creditID string, req models.RestructureRequest) (models.Restructuring, error) {
+	s.logger.Info("received restructure credit req")
+
+	if req.Term == 0 && req.AnnualInterestRate == 0 {
+		return models.Restructuring{}, fmt.Errorf("%w:you must fill the 'Term' or the 'AnnualInterestRate' value", models.ErrInvalidRequest)
+	}
+
+	restructuring := models.Restructuring{
+		Type:            models.RestructuringTerms,
+		NewTerm:         req.Term,
+		NewInterestRate: req.AnnualInterestRate,
+		Reason:          req.Reason,
+	}
+
+	err := s.storage.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		restructuring, err = s.restructure(ctx, creditID, restructuring)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("failed to restructure credit:%s", err)
+		return models.Restructuring{}, err
+	}
+
+	s.logger.Info("credit restructured")
+
+	return restructuring, nil
+}
+
+func (s *Service) GetRestructurings(ctx context.Context, creditID string) ([]models.Restructuring, error) {
+	s.logger.Info("received get restructurings req")
+
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err == nil {
+		err = checkOwnership(ctx, credit)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get restructurings:%s", err)
+		return nil, err
+	}
+
+	restructurings, err := s.storage.GetRestructurings(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get restructurings:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("restructurings got")
+
+	return restructurings, nil
+}
+
+// restructure replaces the payments of the credit from today on,NewTerm of the restructuring is the number of the new payments,
+// zero NewTerm and NewInterestRate keep the remaining payments count and the rate.
+// Only staff restructure credits,the approver is the employee of the token
+func (s *Service) restructure(ctx context.Context, creditID string, restructuring models.Restructuring) (models.Restructuring, error) {
+	actor := requestMetaFromContext(ctx).actor
+	if requestMetaFromContext(ctx).userID != 0 || actor == "" {
+		return models.Restructuring{}, fmt.Errorf("%w:only staff can restructure credits", models.ErrStaffOnly)
+	}
+
+	credit, err := s.activeCredit(ctx, creditID)
+	if err != nil {
+		return models.Restructuring{}, err
+	}
+
+	today := truncateDay(time.Now().UTC())
+
+	//проценты до сегодняшнего дня начисляются по старым условиям
+	if _, _, err = s.accrue(ctx, creditInterest(credit), today.AddDate(0, 0, -1)); err != nil {
+		return models.Restructuring{}, err
+	}
+
+	balances, err := s.creditBalances(ctx, credit)
+	if err != nil {
+		return models.Restructuring{}, err
+	}
+
+	original, err := creditSchedule(credit)
+	if err != nil {
+		return models.Restructuring{}, err
+	}
+
+	kept, remaining := splitSchedule(original, today)
+	if len(remaining) == 0 {
+		return models.Restructuring{}, fmt.Errorf("%w:credit %s has no payments left", models.ErrInvalidRequest, creditID)
+	}
+
+	restructuring.ID = primitive.NewObjectID().Hex()
+	restructuring.CreditID = credit.ID
+	restructuring.OldTerm = credit.Term
+	restructuring.OldInterestRate = credit.AnnualInterestRate
+	restructuring.OutstandingPrincipal = balances[models.AccountLoan]
+
+	if restructuring.InterestTreatment == models.InterestCapitalize {
+		restructuring.CapitalizedInterest = balances[models.AccountInterestReceivable]
+		restructuring.OutstandingPrincipal += restructuring.CapitalizedInterest
+
+		if err = s.postCapitalization(ctx, "capitalization:"+restructuring.ID, credit, restructuring.CapitalizedInterest); err != nil {
+			return models.Restructuring{}, err
+		}
+	}
+
+	if restructuring.OutstandingPrincipal <= 0 {
+		return models.Restructuring{}, fmt.Errorf("%w:credit %s has no principal left", models.ErrInvalidRequest, creditID)
+	}
+
+	if restructuring.NewInterestRate == 0 {
+		restructuring.NewInterestRate = credit.AnnualInterestRate
+	}
+
+	term := restructuring.NewTerm
+	if term == 0 {
+		term = len(remaining)
+	}
+
+	var interestOnly int
+	if restructuring.Type == models.RestructuringHoliday { //каникулы сдвигают льготный период,новые условия его заменяют
+		interestOnly = interestOnlyMonths(remaining)
+	}
+
+	schedule, err := RescheduleCredit(kept, Reschedule{
+		Principal:          restructuring.OutstandingPrincipal,
+		Term:               term,
+		AnnualInterestRate: restructuring.NewInterestRate,
+		Scheme:             credit.Scheme,
+		MonthlyFee:         original[len(original)-1].Fees, //в месяцы без платежей комиссия не берется
+		HolidayMonths:      restructuring.HolidayMonths,
+		InterestTreatment:  restructuring.InterestTreatment,
+		InterestOnlyMonths: interestOnly,
+		Start:              credit.IssuedAt.AddDate(0, len(kept), 0),
+	})
+	if err != nil {
+		return models.Restructuring{}, err
+	}
+
+	first := schedule[len(kept)+restructuring.HolidayMonths+interestOnly] //первый платеж с погашением основного долга
+
+	restructured := credit
+	restructured.Schedule = schedule
+	restructured.Term = len(schedule)
+	restructured.AnnualInterestRate = restructuring.NewInterestRate
+	if restructuring.NewInterestRate != credit.AnnualInterestRate {
+		restructured.RateHistory = withRatePeriod(credit, today)
+	}
+	if credit.RateIndex != "" { //новая ставка плавающего кредита действует и после пересмотров
+		restructured.RateMargin += restructuring.NewInterestRate - restructuring.OldInterestRate
+	}
+	restructured.MonthlyPayment = first.Principal + first.Interest
+	restructured.MaturesAt = credit.IssuedAt.AddDate(0, restructured.Term, 0)
+	restructured.OutstandingPrincipal = restructuring.OutstandingPrincipal
+	restructured.Outbox = []models.CreditEvent{newCreditEvent(ctx, models.EventCreditUpdated, restructured)}
+
+	if restructured, err = s.storage.UpdateCredit(ctx, restructured); err != nil {
+		return models.Restructuring{}, err
+	}
+
+	if err = s.recordHistory(ctx, credit.ID, models.ActionRestructure, credit, restructured); err != nil {
+		return models.Restructuring{}, err
+	}
+
+	restructuring.NewTerm = restructured.Term
+	restructuring.EffectiveDate = today.Format(time.DateOnly)
+	restructuring.ApprovedBy = actor
+	restructuring.Actor = actor
+	restructuring.CreatedAt = time.Now().UTC()
+	restructuring.OriginalSchedule = original
+	restructuring.Schedule = schedule
+
+	if err = s.storage.SaveRestructuring(ctx, restructuring); err != nil {
+		return models.Restructuring{}, err
+	}
+
+	return restructuring, nil
+}
+
+// RescheduleCredit keeps the payments that are already due and builds the rest from the outstanding principal.
+// Nothing is paid during the holiday,its interest is added to the principal or spread evenly over the payments after it
+func RescheduleCredit(kept []models.Payment, terms Reschedule) ([]models.Payment, error) {
+	schedule := slices.Clone(kept)
+	balance := terms.Principal
+	monthlyInterestRate := terms.AnnualInterestRate / 100 / 12
+
+	var deferred int
+
+	for i := 1; i <= terms.HolidayMonths; i++ {
+		interest := int(math.Round(float64(balance) * monthlyInterestRate))
+		if terms.InterestTreatment == models.InterestCapitalize {
+			balance += interest
+		} else {
+			deferred += interest
+		}
+
+		schedule = append(schedule, models.Payment{
+			Number:  len(schedule) + 1,
+			Date:    terms.Start.AddDate(0, i, 0).Format(scheduleDateLayout),
+			Balance: balance,
+		})
+	}
+
+	payments, err := BuildSchedule(balance, terms.Term, terms.AnnualInterestRate, terms.Scheme, nil, terms.Start.AddDate(0, terms.HolidayMonths, 0),
+		models.InitialPeriod{InterestOnlyMonths: terms.InterestOnlyMonths})
+	if err != nil {
+		return nil, err
+	}
+
+	share := deferred / len(payments)
+
+	for i, payment := range payments {
+		deferredInterest := share
+		if i == len(payments)-1 {
+			deferredInterest = deferred - share*(len(payments)-1) //остаток от деления уходит в последний платеж
+		}
+
+		payment.Number = len(schedule) + 1
+		payment.Interest += deferredInterest
+		payment.Fees = terms.MonthlyFee
+		payment.Payment += deferredInterest + terms.MonthlyFee
+
+		schedule = append(schedule, payment)
+	}
+
+	return schedule, nil
+}
+
+// interestOnlyMonths counts the interest-only payments the schedule starts with,the last payment always repays principal
+func interestOnlyMonths(schedule []models.Payment) int {
+	var months int
+	for months < len(schedule)-1 && schedule[months].Principal == 0 && schedule[months].Payment > 0 {
+		months++
+	}
+	return months
+}
+
+// creditSchedule is the current schedule of the credit,credits that were never restructured follow their terms
+func creditSchedule(credit models.Credit) ([]models.Payment, error) {
+	if credit.Schedule != nil {
+		return credit.Schedule, nil
+	}
+
+	return BuildSchedule(credit.Amount, credit.Term, credit.AnnualInterestRate, credit.Scheme, credit.Fees, credit.IssuedAt, credit.InitialPeriod)
+}
+
+// splitSchedule splits the schedule into payments due before the day and the remaining ones
+func splitSchedule(schedule []models.Payment, day time.Time) (due, remaining []models.Payment) {
+	date := day.Format(scheduleDateLayout)
+
+	for i, payment := range schedule {
+		if payment.Date >= date {
+			return schedule[:i], schedule[i:]
+		}
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"math"
+	"time"
+)
+
+const scheduleDateLayout = "2006-01-02"
+
+// BuildSchedule splits the credit into monthly payments.The last payment closes the rest of the principal,so rounding never leaves a debt.
+// The principal isn't paid during the initial period:grace months are free and their interest is capitalized,
+// interest-only months pay the interest and fees
+func BuildSchedule(amount, term int, annualInterestRate float64, scheme string, fees []models.Fee, dateOfIssue time.Time, initial models.InitialPeriod) ([]models.Payment, error) {
+	if scheme == "" {
+		scheme = models.SchemeAnnuity
+	}
+
+	if scheme != models.SchemeAnnuity && scheme != models.SchemeDifferentiated {
+		return nil, fmt.Errorf("%w:%s", models.ErrUnknownScheme, scheme)
+	}
+
+	if initial.GraceMonths < 0 || initial.InterestOnlyMonths < 0 || initial.Months() >= term {
+		return nil, fmt.Errorf("%w:initial period of %v months doesn't fit the term of %v months", models.ErrProductMismatch, initial.Months(), term)
+	}
+
+	monthlyInterestRate := annualInterestRate / 100 / 12
+	monthlyFee := monthlyFees(amount, fees)
+	annuityPayment, _, _ := CalculateCreditParams(term, amount, annualInterestRate, initial)
+
+	schedule := make([]models.Payment, 0, term)
+	balance := amount
+	amortized := amount //основной долг к началу погашения
+
+	for i := 1; i <= term; i++ {
+		interest := int(math.Round(float64(balance) * monthlyInterestRate))
+
+		if i <= initial.GraceMonths {
+			balance += interest
+
+			schedule = append(schedule, models.Payment{
+				Number:  i,
+				Date:    dateOfIssue.AddDate(0, i, 0).Format(scheduleDateLayout),
+				Balance: balance,
+			})
+			continue
+		}
+
+		if i == initial.Months()+1 {
+			amortized = balance
+		}
+
+		var principal int
+		switch {
+		case i <= initial.Months():
+			principal = 0
+		case i == term:
+			principal = balance
+		case scheme == models.SchemeAnnuity:
+			principal = annuityPayment - interest
+		default:
+			principal = amortized / (term - initial.Months())
+		}
+
+		balance -= principal
+
+		schedule = append(schedule, models.Payment{
+			Number:    i,
+			Date:      dateOfIssue.AddDate(0, i, 0).Format(scheduleDateLayout),
+			Payment:   principal + interest + monthlyFee,
+			Principal: principal,
+			Interest:  interest,
+			Fees:      monthlyFee,
+			Balance:   balance,
+		})
+	}
+
+	return schedule, nil
+}
+
+// oneOffFees sums the fees paid once when the credit is issued
+func oneOffFees(amount int, fees []models.Fee) int {
+	var total float64
+
+	for _, fee := range fees {
+		if fee.Type == models.FeeOneOff {
+			total += float64(fee.Amount) + float64(amount)*fee.Percent/100
+		}
+	}
+
+	return int(math.Round(total))
+}
+
+// monthlyFees sums the fees paid with every monthly payment
+func monthlyFees(amount int, fees []models.Fee) int {
+	var total float64
+
+	for _, fee := range fees {
+		if fee.Type == models.FeeMonthly {
+			total += float64(fee.Amount) + float64(amount)*fee.Percent/100
+		}
+	}
+
+	return int(math.Round(total))
+}
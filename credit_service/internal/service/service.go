@@ -3,11 +3,25 @@ package service
 import (
 	"bank/credit_service/internal/domain/models"
 	"context"
+	"time"
 )
 
 type Storage interface {
 	Auth
 	KafkaConsumer
+	History
+	Product
+	ExchangeRate
+	Analytics
+	Outbox
+	Ledger
+	PayoffQuote
+	CreditLine
+	Restructuring
+	RateIndex
+	Document
+	Statement
+	Tx
 }
 
 type Auth interface {
@@ -16,9 +30,111 @@ type Auth interface {
 	GetCreditById(ctx context.Context, id string) (models.Credit, error)
 	GetCreditsByUserId(ctx context.Context, userID int64) ([]models.Credit, error)
 	UpdateCredit(ctx context.Context, credit models.Credit) (updatedCredit models.Credit, err error)
-	DeleteCredit(ctx context.Context, id string) error
+	DeleteCredit(ctx context.Context, credit models.Credit) error
+	UpdateCreditBalance(ctx context.Context, credit models.Credit) error
+	GetActiveCredits(ctx context.Context) ([]models.Credit, error)
+	GetPendingApprovals(ctx context.Context) ([]models.Credit, error)
+	UpdateCreditApproval(ctx context.Context, credit models.Credit) error
+	IsUserExist(ctx context.Context, userID int64) bool
+	CheckNewCredit(ctx context.Context, credit models.Credit) error
+	ExportCredits(ctx context.Context, fn func(models.Credit) error) error
+	SetRefinancedBy(ctx context.Context, creditID, newCreditID string) error
+	GetTotalsByCurrency(ctx context.Context, userID int64) ([]models.CurrencyTotal, error)
 }
 
 type KafkaConsumer interface {
 	NewUserIDCollection(ctx context.Context, userID int64) error
 }
+
+type History interface {
+	AddCreditHistory(ctx context.Context, record models.CreditHistory) error
+	GetCreditHistory(ctx context.Context, creditID string) ([]models.CreditHistory, error)
+}
+
+type Product interface {
+	CreateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	GetProducts(ctx context.Context) ([]models.Product, error)
+	GetProductById(ctx context.Context, id string) (models.Product, error)
+	UpdateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+}
+
+type ExchangeRate interface {
+	SaveExchangeRate(ctx context.Context, rate models.ExchangeRate) error
+	GetExchangeRate(ctx context.Context, currency, base, asOf string) (models.ExchangeRate, error)
+	GetExchangeRateHistory(ctx context.Context, currency, base string) ([]models.ExchangeRate, error)
+}
+
+// DocumentRenderer fills the latest version of the template of the document type
+type DocumentRenderer interface {
+	Render(docType string, data models.DocumentData) (content []byte, version string, err error)
+	ContentType() string
+}
+
+// RateProvider is the source of exchange rates(file,in-memory or an external api)
+type RateProvider interface {
+	Rate(ctx context.Context, currency, base string, date time.Time) (models.ExchangeRate, error)
+}
+
+type Analytics interface {
+	OutstandingByCurrency(ctx context.Context, filter models.AnalyticsFilter) ([]models.OutstandingByCurrency, error)
+	WeightedAverageRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.WeightedAverageRate, error)
+	IssuedByMonth(ctx context.Context, filter models.AnalyticsFilter) ([]models.IssuedByMonth, error)
+	MaturityProfile(ctx context.Context, filter models.AnalyticsFilter) ([]models.MaturityBucket, error)
+	ExposureByUser(ctx context.Context, filter models.AnalyticsFilter) ([]models.UserCurrencyExposure, error)
+}
+
+type Outbox interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]models.CreditEvent, error)
+	DeletePublishedEvents(ctx context.Context, creditID string, eventIDs []string) error
+}
+
+type Ledger interface {
+	PostEntry(ctx context.Context, entry models.JournalEntry) error
+	GetEntries(ctx context.Context, filter models.LedgerFilter) ([]models.JournalEntry, error)
+	GetCreditBalances(ctx context.Context, creditID string) (map[string]int, error)
+	GetAccountBalances(ctx context.Context, asOf time.Time) ([]models.AccountBalance, error)
+	GetUnbalancedEntries(ctx context.Context) ([]string, error)
+}
+
+type PayoffQuote interface {
+	SavePayoffQuote(ctx context.Context, quote models.PayoffQuote) error
+	GetPayoffQuote(ctx context.Context, id string) (models.PayoffQuote, error)
+}
+
+type CreditLine interface {
+	CreateCreditLine(ctx context.Context, line models.CreditLine) (models.CreditLine, error)
+	GetCreditLineById(ctx context.Context, id string) (models.CreditLine, error)
+	GetActiveCreditLines(ctx context.Context) ([]models.CreditLine, error)
+	ReserveDrawdown(ctx context.Context, id string, amount int) error
+	ChangeDrawn(ctx context.Context, id string, delta int) error
+	CloseCreditLine(ctx context.Context, id string, closedAt time.Time) error
+}
+
+type Restructuring interface {
+	SaveRestructuring(ctx context.Context, restructuring models.Restructuring) error
+	GetRestructurings(ctx context.Context, creditID string) ([]models.Restructuring, error)
+}
+
+type RateIndex interface {
+	SaveIndexValue(ctx context.Context, value models.IndexValue) error
+	GetIndexValue(ctx context.Context, index, date string) (models.IndexValue, error)
+	GetIndexValues(ctx context.Context, index string) ([]models.IndexValue, error)
+}
+
+// Tx runs storage calls made with the ctx passed to fn atomically
+type Tx interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Document interface {
+	SaveDocument(ctx context.Context, document models.Document) error
+	GetDocuments(ctx context.Context, creditID string) ([]models.Document, error)
+	GetDocument(ctx context.Context, creditID, documentID string) (models.Document, error)
+}
+
+type Statement interface {
+	SaveStatement(ctx context.Context, statement models.CreditStatement) error
+	GetStatements(ctx context.Context, creditID string) ([]models.CreditStatement, error)
+	GetStatementCreditIDs(ctx context.Context, from time.Time) ([]string, error)
+}
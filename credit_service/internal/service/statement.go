@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetCreditStatement is the statement of the credit for [from,to),the current month by default
+func (s *Service) GetCreditStatement(ctx context.Context, creditID string, from, to time.Time) (models.CreditStatement, error) {
+	s.logger.Info("received get credit statement req")
+
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err == nil {
+		err = checkOwnership(ctx, credit)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get credit statement:%s", err)
+		return models.CreditStatement{}, err
+	}
+
+	if from, to, err = statementPeriod(from, to); err != nil {
+		return models.CreditStatement{}, err
+	}
+
+	entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: credit.ID, To: to})
+	if err != nil {
+		s.logger.Errorf("failed to get journal entries:%s", err)
+		return models.CreditStatement{}, err
+	}
+
+	s.logger.Info("credit statement got")
+
+	return BuildStatement(credit, entries, from, to), nil
+}
+
+// GetUserStatement is the statements of the issued credits of the user for [from,to),the current month by default
+func (s *Service) GetUserStatement(ctx context.Context, userID int64, from, to time.Time) (models.UserStatement, error) {
+	s.logger.Info("received get user statement req")
+
+	if meta := requestMetaFromContext(ctx); meta.userID != 0 && meta.userID != userID { //чужие кредиты не показываются
+		return models.UserStatement{}, fmt.Errorf("%w:%v", models.ErrUserCreditsNotFound, userID)
+	}
+
+	from, to, err := statementPeriod(from, to)
+	if err != nil {
+		return models.UserStatement{}, err
+	}
+
+	credits, err := s.storage.GetCreditsByUserId(ctx, userID)
+	if err != nil {
+		s.logger.Errorf("failed to get user credits:%s", err)
+		return models.UserStatement{}, err
+	}
+	statement := models.UserStatement{UserID: userID, From: from, To: to}
+
+	for _, credit := range credits {
+		if status := creditStatus(credit); status == models.CreditStatusPending || status == models.CreditStatusRejected {
+			continue //не выданы,движений нет
+		}
+
+		entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: credit.ID, To: to})
+		if err != nil {
+			s.logger.Errorf("failed to get journal entries:%s", err)
+			return models.UserStatement{}, err
+		}
+
+		statement.Credits = append(statement.Credits, BuildStatement(credit, entries, from, to))
+	}
+
+	s.logger.Info("user statement got")
+
+	return statement, nil
+}
+
+// GetMonthlyStatements returns the month-end statements of the credit made by RunMonthlyStatements
+func (s *Service) GetMonthlyStatements(ctx context.Context, creditID string) ([]models.CreditStatement, error) {
+	s.logger.Info("received get monthly statements req")
+
+	credit, err := s.storage.GetCreditById(ctx, creditID)
+	if err == nil {
+		err = checkOwnership(ctx, credit)
+	}
+	if err != nil {
+		s.logger.Errorf("failed to get monthly statements:%s", err)
+		return nil, err
+	}
+
+	statements, err := s.storage.GetStatements(ctx, creditID)
+	if err != nil {
+		s.logger.Errorf("failed to get monthly statements:%s", err)
+		return nil, err
+	}
+
+	s.logger.Info("monthly statements got")
+
+	return statements, nil
+}
+
+// RunMonthlyStatements saves the statement of the month for every active credit.
+// Credits that already have the statement are skipped,so the job can be rerun until every credit has it
+func (s *Service) RunMonthlyStatements(ctx context.Context, month time.Time) (models.StatementReport, error) {
+	s.logger.Info("received run monthly statements req")
+
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	period := from.Format("2006-01")
+
+	if to.After(time.Now().UTC()) {
+		return models.StatementReport{}, fmt.Errorf("%w:month %s hasn't ended", models.ErrInvalidRequest, period)
+	}
+
+	credits, err := s.storage.GetActiveCredits(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get active credits:%s", err)
+		return models.StatementReport{}, err
+	}
+
+	creditIDs, err := s.storage.GetStatementCreditIDs(ctx, from)
+	if err != nil {
+		s.logger.Errorf("failed to get statements of %s:%s", period, err)
+		return models.StatementReport{}, err
+	}
+
+	done := make(map[string]bool, len(creditIDs))
+	for _, creditID := range creditIDs {
+		done[creditID] = true
+	}
+
+	report := models.StatementReport{Period: period}
+
+	for _, credit := range credits {
+		if !credit.IssuedAt.Before(to) || done[credit.ID] {
+			continue
+		}
+		report.Credits++
+
+		entries, err := s.storage.GetEntries(ctx, models.LedgerFilter{CreditID: credit.ID, To: to})
+		if err == nil {
+			statement := BuildStatement(credit, entries, from, to)
+			statement.ID = credit.ID + ":" + period
+			statement.CreatedAt = time.Now().UTC()
+
+			err = s.storage.SaveStatement(ctx, statement)
+		}
+		if err != nil {
+			s.logger.Errorf("failed to make statement of %s:%s", credit.ID, err)
+			report.Failed = append(report.Failed, credit.ID)
+			continue
+		}
+
+		report.Generated++
+	}
+
+	s.logger.Infof("monthly statements of %s made for %v credits", period, report.Generated)
+
+	return report, nil
+}
+
+// BuildStatement sums the postings of the credit before from into the opening balance and lists the ones in [from,to).
+// Daily interest accruals are only summed
+func BuildStatement(credit models.Credit, entries []models.JournalEntry, from, to time.Time) models.CreditStatement {
+	statement := models.CreditStatement{
+		CreditID: credit.ID,
+		UserID:   credit.UserID,
+		Currency: credit.Currency,
+		From:     from,
+		To:       to,
+	}
+
+	var balance models.DebtBalance
+
+	for _, entry := range entries {
+		if !entry.ValueDate.Before(to) {
+			continue
+		}
+
+		principal, interest, penalties := creditChanges(entry, credit.ID)
+		if principal == 0 && interest == 0 && penalties == 0 {
+			continue
+		}
+
+		if entry.ValueDate.Before(from) {
+			balance = addDebt(balance, principal, interest, penalties)
+			statement.OpeningBalance = balance
+			continue
+		}
+
+		balance = addDebt(balance, principal, interest, penalties)
+
+		switch entry.Type {
+		case models.EntryDisbursement:
+			statement.Disbursed += principal
+		case models.EntryRepayment:
+			statement.Payments -= principal + interest + penalties
+		case models.EntryAccrual:
+			statement.Interest += interest
+			continue
+		case models.EntryPenalty:
+			statement.Penalties += penalties
+		case models.EntryCapitalization: //долг переходит из процентов в основной,сумма не меняется
+		default:
+			statement.Adjustments += principal + interest + penalties
+		}
+
+		statement.Transactions = append(statement.Transactions, models.StatementTransaction{
+			EntryID:     entry.ID,
+			Type:        entry.Type,
+			ValueDate:   entry.ValueDate,
+			Description: entry.Description,
+			Principal:   principal,
+			Interest:    interest,
+			Penalties:   penalties,
+		})
+	}
+
+	statement.ClosingBalance = balance
+
+	return statement
+}
+
+// creditChanges is how the entry changes the debt of the credit on its accounts
+func creditChanges(entry models.JournalEntry, creditID string) (principal, interest, penalties int) {
+	for _, line := range entry.Lines {
+		if line.CreditID != creditID {
+			continue
+		}
+		switch line.Account {
+		case models.AccountLoan:
+			principal += line.Debit - line.Credit
+		case models.AccountInterestReceivable:
+			interest += line.Debit - line.Credit
+		case models.AccountPenaltyReceivable:
+			penalties += line.Debit - line.Credit
+		}
+	}
+	return principal, interest, penalties
+}
+
+func addDebt(balance models.DebtBalance, principal, interest, penalties int) models.DebtBalance {
+	balance.Principal += principal
+	balance.Interest += interest
+	balance.Penalties += penalties
+	balance.Total = balance.Principal + balance.Interest + balance.Penalties
+	return balance
+}
+
+// statementPeriod defaults to to the end of today and from to the start of the month of the last day
+func statementPeriod(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = truncateDay(time.Now().UTC()).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		last := to.AddDate(0, 0, -1)
+		from = time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("%w:'from' must not be after 'to'", models.ErrInvalidRequest)
+	}
+
+	return from, to, nil
+}
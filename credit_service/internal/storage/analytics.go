@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// outstanding is the remaining principal,credits created before it was tracked owe the whole amount
+var outstanding = bson.M{"$ifNull": bson.A{"$outstandingPrincipal", "$amount"}}
+
+type AnalyticsMongoDB struct {
+	creditCollection *mongo.Collection
+}
+
+func NewAnalyticsMongoDB(DB *mongo.Database, creditCollection string) *AnalyticsMongoDB {
+	return &AnalyticsMongoDB{
+		creditCollection: DB.Collection(creditCollection),
+	}
+}
+
+func (d *AnalyticsMongoDB) OutstandingByCurrency(ctx context.Context, filter models.AnalyticsFilter) ([]models.OutstandingByCurrency, error) {
+	pipeline := mongo.Pipeline{
+		matchStage(filter),
+		{{Key: "$group", Value: bson.M{
+			"_id":                  "$currency",
+			"count":                bson.M{"$sum": 1},
+			"outstandingPrincipal": bson.M{"$sum": outstanding},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var res []models.OutstandingByCurrency
+
+	if err := d.aggregate(ctx, pipeline, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *AnalyticsMongoDB) WeightedAverageRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.WeightedAverageRate, error) {
+	pipeline := mongo.Pipeline{
+		matchStage(filter),
+		{{Key: "$group", Value: bson.M{
+			"_id":                  "$currency",
+			"outstandingPrincipal": bson.M{"$sum": outstanding},
+			"weightedRateSum":      bson.M{"$sum": bson.M{"$multiply": bson.A{"$annualInterestRate", outstanding}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"outstandingPrincipal": 1,
+			"weightedAverageRate": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$outstandingPrincipal", 0}},
+				0,
+				bson.M{"$divide": bson.A{"$weightedRateSum", "$outstandingPrincipal"}},
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var res []models.WeightedAverageRate
+
+	if err := d.aggregate(ctx, pipeline, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *AnalyticsMongoDB) IssuedByMonth(ctx context.Context, filter models.AnalyticsFilter) ([]models.IssuedByMonth, error) {
+	pipeline := mongo.Pipeline{
+		matchStage(filter),
+		{{Key: "$match", Value: bson.M{"issuedAt": bson.M{"$type": "date"}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"month":    bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$issuedAt"}},
+				"currency": "$currency",
+			},
+			"count":  bson.M{"$sum": 1},
+			"volume": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"month":    "$_id.month",
+			"currency": "$_id.currency",
+			"count":    1,
+			"volume":   1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "month", Value: 1}, {Key: "currency", Value: 1}}}},
+	}
+
+	var res []models.IssuedByMonth
+
+	if err := d.aggregate(ctx, pipeline, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *AnalyticsMongoDB) MaturityProfile(ctx context.Context, filter models.AnalyticsFilter) ([]models.MaturityBucket, error) {
+	pipeline := mongo.Pipeline{
+		matchStage(filter),
+		{{Key: "$match", Value: bson.M{"maturesAt": bson.M{"$type": "date"}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"year":     bson.M{"$year": "$maturesAt"},
+				"quarter":  bson.M{"$ceil": bson.M{"$divide": bson.A{bson.M{"$month": "$maturesAt"}, 3}}},
+				"currency": "$currency",
+			},
+			"count":                bson.M{"$sum": 1},
+			"outstandingPrincipal": bson.M{"$sum": outstanding},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":                  0,
+			"year":                 "$_id.year",
+			"quarter":              "$_id.quarter",
+			"currency":             "$_id.currency",
+			"count":                1,
+			"outstandingPrincipal": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "year", Value: 1}, {Key: "quarter", Value: 1}, {Key: "currency", Value: 1}}}},
+	}
+
+	var res []models.MaturityBucket
+
+	if err := d.aggregate(ctx, pipeline, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ExposureByUser groups the outstanding principal by user and currency,the conversion to one currency is done by the service.
+// Co-borrowers are jointly liable,so each of them carries the whole outstanding principal of the credit
+func (d *AnalyticsMongoDB) ExposureByUser(ctx context.Context, filter models.AnalyticsFilter) ([]models.UserCurrencyExposure, error) {
+	pipeline := mongo.Pipeline{
+		matchStage(filter),
+		{{Key: "$project", Value: bson.M{
+			"currency":    1,
+			"outstanding": outstanding,
+			//основной заемщик есть и в userID,и в borrowers,$setUnion убирает повтор
+			"parties": bson.M{"$setUnion": bson.A{bson.A{"$userID"}, bson.M{"$ifNull": bson.A{"$borrowers.userID", bson.A{}}}}},
+		}}},
+		{{Key: "$unwind", Value: "$parties"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                  bson.M{"userID": "$parties", "currency": "$currency"},
+			"count":                bson.M{"$sum": 1},
+			"outstandingPrincipal": bson.M{"$sum": "$outstanding"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":                  0,
+			"userID":               "$_id.userID",
+			"currency":             "$_id.currency",
+			"count":                1,
+			"outstandingPrincipal": 1,
+		}}},
+	}
+
+	var res []models.UserCurrencyExposure
+
+	if err := d.aggregate(ctx, pipeline, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *AnalyticsMongoDB) aggregate(ctx context.Context, pipeline mongo.Pipeline, res interface{}) error {
+	cursor, err := d.creditCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("aggregate failed:%s", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, res); err != nil {
+		return fmt.Errorf("decode failed:%s", err)
+	}
+
+	return nil
+}
+
+func matchStage(filter models.AnalyticsFilter) bson.D {
+	match := issued(notDeleted(bson.M{}))
+
+	issuedAt := bson.M{}
+	if !filter.From.IsZero() {
+		issuedAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		issuedAt["$lt"] = filter.To
+	}
+	if len(issuedAt) > 0 {
+		match["issuedAt"] = issuedAt
+	}
+
+	return bson.D{{Key: "$match", Value: match}}
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bank/credit_service/internal/domain/models"
 	"context"
 	"errors"
 	"fmt"
@@ -31,7 +32,7 @@ func (d *ConsumerMongoDB) NewUserIDCollection(ctx context.Context, userID int64)
 		return nil
 	}
 
-	return fmt.Errorf("userID:%v already inserted into MongoDB", userID)
+	return fmt.Errorf("%w:%v", models.ErrUserAlreadyInserted, userID)
 }
 
 func IsUserIdNOTExist(ctx context.Context, userID int64, userIDCollection *mongo.Collection) bool {
@@ -24,12 +24,8 @@ func NewAuthMongoDB(DB *mongo.Database, creditCollection, userIDCollection strin
 }
 
 func (d *AuthMongoDB) CreateCredit(ctx context.Context, credit models.Credit) (models.Credit, error) {
-	if d.IsCreditExist(ctx, credit.UserID, credit.Amount, credit.Term, credit.Currency, credit.AnnualInterestRate) {
-		return models.Credit{}, errors.New("you already took this credit")
-	}
-
-	if IsUserIdNOTExist(ctx, credit.UserID, d.userIDCollection) {
-		return models.Credit{}, fmt.Errorf("provided userID:%v doesn't exist", credit.UserID)
+	if err := d.CheckNewCredit(ctx, credit); err != nil {
+		return models.Credit{}, err
 	}
 
 	res, err := d.creditCollection.InsertOne(ctx, credit)
@@ -49,7 +45,7 @@ func (d *AuthMongoDB) CreateCredit(ctx context.Context, credit models.Credit) (m
 
 func (d *AuthMongoDB) GetCredits(ctx context.Context) ([]models.Credit, error) {
 	//получаем все доки в коллекции.
-	res, err := d.creditCollection.Find(ctx, bson.M{}) //в bson.M{} хранятся поля,которые мы хотим получить из коллекции
+	res, err := d.creditCollection.Find(ctx, notDeleted(bson.M{})) //в bson.M{} хранятся поля,которые мы хотим получить из коллекции
 	if err != nil {
 		return []models.Credit{}, fmt.Errorf("find failed:%s", err)
 	}
@@ -68,11 +64,11 @@ func (d *AuthMongoDB) GetCredits(ctx context.Context) ([]models.Credit, error) {
 	}
 
 	if res.Err() != nil {
-		return nil, fmt.Errorf("failed to find credits:%s", err.Error())
+		return nil, fmt.Errorf("failed to find credits:%s", res.Err())
 	}
 
 	if len(credits) == 0 {
-		return nil, errors.New("no credits found")
+		return nil, models.ErrCreditsNotFound
 	}
 
 	return credits, nil
@@ -81,18 +77,18 @@ func (d *AuthMongoDB) GetCredits(ctx context.Context) ([]models.Credit, error) {
 func (d *AuthMongoDB) GetCreditById(ctx context.Context, id string) (credit models.Credit, err error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return credit, fmt.Errorf("failed to conver string to ObjectID:%s", err)
+		return credit, fmt.Errorf("%w:%s", models.ErrInvalidID, err)
 	}
 
-	query := bson.M{"_id": objectID} //ObjectID используется в кач.значения поля _id
+	query := notDeleted(bson.M{"_id": objectID}) //ObjectID используется в кач.значения поля _id
 
 	res := d.creditCollection.FindOne(ctx, query)
 
 	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
-		return credit, fmt.Errorf("no credit found with provided ID:%s", id)
+		return credit, fmt.Errorf("%w:%s", models.ErrCreditNotFound, id)
 	}
 	if res.Err() != nil {
-		return credit, fmt.Errorf("failed to find credit by id:%s", err.Error())
+		return credit, fmt.Errorf("failed to find credit by id:%s", res.Err())
 	}
 
 	if err = res.Decode(&credit); err != nil {
@@ -102,8 +98,9 @@ func (d *AuthMongoDB) GetCreditById(ctx context.Context, id string) (credit mode
 	return credit, nil
 }
 
+// GetCreditsByUserId returns credits where the user is the primary borrower or a co-borrower
 func (d *AuthMongoDB) GetCreditsByUserId(ctx context.Context, userID int64) ([]models.Credit, error) {
-	query := bson.M{"userID": userID}
+	query := notDeleted(borrower(userID))
 
 	res, err := d.creditCollection.Find(ctx, query)
 	if err != nil {
@@ -125,11 +122,11 @@ func (d *AuthMongoDB) GetCreditsByUserId(ctx context.Context, userID int64) ([]m
 	}
 
 	if res.Err() != nil {
-		return nil, fmt.Errorf("failed to find credits by userID:%s", err.Error())
+		return nil, fmt.Errorf("failed to find credits by userID:%s", res.Err())
 	}
 
 	if len(credits) == 0 {
-		return nil, errors.New("no credits found for provided userID")
+		return nil, models.ErrUserCreditsNotFound
 	}
 
 	return credits, nil
@@ -138,30 +135,50 @@ func (d *AuthMongoDB) GetCreditsByUserId(ctx context.Context, userID int64) ([]m
 func (d *AuthMongoDB) UpdateCredit(ctx context.Context, credit models.Credit) (updatedCredit models.Credit, err error) {
 	objectID, err := primitive.ObjectIDFromHex(credit.ID)
 	if err != nil {
-		return updatedCredit, fmt.Errorf("failed to conver string to ObjectID:%s", err)
+		return updatedCredit, fmt.Errorf("%w:%s", models.ErrInvalidID, err)
 	}
 
-	query := bson.M{"_id": objectID}
+	query := notDeleted(bson.M{"_id": objectID})
 
 	update := bson.M{
 		"$set": bson.M{ //поля,которые нужно обновить
-			"amount":             credit.Amount,
-			"currency":           credit.Currency,
-			"annualInterestRate": credit.AnnualInterestRate,
-			"term":               credit.Term,
-			"dateOfIssue":        credit.DateOfIssue,
-			"maturityDate":       credit.MaturityDate,
-			"monthlyPayment":     credit.MonthlyPayment,
+			"amount":               credit.Amount,
+			"currency":             credit.Currency,
+			"annualInterestRate":   credit.AnnualInterestRate,
+			"term":                 credit.Term,
+			"dateOfIssue":          credit.DateOfIssue,
+			"maturityDate":         credit.MaturityDate,
+			"monthlyPayment":       credit.MonthlyPayment,
+			"scheme":               credit.Scheme,
+			"fees":                 credit.Fees,
+			"graceMonths":          credit.GraceMonths,
+			"interestOnlyMonths":   credit.InterestOnlyMonths,
+			"apr":                  credit.APR,
+			"issuedAt":             credit.IssuedAt,
+			"maturesAt":            credit.MaturesAt,
+			"outstandingPrincipal": credit.OutstandingPrincipal,
+			"borrowers":            credit.Borrowers,
+			"collateral":           credit.Collateral,
+			"guarantors":           credit.Guarantors,
+			"ltv":                  credit.LTV,
+			"rateIndex":            credit.RateIndex,
+			"rateMargin":           credit.RateMargin,
+			"rateResetMonths":      credit.RateResetMonths,
+			"nextRateReset":        credit.NextRateReset,
+			"rateHistory":          credit.RateHistory,
+			"schedule":             credit.Schedule,
+			"approval":             credit.Approval,
 		},
 	}
+	pushOutbox(update, credit.Outbox) //событие пишется тем же запросом,что и изменение
 
 	res := d.creditCollection.FindOneAndUpdate(ctx, query, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
 
 	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
-		return updatedCredit, fmt.Errorf("no credit found with provided ID:%s", credit.ID)
+		return updatedCredit, fmt.Errorf("%w:%s", models.ErrCreditNotFound, credit.ID)
 	}
 	if res.Err() != nil {
-		return models.Credit{}, fmt.Errorf("failed to update credit:%s", err)
+		return models.Credit{}, fmt.Errorf("failed to update credit:%s", res.Err())
 	}
 
 	if err = res.Decode(&updatedCredit); err != nil {
@@ -171,37 +188,265 @@ func (d *AuthMongoDB) UpdateCredit(ctx context.Context, credit models.Credit) (u
 	return updatedCredit, nil
 }
 
-func (d *AuthMongoDB) DeleteCredit(ctx context.Context, id string) error {
-	ObjectID, err := primitive.ObjectIDFromHex(id)
+// UpdateCreditBalance saves the outstanding principal and status of the credit after a posting and its outbox events
+func (d *AuthMongoDB) UpdateCreditBalance(ctx context.Context, credit models.Credit) error {
+	objectID, err := primitive.ObjectIDFromHex(credit.ID)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	query := notDeleted(bson.M{"_id": objectID})
+
+	update := bson.M{"$set": bson.M{"outstandingPrincipal": credit.OutstandingPrincipal, "status": credit.Status}}
+	pushOutbox(update, credit.Outbox)
+
+	res, err := d.creditCollection.UpdateOne(ctx, query, update)
 	if err != nil {
-		return fmt.Errorf("failed to conver string to ObjectID:%s", err)
+		return fmt.Errorf("failed to update credit balance:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditNotFound, credit.ID)
 	}
 
-	query := bson.M{"_id": ObjectID}
+	return nil
+}
 
-	res, err := d.creditCollection.DeleteOne(ctx, query)
+// DeleteCredit soft deletes the credit with its DeletedAt and Status and saves its outbox events
+func (d *AuthMongoDB) DeleteCredit(ctx context.Context, credit models.Credit) error {
+	ObjectID, err := primitive.ObjectIDFromHex(credit.ID)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	query := notDeleted(bson.M{"_id": ObjectID})
+
+	update := bson.M{"$set": bson.M{"deletedAt": credit.DeletedAt, "status": credit.Status}} //soft delete,документ остается для истории
+	pushOutbox(update, credit.Outbox)
+
+	res, err := d.creditCollection.UpdateOne(ctx, query, update)
 	if err != nil {
 		return fmt.Errorf("failed to delete credit:%s", err)
 	}
 
-	if res.DeletedCount == 0 {
-		return fmt.Errorf("no credit found with provided ID:%s", id)
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditNotFound, credit.ID)
 	}
 
 	return nil
 }
 
 func (d *AuthMongoDB) IsCreditExist(ctx context.Context, userID int64, amount, term int, currency string, annualInterestRate float64) bool {
-	query := bson.M{
+	query := notDeleted(bson.M{
 		"userID":             userID,
 		"amount":             amount,
 		"term":               term,
 		"currency":           currency,
 		"annualInterestRate": annualInterestRate,
-	}
+		"status":             bson.M{"$ne": models.CreditStatusRejected}, //отклоненную заявку можно подать снова
+	})
 
 	res := d.creditCollection.FindOne(ctx, query)
 
 	//если ошибка будет mongo.ErrNoDocuments выведет !true=false.Если ошибка будет отличаться от mongo.ErrNoDocuments-выведет !false=true
 	return !errors.Is(res.Err(), mongo.ErrNoDocuments)
 }
+
+// CheckNewCredit returns the error CreateCredit would return for the credit without inserting it
+func (d *AuthMongoDB) CheckNewCredit(ctx context.Context, credit models.Credit) error {
+	if d.IsCreditExist(ctx, credit.UserID, credit.Amount, credit.Term, credit.Currency, credit.AnnualInterestRate) {
+		return models.ErrCreditAlreadyExists
+	}
+
+	if IsUserIdNOTExist(ctx, credit.UserID, d.userIDCollection) {
+		return fmt.Errorf("%w:%v", models.ErrUserNotFound, credit.UserID)
+	}
+
+	return nil
+}
+
+// ExportCredits passes every credit,deleted ones too,to fn in the order of ids without loading them all
+func (d *AuthMongoDB) ExportCredits(ctx context.Context, fn func(models.Credit) error) error {
+	res, err := d.creditCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("find failed:%s", err)
+	}
+	defer res.Close(ctx)
+
+	for res.Next(ctx) {
+		var credit models.Credit
+
+		if err = res.Decode(&credit); err != nil {
+			return fmt.Errorf("decode failed:%s", err)
+		}
+
+		if err = fn(credit); err != nil {
+			return err
+		}
+	}
+
+	if res.Err() != nil {
+		return fmt.Errorf("failed to export credits:%s", res.Err())
+	}
+
+	return nil
+}
+
+func (d *AuthMongoDB) IsUserExist(ctx context.Context, userID int64) bool {
+	return !IsUserIdNOTExist(ctx, userID, d.userIDCollection)
+}
+
+// SetRefinancedBy links the credit to the credit that refinanced it
+func (d *AuthMongoDB) SetRefinancedBy(ctx context.Context, creditID, newCreditID string) error {
+	objectID, err := primitive.ObjectIDFromHex(creditID)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	res, err := d.creditCollection.UpdateOne(ctx, notDeleted(bson.M{"_id": objectID}), bson.M{"$set": bson.M{"refinancedBy": newCreditID}})
+	if err != nil {
+		return fmt.Errorf("failed to set refinancedBy:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditNotFound, creditID)
+	}
+
+	return nil
+}
+
+// GetActiveCredits returns credits that are neither closed nor cancelled,credits created before statuses are active
+func (d *AuthMongoDB) GetActiveCredits(ctx context.Context) ([]models.Credit, error) {
+	query := notDeleted(bson.M{"status": bson.M{"$in": bson.A{models.CreditStatusActive, nil}}})
+
+	res, err := d.creditCollection.Find(ctx, query, options.Find().SetProjection(bson.M{"outbox": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var credits []models.Credit
+
+	if err = res.All(ctx, &credits); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return credits, nil
+}
+
+// GetPendingApprovals returns credits waiting for approval,the oldest first
+func (d *AuthMongoDB) GetPendingApprovals(ctx context.Context) ([]models.Credit, error) {
+	query := notDeleted(bson.M{"status": models.CreditStatusPending})
+
+	opts := options.Find().SetProjection(bson.M{"outbox": 0}).SetSort(bson.M{"approval.requestedAt": 1})
+
+	res, err := d.creditCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	credits := []models.Credit{}
+
+	if err = res.All(ctx, &credits); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return credits, nil
+}
+
+// UpdateCreditApproval saves the approval decisions and status of the credit and its outbox events
+func (d *AuthMongoDB) UpdateCreditApproval(ctx context.Context, credit models.Credit) error {
+	objectID, err := primitive.ObjectIDFromHex(credit.ID)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	//решение принимается только по кредиту,который все еще ждет одобрения
+	query := notDeleted(bson.M{"_id": objectID, "status": models.CreditStatusPending})
+
+	update := bson.M{"$set": bson.M{"approval": credit.Approval, "status": credit.Status}}
+	pushOutbox(update, credit.Outbox)
+
+	res, err := d.creditCollection.UpdateOne(ctx, query, update)
+	if err != nil {
+		return fmt.Errorf("failed to update credit approval:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditNotPending, credit.ID)
+	}
+
+	return nil
+}
+
+// borrower matches credits of the user,credits created before co-borrowers have only userID
+func borrower(userID int64) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"userID": userID}, bson.M{"borrowers.userID": userID}}}
+}
+
+// issued adds to the query a filter that skips credits that were never disbursed:waiting for approval or rejected
+func issued(query bson.M) bson.M {
+	query["status"] = bson.M{"$nin": bson.A{models.CreditStatusPending, models.CreditStatusRejected}}
+	return query
+}
+
+// notDeleted adds to the query a filter that skips soft deleted credits
+func notDeleted(query bson.M) bson.M {
+	query["deletedAt"] = bson.M{"$exists": false}
+	return query
+}
+
+// GetTotalsByCurrency sums the outstanding principal of open credits per currency,userID=0 means all users
+func (d *AuthMongoDB) GetTotalsByCurrency(ctx context.Context, userID int64) ([]models.CurrencyTotal, error) {
+	match := notDeleted(bson.M{})
+	if userID != 0 {
+		match = notDeleted(borrower(userID))
+	}
+	match["status"] = bson.M{"$nin": bson.A{models.CreditStatusPending, models.CreditStatusRejected, models.CreditStatusClosed, models.CreditStatusCancelled}}
+	match["refinancedBy"] = bson.M{"$exists": false} //долг рефинансированного кредита уже учтен в новом
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$currency",
+			"count":  bson.M{"$sum": 1},
+			"amount": bson.M{"$sum": outstanding},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	res, err := d.creditCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var groups []struct {
+		Currency string `bson:"_id"`
+		Count    int    `bson:"count"`
+		Amount   int    `bson:"amount"`
+	}
+
+	if err = res.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(groups) == 0 {
+		return nil, models.ErrCreditsNotFound
+	}
+
+	totals := make([]models.CurrencyTotal, 0, len(groups))
+	for _, group := range groups {
+		totals = append(totals, models.CurrencyTotal{
+			Currency: group.Currency,
+			Count:    group.Count,
+			Amount:   group.Amount,
+		})
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type CreditLineMongoDB struct {
+	creditLineCollection *mongo.Collection
+	userIDCollection     *mongo.Collection
+}
+
+func NewCreditLineMongoDB(DB *mongo.Database, creditLineCollection, userIDCollection string) *CreditLineMongoDB {
+	return &CreditLineMongoDB{
+		creditLineCollection: DB.Collection(creditLineCollection),
+		userIDCollection:     DB.Collection(userIDCollection),
+	}
+}
+
+func (d *CreditLineMongoDB) CreateCreditLine(ctx context.Context, line models.CreditLine) (models.CreditLine, error) {
+	if IsUserIdNOTExist(ctx, line.UserID, d.userIDCollection) {
+		return models.CreditLine{}, fmt.Errorf("%w:%v", models.ErrUserNotFound, line.UserID)
+	}
+
+	res, err := d.creditLineCollection.InsertOne(ctx, line)
+	if err != nil {
+		return models.CreditLine{}, fmt.Errorf("insert one failed:%s", err)
+	}
+
+	objectID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return models.CreditLine{}, errors.New("failed to get ObjectID")
+	}
+	line.ID = objectID.Hex()
+
+	return line, nil
+}
+
+func (d *CreditLineMongoDB) GetCreditLineById(ctx context.Context, id string) (line models.CreditLine, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return line, fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	res := d.creditLineCollection.FindOne(ctx, bson.M{"_id": objectID})
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return line, fmt.Errorf("%w:%s", models.ErrCreditLineNotFound, id)
+	}
+	if res.Err() != nil {
+		return line, fmt.Errorf("failed to find credit line by id:%s", res.Err())
+	}
+
+	if err = res.Decode(&line); err != nil {
+		return line, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return line, nil
+}
+
+func (d *CreditLineMongoDB) GetActiveCreditLines(ctx context.Context) ([]models.CreditLine, error) {
+	res, err := d.creditLineCollection.Find(ctx, bson.M{"status": models.CreditLineStatusActive})
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var lines []models.CreditLine
+
+	if err = res.All(ctx, &lines); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return lines, nil
+}
+
+// ReserveDrawdown increases the drawn amount only if it stays within the limit,so parallel drawdowns can't exceed it
+func (d *CreditLineMongoDB) ReserveDrawdown(ctx context.Context, id string, amount int) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	query := bson.M{
+		"_id":    objectID,
+		"status": models.CreditLineStatusActive,
+		"$expr":  bson.M{"$lte": bson.A{bson.M{"$add": bson.A{"$drawn", amount}}, "$limit"}},
+	}
+
+	res, err := d.creditLineCollection.UpdateOne(ctx, query, bson.M{"$inc": bson.M{"drawn": amount, "version": 1}})
+	if err != nil {
+		return fmt.Errorf("failed to reserve drawdown:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%v", models.ErrCreditLimitExceeded, amount)
+	}
+
+	return nil
+}
+
+// ChangeDrawn adds delta to the drawn amount,repayments restore the limit with a negative delta.
+// The version always changes,so transactions that change the same line conflict even with a zero delta
+func (d *CreditLineMongoDB) ChangeDrawn(ctx context.Context, id string, delta int) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	res, err := d.creditLineCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$inc": bson.M{"drawn": delta, "version": 1}})
+	if err != nil {
+		return fmt.Errorf("failed to change drawn amount:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditLineNotFound, id)
+	}
+
+	return nil
+}
+
+// CloseCreditLine closes the line only when nothing is drawn
+func (d *CreditLineMongoDB) CloseCreditLine(ctx context.Context, id string, closedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	query := bson.M{"_id": objectID, "status": models.CreditLineStatusActive, "drawn": 0}
+	update := bson.M{"$set": bson.M{"status": models.CreditLineStatusClosed, "closedAt": closedAt}}
+
+	res, err := d.creditLineCollection.UpdateOne(ctx, query, update)
+	if err != nil {
+		return fmt.Errorf("failed to close credit line:%s", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrCreditLineHasDebt, id)
+	}
+
+	return nil
+}
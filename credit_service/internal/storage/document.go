@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DocumentMongoDB struct {
+	documentCollection *mongo.Collection
+}
+
+func NewDocumentMongoDB(DB *mongo.Database, documentCollection string) *DocumentMongoDB {
+	return &DocumentMongoDB{
+		documentCollection: DB.Collection(documentCollection),
+	}
+}
+
+// SaveDocument only inserts documents,a changed document is a new document
+func (d *DocumentMongoDB) SaveDocument(ctx context.Context, document models.Document) error {
+	if _, err := d.documentCollection.InsertOne(ctx, document); err != nil {
+		return fmt.Errorf("insert one failed:%s", err)
+	}
+
+	return nil
+}
+
+// GetDocuments returns the documents of the credit without their content
+func (d *DocumentMongoDB) GetDocuments(ctx context.Context, creditID string) ([]models.Document, error) {
+	query := bson.M{"creditID": creditID}
+
+	opts := options.Find().SetProjection(bson.M{"content": 0}).SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "type", Value: 1}})
+
+	res, err := d.documentCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var documents []models.Document
+
+	if err = res.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("%w:%s", models.ErrDocumentsNotFound, creditID)
+	}
+
+	return documents, nil
+}
+
+func (d *DocumentMongoDB) GetDocument(ctx context.Context, creditID, documentID string) (document models.Document, err error) {
+	res := d.documentCollection.FindOne(ctx, bson.M{"_id": documentID, "creditID": creditID})
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return document, fmt.Errorf("%w:%s", models.ErrDocumentNotFound, documentID)
+	}
+	if res.Err() != nil {
+		return document, fmt.Errorf("failed to find document:%s", res.Err())
+	}
+
+	if err = res.Decode(&document); err != nil {
+		return document, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return document, nil
+}
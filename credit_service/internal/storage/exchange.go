@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExchangeRateMongoDB struct {
+	exchangeRateCollection *mongo.Collection
+}
+
+func NewExchangeRateMongoDB(DB *mongo.Database, exchangeRateCollection string) *ExchangeRateMongoDB {
+	return &ExchangeRateMongoDB{
+		exchangeRateCollection: DB.Collection(exchangeRateCollection),
+	}
+}
+
+func (d *ExchangeRateMongoDB) SaveExchangeRate(ctx context.Context, rate models.ExchangeRate) error {
+	if _, err := d.exchangeRateCollection.InsertOne(ctx, rate); err != nil {
+		return fmt.Errorf("failed to insert exchange rate:%s", err)
+	}
+
+	return nil
+}
+
+func (d *ExchangeRateMongoDB) GetExchangeRate(ctx context.Context, currency, base, asOf string) (rate models.ExchangeRate, err error) {
+	query := bson.M{"currency": currency, "base": base, "asOf": asOf}
+
+	res := d.exchangeRateCollection.FindOne(ctx, query)
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return rate, fmt.Errorf("%w for %s/%s on %s", models.ErrExchangeRateNotFound, currency, base, asOf)
+	}
+	if res.Err() != nil {
+		return rate, fmt.Errorf("failed to find exchange rate:%s", res.Err())
+	}
+
+	if err = res.Decode(&rate); err != nil {
+		return rate, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return rate, nil
+}
+
+func (d *ExchangeRateMongoDB) GetExchangeRateHistory(ctx context.Context, currency, base string) ([]models.ExchangeRate, error) {
+	query := bson.M{"currency": currency, "base": base}
+
+	res, err := d.exchangeRateCollection.Find(ctx, query, options.Find().SetSort(bson.M{"asOf": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find exchange rates:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var rates []models.ExchangeRate
+
+	if err = res.All(ctx, &rates); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(rates) == 0 {
+		return nil, models.ErrExchangeRatesNotFound
+	}
+
+	return rates, nil
+}
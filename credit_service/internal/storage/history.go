@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HistoryMongoDB struct {
+	historyCollection *mongo.Collection
+}
+
+func NewHistoryMongoDB(DB *mongo.Database, historyCollection string) *HistoryMongoDB {
+	return &HistoryMongoDB{
+		historyCollection: DB.Collection(historyCollection),
+	}
+}
+
+// AddCreditHistory only inserts records: history is append-only and is never updated or deleted
+func (d *HistoryMongoDB) AddCreditHistory(ctx context.Context, record models.CreditHistory) error {
+	if _, err := d.historyCollection.InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("failed to insert credit history:%s", err)
+	}
+
+	return nil
+}
+
+func (d *HistoryMongoDB) GetCreditHistory(ctx context.Context, creditID string) ([]models.CreditHistory, error) {
+	query := bson.M{"creditID": creditID}
+
+	res, err := d.historyCollection.Find(ctx, query, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credit history:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var history []models.CreditHistory
+
+	if err = res.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(history) == 0 {
+		return nil, models.ErrHistoryNotFound
+	}
+
+	return history, nil
+}
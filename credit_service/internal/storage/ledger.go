@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// LedgerMongoDB is the journal of double-entry postings.It's append-only,mistakes are fixed with reversal entries
+type LedgerMongoDB struct {
+	ledgerCollection *mongo.Collection
+}
+
+func NewLedgerMongoDB(DB *mongo.Database, ledgerCollection string) *LedgerMongoDB {
+	return &LedgerMongoDB{
+		ledgerCollection: DB.Collection(ledgerCollection),
+	}
+}
+
+// PostEntry inserts the entry,the entry ID is the _id so the same posting can't be made twice
+func (d *LedgerMongoDB) PostEntry(ctx context.Context, entry models.JournalEntry) error {
+	_, err := d.ledgerCollection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w:%s", models.ErrEntryAlreadyPosted, entry.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post journal entry:%s", err)
+	}
+
+	return nil
+}
+
+// GetEntries returns entries in the order of their value date,with Account set only entries touching the account
+func (d *LedgerMongoDB) GetEntries(ctx context.Context, filter models.LedgerFilter) ([]models.JournalEntry, error) {
+	query := bson.M{}
+
+	if filter.CreditID != "" {
+		query["creditID"] = filter.CreditID
+	}
+	if filter.Currency != "" {
+		query["currency"] = filter.Currency
+	}
+	if filter.Account != "" {
+		query["lines.account"] = filter.Account
+	}
+
+	valueDate := bson.M{}
+	if !filter.From.IsZero() {
+		valueDate["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		valueDate["$lt"] = filter.To
+	}
+	if len(valueDate) != 0 {
+		query["valueDate"] = valueDate
+	}
+
+	res, err := d.ledgerCollection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "valueDate", Value: 1}, {Key: "postedAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find journal entries:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var entries []models.JournalEntry
+
+	if err = res.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return entries, nil
+}
+
+// GetCreditBalances returns debit minus credit of every account of the credit
+func (d *LedgerMongoDB) GetCreditBalances(ctx context.Context, creditID string) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"creditID": creditID}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$match", Value: bson.M{"lines.creditID": creditID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$lines.account",
+			"balance": bson.M{"$sum": bson.M{"$subtract": bson.A{"$lines.debit", "$lines.credit"}}},
+		}}},
+	}
+
+	var groups []struct {
+		Account string `bson:"_id"`
+		Balance int    `bson:"balance"`
+	}
+
+	if err := d.aggregate(ctx, pipeline, &groups); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]int, len(groups))
+	for _, group := range groups {
+		balances[group.Account] = group.Balance
+	}
+
+	return balances, nil
+}
+
+// GetAccountBalances sums the turnover of every account per currency for entries before asOf
+func (d *LedgerMongoDB) GetAccountBalances(ctx context.Context, asOf time.Time) ([]models.AccountBalance, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"valueDate": bson.M{"$lt": asOf}}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"account": "$lines.account", "currency": "$currency"},
+			"debit":  bson.M{"$sum": "$lines.debit"},
+			"credit": bson.M{"$sum": "$lines.credit"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"account":  "$_id.account",
+			"currency": "$_id.currency",
+			"debit":    1,
+			"credit":   1,
+			"balance":  bson.M{"$subtract": bson.A{"$debit", "$credit"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "currency", Value: 1}, {Key: "account", Value: 1}}}},
+	}
+
+	var balances []models.AccountBalance
+
+	if err := d.aggregate(ctx, pipeline, &balances); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// GetUnbalancedEntries returns IDs of stored entries whose debits don't equal credits
+func (d *LedgerMongoDB) GetUnbalancedEntries(ctx context.Context) ([]string, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"debit":  bson.M{"$sum": "$lines.debit"},
+			"credit": bson.M{"$sum": "$lines.credit"},
+			"lines":  bson.M{"$size": bson.M{"$ifNull": bson.A{"$lines", bson.A{}}}},
+		}}},
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"$expr": bson.M{"$ne": bson.A{"$debit", "$credit"}}},
+			bson.M{"lines": 0},
+		}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var entries []struct {
+		ID string `bson:"_id"`
+	}
+
+	if err := d.aggregate(ctx, pipeline, &entries); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+
+	return ids, nil
+}
+
+func (d *LedgerMongoDB) aggregate(ctx context.Context, pipeline mongo.Pipeline, res interface{}) error {
+	cursor, err := d.ledgerCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("aggregate failed:%s", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, res); err != nil {
+		return fmt.Errorf("decode failed:%s", err)
+	}
+
+	return nil
+}
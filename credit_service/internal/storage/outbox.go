@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxMongoDB reads the events that credits keep in their outbox field until they are published
+type OutboxMongoDB struct {
+	creditCollection *mongo.Collection
+}
+
+func NewOutboxMongoDB(DB *mongo.Database, creditCollection string) *OutboxMongoDB {
+	return &OutboxMongoDB{creditCollection: DB.Collection(creditCollection)}
+}
+
+// CreateOutboxIndex adds the partial index that lets GetPendingEvents skip credits with an empty outbox
+func (d *OutboxMongoDB) CreateOutboxIndex(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "outbox.0", Value: 1}},
+		Options: options.Index().
+			SetName("pending_outbox").
+			SetPartialFilterExpression(bson.M{"outbox.0": bson.M{"$exists": true}}), //в индексе только кредиты с неотправленными событиями
+	}
+
+	if _, err := d.creditCollection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create outbox index:%s", err)
+	}
+
+	return nil
+}
+
+// GetPendingEvents returns unpublished events of at most limit credits,events of one credit keep their order
+func (d *OutboxMongoDB) GetPendingEvents(ctx context.Context, limit int) ([]models.CreditEvent, error) {
+	query := bson.M{"outbox.0": bson.M{"$exists": true}} //удаленные кредиты тоже,их события еще не отправлены
+
+	opts := options.Find().
+		SetProjection(bson.M{"outbox": 1}).
+		SetLimit(int64(limit))
+
+	res, err := d.creditCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var events []models.CreditEvent
+
+	for res.Next(ctx) {
+		var doc struct {
+			ID     primitive.ObjectID   `bson:"_id"`
+			Outbox []models.CreditEvent `bson:"outbox"`
+		}
+
+		if err = res.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("decode failed:%s", err)
+		}
+
+		for _, event := range doc.Outbox {
+			event.CreditID = doc.ID.Hex() //при создании id кредита еще неизвестен
+			events = append(events, event)
+		}
+	}
+
+	if res.Err() != nil {
+		return nil, fmt.Errorf("failed to find pending events:%s", res.Err())
+	}
+
+	return events, nil
+}
+
+// DeletePublishedEvents removes the events from the credit outbox after kafka accepted them
+func (d *OutboxMongoDB) DeletePublishedEvents(ctx context.Context, creditID string, eventIDs []string) error {
+	objectID, err := primitive.ObjectIDFromHex(creditID)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	update := bson.M{"$pull": bson.M{"outbox": bson.M{"id": bson.M{"$in": eventIDs}}}}
+
+	if _, err = d.creditCollection.UpdateOne(ctx, bson.M{"_id": objectID}, update); err != nil {
+		return fmt.Errorf("failed to delete published event:%s", err)
+	}
+
+	return nil
+}
+
+// pushOutbox adds events to the credit outbox in the same update as the change itself
+func pushOutbox(update bson.M, events []models.CreditEvent) {
+	if len(events) == 0 {
+		return
+	}
+	update["$push"] = bson.M{"outbox": bson.M{"$each": events}}
+}
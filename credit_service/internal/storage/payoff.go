@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PayoffQuoteMongoDB struct {
+	payoffQuoteCollection *mongo.Collection
+}
+
+func NewPayoffQuoteMongoDB(DB *mongo.Database, payoffQuoteCollection string) *PayoffQuoteMongoDB {
+	return &PayoffQuoteMongoDB{
+		payoffQuoteCollection: DB.Collection(payoffQuoteCollection),
+	}
+}
+
+func (d *PayoffQuoteMongoDB) SavePayoffQuote(ctx context.Context, quote models.PayoffQuote) error {
+	if _, err := d.payoffQuoteCollection.InsertOne(ctx, quote); err != nil {
+		return fmt.Errorf("insert one failed:%s", err)
+	}
+
+	return nil
+}
+
+func (d *PayoffQuoteMongoDB) GetPayoffQuote(ctx context.Context, id string) (quote models.PayoffQuote, err error) {
+	res := d.payoffQuoteCollection.FindOne(ctx, bson.M{"_id": id})
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return quote, fmt.Errorf("%w:%s", models.ErrPayoffQuoteNotFound, id)
+	}
+	if res.Err() != nil {
+		return quote, fmt.Errorf("failed to find payoff quote by id:%s", res.Err())
+	}
+
+	if err = res.Decode(&quote); err != nil {
+		return quote, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProductMongoDB struct {
+	productCollection *mongo.Collection
+}
+
+func NewProductMongoDB(DB *mongo.Database, productCollection string) *ProductMongoDB {
+	return &ProductMongoDB{
+		productCollection: DB.Collection(productCollection),
+	}
+}
+
+func (d *ProductMongoDB) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	res, err := d.productCollection.InsertOne(ctx, product)
+	if err != nil {
+		return models.Product{}, fmt.Errorf("insert one failed:%s", err)
+	}
+
+	objectID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return models.Product{}, errors.New("failed to get ObjectID")
+	}
+	product.ID = objectID.Hex()
+
+	return product, nil
+}
+
+func (d *ProductMongoDB) GetProducts(ctx context.Context) ([]models.Product, error) {
+	res, err := d.productCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var products []models.Product
+
+	if err = res.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(products) == 0 {
+		return nil, models.ErrProductsNotFound
+	}
+
+	return products, nil
+}
+
+func (d *ProductMongoDB) GetProductById(ctx context.Context, id string) (product models.Product, err error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return product, fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	res := d.productCollection.FindOne(ctx, bson.M{"_id": objectID})
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return product, fmt.Errorf("%w:%s", models.ErrProductNotFound, id)
+	}
+	if res.Err() != nil {
+		return product, fmt.Errorf("failed to find product by id:%s", res.Err())
+	}
+
+	if err = res.Decode(&product); err != nil {
+		return product, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return product, nil
+}
+
+func (d *ProductMongoDB) UpdateProduct(ctx context.Context, product models.Product) (updatedProduct models.Product, err error) {
+	objectID, err := primitive.ObjectIDFromHex(product.ID)
+	if err != nil {
+		return updatedProduct, fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	product.ID = "" //_id не обновляется
+
+	res := d.productCollection.FindOneAndReplace(ctx, bson.M{"_id": objectID}, product, options.FindOneAndReplace().SetReturnDocument(options.After))
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return updatedProduct, fmt.Errorf("%w:%s", models.ErrProductNotFound, objectID.Hex())
+	}
+	if res.Err() != nil {
+		return updatedProduct, fmt.Errorf("failed to update product:%s", res.Err())
+	}
+
+	if err = res.Decode(&updatedProduct); err != nil {
+		return updatedProduct, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return updatedProduct, nil
+}
+
+func (d *ProductMongoDB) DeleteProduct(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w:%s", models.ErrInvalidID, err)
+	}
+
+	res, err := d.productCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete product:%s", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("%w:%s", models.ErrProductNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RateIndexMongoDB struct {
+	rateIndexCollection *mongo.Collection
+}
+
+func NewRateIndexMongoDB(DB *mongo.Database, rateIndexCollection string) *RateIndexMongoDB {
+	return &RateIndexMongoDB{
+		rateIndexCollection: DB.Collection(rateIndexCollection),
+	}
+}
+
+// SaveIndexValue replaces the value of the index on the same date,so a mistyped value can be corrected
+func (d *RateIndexMongoDB) SaveIndexValue(ctx context.Context, value models.IndexValue) error {
+	query := bson.M{"index": value.Index, "date": value.Date}
+
+	if _, err := d.rateIndexCollection.ReplaceOne(ctx, query, value, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save index value:%s", err)
+	}
+
+	return nil
+}
+
+// GetIndexValue returns the value of the index in effect on the date,the latest one set on or before it
+func (d *RateIndexMongoDB) GetIndexValue(ctx context.Context, index, date string) (value models.IndexValue, err error) {
+	query := bson.M{"index": index, "date": bson.M{"$lte": date}}
+
+	res := d.rateIndexCollection.FindOne(ctx, query, options.FindOne().SetSort(bson.M{"date": -1}))
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return value, fmt.Errorf("%w for %s on %s", models.ErrIndexValueNotFound, index, date)
+	}
+	if res.Err() != nil {
+		return value, fmt.Errorf("failed to find index value:%s", res.Err())
+	}
+
+	if err = res.Decode(&value); err != nil {
+		return value, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return value, nil
+}
+
+func (d *RateIndexMongoDB) GetIndexValues(ctx context.Context, index string) ([]models.IndexValue, error) {
+	query := bson.M{"index": index}
+
+	res, err := d.rateIndexCollection.Find(ctx, query, options.Find().SetSort(bson.M{"date": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find index values:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var values []models.IndexValue
+
+	if err = res.All(ctx, &values); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w:%s", models.ErrIndexValuesNotFound, index)
+	}
+
+	return values, nil
+}
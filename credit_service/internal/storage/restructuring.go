@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RestructuringMongoDB struct {
+	restructuringCollection *mongo.Collection
+}
+
+func NewRestructuringMongoDB(DB *mongo.Database, restructuringCollection string) *RestructuringMongoDB {
+	return &RestructuringMongoDB{
+		restructuringCollection: DB.Collection(restructuringCollection),
+	}
+}
+
+// SaveRestructuring only inserts records,like the credit history they are never changed
+func (d *RestructuringMongoDB) SaveRestructuring(ctx context.Context, restructuring models.Restructuring) error {
+	if _, err := d.restructuringCollection.InsertOne(ctx, restructuring); err != nil {
+		return fmt.Errorf("insert one failed:%s", err)
+	}
+
+	return nil
+}
+
+func (d *RestructuringMongoDB) GetRestructurings(ctx context.Context, creditID string) ([]models.Restructuring, error) {
+	query := bson.M{"creditID": creditID}
+
+	res, err := d.restructuringCollection.Find(ctx, query, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find restructurings:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var restructurings []models.Restructuring
+
+	if err = res.All(ctx, &restructurings); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(restructurings) == 0 {
+		return nil, fmt.Errorf("%w:%s", models.ErrRestructuringsNotFound, creditID)
+	}
+
+	return restructurings, nil
+}
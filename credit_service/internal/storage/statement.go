@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type StatementMongoDB struct {
+	statementCollection *mongo.Collection
+}
+
+func NewStatementMongoDB(DB *mongo.Database, statementCollection string) *StatementMongoDB {
+	return &StatementMongoDB{
+		statementCollection: DB.Collection(statementCollection),
+	}
+}
+
+// SaveStatement replaces the statement with the same id,a rerun of the job makes the same statement
+func (d *StatementMongoDB) SaveStatement(ctx context.Context, statement models.CreditStatement) error {
+	_, err := d.statementCollection.ReplaceOne(ctx, bson.M{"_id": statement.ID}, statement, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save statement:%s", err)
+	}
+
+	return nil
+}
+
+func (d *StatementMongoDB) GetStatements(ctx context.Context, creditID string) ([]models.CreditStatement, error) {
+	res, err := d.statementCollection.Find(ctx, bson.M{"creditID": creditID}, options.Find().SetSort(bson.M{"from": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find statements:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var statements []models.CreditStatement
+
+	if err = res.All(ctx, &statements); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("%w:%s", models.ErrStatementsNotFound, creditID)
+	}
+
+	return statements, nil
+}
+
+// GetStatementCreditIDs returns the credits that already have the statement starting from the date
+func (d *StatementMongoDB) GetStatementCreditIDs(ctx context.Context, from time.Time) ([]string, error) {
+	ids, err := d.statementCollection.Distinct(ctx, "creditID", bson.M{"from": from})
+	if err != nil {
+		return nil, fmt.Errorf("distinct failed:%s", err)
+	}
+
+	creditIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if creditID, ok := id.(string); ok {
+			creditIDs = append(creditIDs, creditID)
+		}
+	}
+
+	return creditIDs, nil
+}
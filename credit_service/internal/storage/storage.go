@@ -1,15 +1,44 @@
 package storage
 
-import "go.mongodb.org/mongo-driver/mongo"
+import (
+	"bank/credit_service/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+)
 
 type MongoDB struct {
 	*AuthMongoDB
 	*ConsumerMongoDB
+	*HistoryMongoDB
+	*ProductMongoDB
+	*ExchangeRateMongoDB
+	*AnalyticsMongoDB
+	*OutboxMongoDB
+	*LedgerMongoDB
+	*PayoffQuoteMongoDB
+	*CreditLineMongoDB
+	*RestructuringMongoDB
+	*RateIndexMongoDB
+	*DocumentMongoDB
+	*StatementMongoDB
+	*TxMongoDB
 }
 
-func NewStorage(DB *mongo.Database, creditCollection, userIDCollection string) *MongoDB {
+func NewStorage(DB *mongo.Database, cfg config.MongoDb) *MongoDB {
 	return &MongoDB{
-		AuthMongoDB:     NewAuthMongoDB(DB, creditCollection, userIDCollection),
-		ConsumerMongoDB: NewConsumerMongoDB(DB, userIDCollection),
+		AuthMongoDB:          NewAuthMongoDB(DB, cfg.CreditCollection, cfg.UserIDCollection),
+		ConsumerMongoDB:      NewConsumerMongoDB(DB, cfg.UserIDCollection),
+		HistoryMongoDB:       NewHistoryMongoDB(DB, cfg.HistoryCollection),
+		ProductMongoDB:       NewProductMongoDB(DB, cfg.ProductCollection),
+		ExchangeRateMongoDB:  NewExchangeRateMongoDB(DB, cfg.ExchangeRateCollection),
+		AnalyticsMongoDB:     NewAnalyticsMongoDB(DB, cfg.CreditCollection),
+		OutboxMongoDB:        NewOutboxMongoDB(DB, cfg.CreditCollection),
+		LedgerMongoDB:        NewLedgerMongoDB(DB, cfg.LedgerCollection),
+		PayoffQuoteMongoDB:   NewPayoffQuoteMongoDB(DB, cfg.PayoffQuoteCollection),
+		CreditLineMongoDB:    NewCreditLineMongoDB(DB, cfg.CreditLineCollection, cfg.UserIDCollection),
+		RestructuringMongoDB: NewRestructuringMongoDB(DB, cfg.RestructuringCollection),
+		RateIndexMongoDB:     NewRateIndexMongoDB(DB, cfg.RateIndexCollection),
+		DocumentMongoDB:      NewDocumentMongoDB(DB, cfg.DocumentCollection),
+		StatementMongoDB:     NewStatementMongoDB(DB, cfg.StatementCollection),
+		TxMongoDB:            NewTxMongoDB(DB),
 	}
 }
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TxMongoDB runs several storage calls in one multi-document transaction,MongoDB must be a replica set
+type TxMongoDB struct {
+	client *mongo.Client
+}
+
+func NewTxMongoDB(DB *mongo.Database) *TxMongoDB {
+	return &TxMongoDB{
+		client: DB.Client(),
+	}
+}
+
+// WithTransaction commits the changes fn made with the ctx it got only if fn returns nil.
+// fn is retried on transient errors,so it must not have effects outside the storage.
+// Inside another transaction fn joins it,so the changes are committed together
+func (d *TxMongoDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := d.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session:%s", err)
+	}
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx) //запросы с sessionCtx выполняются внутри транзакции
+	})
+
+	return err
+}
@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"slices"
+	"strings"
+)
+
+// Validator checks requests against struct tags and the configured credit limits.
+// Custom tags: credit_amount,credit_term,currency and interest_rate
+type Validator struct {
+	validate *validator.Validate
+	limits   config.Validation
+}
+
+func New(limits config.Validation) *Validator {
+	v := &Validator{
+		validate: validator.New(),
+		limits:   limits,
+	}
+
+	_ = v.validate.RegisterValidation("credit_amount", func(fl validator.FieldLevel) bool {
+		amount := int(fl.Field().Int())
+		return amount >= limits.MinAmount && amount <= limits.MaxAmount
+	})
+
+	_ = v.validate.RegisterValidation("credit_term", func(fl validator.FieldLevel) bool {
+		term := int(fl.Field().Int())
+		return term >= limits.MinTerm && term <= limits.MaxTerm
+	})
+
+	_ = v.validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return slices.Contains(limits.Currencies, fl.Field().String())
+	})
+
+	_ = v.validate.RegisterValidation("interest_rate", func(fl validator.FieldLevel) bool {
+		rate := fl.Field().Float()
+		return rate > 0 && rate <= limits.MaxInterestRate
+	})
+
+	return v
+}
+
+// Struct returns *models.ValidationError with every invalid field or nil
+func (v *Validator) Struct(data interface{}) error {
+	err := v.validate.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return fmt.Errorf("%w:%s", models.ErrInvalidRequest, err)
+	}
+
+	validationErr := &models.ValidationError{}
+	for _, fieldErr := range errs {
+		validationErr.Fields = append(validationErr.Fields, models.FieldError{
+			Field:   fieldPath(fieldErr),
+			Message: v.message(fieldErr),
+		})
+	}
+
+	return validationErr
+}
+
+func (v *Validator) message(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required", "required_if":
+		return fmt.Sprintf("you must fill the '%s' value", err.Field())
+	case "credit_amount":
+		return fmt.Sprintf("the '%s' value must be between %v and %v", err.Field(), v.limits.MinAmount, v.limits.MaxAmount)
+	case "credit_term":
+		return fmt.Sprintf("the '%s' value must be between %v and %v", err.Field(), v.limits.MinTerm, v.limits.MaxTerm)
+	case "currency":
+		return fmt.Sprintf("the '%s' value must be one of %s", err.Field(), strings.Join(v.limits.Currencies, ","))
+	case "interest_rate":
+		return fmt.Sprintf("the '%s' value must be above 0 and at most %v", err.Field(), v.limits.MaxInterestRate)
+	case "oneof":
+		return fmt.Sprintf("the '%s' value must be one of %s", err.Field(), err.Param())
+	case "gt":
+		return fmt.Sprintf("the '%s' value must be greater than %s", err.Field(), err.Param())
+	case "gte", "min":
+		return fmt.Sprintf("the '%s' value must be at least %s", err.Field(), err.Param())
+	case "lt":
+		return fmt.Sprintf("the '%s' value must be less than %s", err.Field(), err.Param())
+	case "lte", "max":
+		return fmt.Sprintf("the '%s' value must be at most %s", err.Field(), err.Param())
+	default:
+		return fmt.Sprintf("the '%s' value is invalid", err.Field())
+	}
+}
+
+// fieldPath is the path of the field without the struct name:Credit.Fees[0].Amount -> Fees[0].Amount
+func fieldPath(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if i := strings.Index(namespace, "."); i != -1 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RoleStaff is the role of bank employees in access tokens,other users are customers
+const RoleStaff = "staff"
+
+// Claims are the fields of the access token issued by auth_service
+type Claims struct {
+	UserID   int64
+	Username string
+	Role     string
+}
+
+// ParseAccessToken checks the signature and the expiry of the access token signed by auth_service with the same secret key.
+// Refresh tokens are signed with the same key but carry only userId and exp,so username and role are required
+func ParseAccessToken(accessToken, secretKey string) (Claims, error) {
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired()) //без этого подойдет токен с alg=none
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return Claims{}, fmt.Errorf("%w:access token expired", models.ErrUnauthenticated)
+		}
+		return Claims{}, fmt.Errorf("%w:parse access token failed:%s", models.ErrUnauthenticated, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return Claims{}, fmt.Errorf("%w:access token is not valid", models.ErrUnauthenticated)
+	}
+
+	userID, ok := claims["userId"].(float64) //числа в json всегда float64
+	if !ok || userID <= 0 {
+		return Claims{}, fmt.Errorf("%w:userId is missing or invalid in access token", models.ErrUnauthenticated)
+	}
+
+	username, _ := claims["username"].(string)
+	role, _ := claims["role"].(string)
+	if username == "" || role == "" { //их нет в refresh токене
+		return Claims{}, fmt.Errorf("%w:username or role is missing in access token,is it a refresh token", models.ErrUnauthenticated)
+	}
+
+	return Claims{UserID: int64(userID), Username: username, Role: role}, nil
+}
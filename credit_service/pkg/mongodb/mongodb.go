@@ -16,7 +16,12 @@ func ConnToMongoDB(cfg *config.Config) (*mongo.Database, error) {
 		Username: cfg.MongoDb.Username,
 		Password: cfg.MongoDb.Password,
 	}
-	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri).SetAuth(credentials))
+	opts := options.Client().ApplyURI(uri).SetAuth(credentials).SetDirect(cfg.MongoDb.DirectConnection)
+	if cfg.MongoDb.ReplicaSet != "" {
+		opts.SetReplicaSet(cfg.MongoDb.ReplicaSet)
+	}
+
+	client, err := mongo.Connect(context.Background(), opts)
 	if err != nil {
 		return nil, fmt.Errorf("mongo.Connect failed:%s", err)
 	}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/tests/suite"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDayCountFraction(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		convention string
+		start, end time.Time
+		expected   float64
+	}{
+		{"30/360 end of month", models.DayCount30360, date(2023, 1, 31), date(2023, 2, 1), 1.0 / 360},
+		{"30/360 february", models.DayCount30360, date(2023, 2, 28), date(2023, 3, 1), 3.0 / 360},
+		{"30/360 full year", models.DayCount30360, date(2023, 1, 15), date(2024, 1, 15), 1},
+		{"ACT/365 one day", models.DayCountACT365, date(2024, 2, 28), date(2024, 2, 29), 1.0 / 365},
+		{"ACT/365 leap year", models.DayCountACT365, date(2024, 1, 1), date(2025, 1, 1), 366.0 / 365},
+		{"ACT/ACT leap day", models.DayCountACTACT, date(2024, 2, 28), date(2024, 2, 29), 1.0 / 366},
+		{"ACT/ACT across years", models.DayCountACTACT, date(2023, 12, 31), date(2024, 1, 2), 1.0/365 + 1.0/366},
+		{"time of day is ignored", models.DayCountACT365, date(2024, 3, 1).Add(23 * time.Hour), date(2024, 3, 2), 1.0 / 365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fraction, err := service.DayCountFraction(tt.convention, tt.start, tt.end)
+			require.NoError(t, err)
+			require.InDelta(t, tt.expected, fraction, 1e-12)
+		})
+	}
+
+	_, err := service.DayCountFraction("ACT/360", date(2024, 1, 1), date(2024, 1, 2))
+	require.ErrorIs(t, err, models.ErrUnknownDayCount)
+}
+
+func TestCreditAccruals_RateReset(t *testing.T) {
+	issuedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	resetDate := issuedAt.AddDate(0, 0, 10)
+
+	credit := models.Credit{ID: randomHex(), Currency: "RUB", AnnualInterestRate: 10, IssuedAt: issuedAt}
+	entries := []models.JournalEntry{{
+		Type:      models.EntryDisbursement,
+		ValueDate: issuedAt,
+		Lines: []models.EntryLine{
+			{Account: models.AccountLoan, CreditID: credit.ID, Debit: 100_000},
+			{Account: models.AccountCash, Credit: 100_000},
+		},
+	}}
+
+	accruals, err := service.CreditAccruals(credit, entries, resetDate.AddDate(0, 0, -1), models.DayCountACT365)
+	require.NoError(t, err)
+	require.Len(t, accruals, 10)
+
+	var before int
+	for _, accrual := range accruals {
+		before += accrual.Amount
+		entries = append(entries, models.JournalEntry{
+			Type:      models.EntryAccrual,
+			ValueDate: accrual.Day,
+			Lines: []models.EntryLine{
+				{Account: models.AccountInterestReceivable, CreditID: credit.ID, Debit: accrual.Amount},
+				{Account: models.AccountInterestIncome, Credit: accrual.Amount},
+			},
+		})
+	}
+	require.Equal(t, 274, before) //100 000*10%*10/365
+
+	//ставка выросла вдвое,прошлые дни не пересчитываются
+	credit.AnnualInterestRate = 20
+	credit.RateHistory = []models.RatePeriod{{AnnualInterestRate: 10, Until: resetDate}}
+
+	accruals, err = service.CreditAccruals(credit, entries, resetDate.AddDate(0, 0, 9), models.DayCountACT365)
+	require.NoError(t, err)
+	require.Len(t, accruals, 10)
+	require.True(t, resetDate.Equal(accruals[0].Day))
+
+	var after int
+	for _, accrual := range accruals {
+		require.InDelta(t, 100_000*0.2/365, accrual.Amount, 1)
+		after += accrual.Amount
+	}
+	require.Equal(t, 822, before+after) //274+100 000*20%*10/365
+}
+
+func TestAccrual_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	creditID := response.CreatedCredit.ID
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	var report models.AccrualReport
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/accruals?through=%s", restPort, today), nil, http.StatusOK, &report)
+	require.Equal(t, today, report.Through)
+	require.Empty(t, report.Failed)
+
+	var statement models.AccountStatement
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/accounts/interest_receivable/statement?creditID=%s", restPort, creditID), nil, http.StatusOK, &statement)
+	require.LessOrEqual(t, len(statement.Lines), report.PostedEntries)
+
+	//повторный запуск за тот же день ничего не начисляет
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/accruals?through=%s", restPort, today), nil, http.StatusOK, &report)
+	require.Equal(t, 0, report.PostedEntries)
+
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/accruals?through=%s", restPort, time.Now().UTC().AddDate(0, 0, 2).Format(time.DateOnly)), nil, http.StatusBadRequest, nil)
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAnalytics_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	credits := st.MongoClient.Database(st.Cfg.MongoDb.Dbname).Collection(st.Cfg.MongoDb.CreditCollection)
+
+	_, err = credits.InsertMany(context.Background(), []interface{}{
+		bson.M{"userID": 1, "amount": 100_000, "outstandingPrincipal": 60_000, "currency": "RUB", "annualInterestRate": 10.0,
+			"status": models.CreditStatusActive, "issuedAt": date(2024, time.January, 15), "maturesAt": date(2025, time.February, 10)},
+		bson.M{"userID": 2, "amount": 200_000, "currency": "RUB", "annualInterestRate": 20.0, //остаток еще не считался
+			"status": models.CreditStatusActive, "issuedAt": date(2024, time.January, 20), "maturesAt": date(2025, time.May, 1),
+			"borrowers": bson.A{bson.M{"userID": 2, "role": models.BorrowerPrimary}, bson.M{"userID": 3, "role": models.BorrowerCo}}},
+		bson.M{"userID": 1, "amount": 1_000, "outstandingPrincipal": 500, "currency": "USD", "annualInterestRate": 5.0,
+			"status": models.CreditStatusActive, "issuedAt": date(2024, time.February, 10), "maturesAt": date(2025, time.March, 31)},
+		bson.M{"userID": 1, "amount": 900_000, "outstandingPrincipal": 900_000, "currency": "RUB", "annualInterestRate": 50.0,
+			"status": models.CreditStatusCancelled, "issuedAt": date(2024, time.January, 10), "maturesAt": date(2025, time.January, 1), "deletedAt": time.Now()},
+		bson.M{"userID": 3, "amount": 50_000, "currency": "RUB", "annualInterestRate": 15.0, "status": models.CreditStatusPending},
+	})
+	require.NoError(t, err)
+
+	analyticsURL := fmt.Sprintf("http://localhost:%s/analytics", restPort)
+
+	var outstanding []models.OutstandingByCurrency
+	doJSON(t, st, "GET", analyticsURL+"/outstanding", nil, http.StatusOK, &outstanding)
+	require.Equal(t, []models.OutstandingByCurrency{
+		{Currency: "RUB", Count: 2, OutstandingPrincipal: 260_000},
+		{Currency: "USD", Count: 1, OutstandingPrincipal: 500},
+	}, outstanding)
+
+	outstanding = nil
+	doJSON(t, st, "GET", analyticsURL+"/outstanding?from=2024-02-01&to=2024-02-29", nil, http.StatusOK, &outstanding)
+	require.Equal(t, []models.OutstandingByCurrency{{Currency: "USD", Count: 1, OutstandingPrincipal: 500}}, outstanding)
+
+	var rates []models.WeightedAverageRate
+	doJSON(t, st, "GET", analyticsURL+"/weighted-rate", nil, http.StatusOK, &rates)
+	require.Len(t, rates, 2)
+	require.Equal(t, "RUB", rates[0].Currency)
+	require.Equal(t, 260_000, rates[0].OutstandingPrincipal)
+	require.InDelta(t, (10.0*60_000+20.0*200_000)/260_000, rates[0].WeightedAverageRate, 1e-9)
+	require.Equal(t, "USD", rates[1].Currency)
+	require.InDelta(t, 5.0, rates[1].WeightedAverageRate, 1e-9)
+
+	var issued []models.IssuedByMonth
+	doJSON(t, st, "GET", analyticsURL+"/issued-by-month", nil, http.StatusOK, &issued)
+	require.Equal(t, []models.IssuedByMonth{
+		{Month: "2024-01", Currency: "RUB", Count: 2, Volume: 300_000},
+		{Month: "2024-02", Currency: "USD", Count: 1, Volume: 1_000},
+	}, issued)
+
+	var maturity []models.MaturityBucket
+	doJSON(t, st, "GET", analyticsURL+"/maturity-profile", nil, http.StatusOK, &maturity)
+	require.Equal(t, []models.MaturityBucket{
+		{Year: 2025, Quarter: 1, Currency: "RUB", Count: 1, OutstandingPrincipal: 60_000},
+		{Year: 2025, Quarter: 1, Currency: "USD", Count: 1, OutstandingPrincipal: 500},
+		{Year: 2025, Quarter: 2, Currency: "RUB", Count: 1, OutstandingPrincipal: 200_000},
+	}, maturity)
+
+	var borrowers []models.BorrowerExposure
+	doJSON(t, st, "GET", analyticsURL+"/top-borrowers", nil, http.StatusOK, &borrowers)
+	require.Len(t, borrowers, 3)
+
+	byUser := make(map[int64]models.BorrowerExposure)
+	for _, borrower := range borrowers {
+		byUser[borrower.UserID] = borrower
+	}
+	require.Equal(t, 200_000.0, byUser[2].Exposure)
+	require.Equal(t, 200_000.0, byUser[3].Exposure) //созаемщик отвечает за весь долг
+	require.Equal(t, 1, byUser[3].Count)
+	require.Equal(t, 60_000+500*90.0, byUser[1].Exposure)
+	require.Equal(t, 2, byUser[1].Count)
+	require.Equal(t, int64(1), borrowers[2].UserID)
+
+	borrowers = nil
+	doJSON(t, st, "GET", analyticsURL+"/top-borrowers?limit=1", nil, http.StatusOK, &borrowers)
+	require.Len(t, borrowers, 1)
+	require.Equal(t, 200_000.0, borrowers[0].Exposure)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestApproval_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID := randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+
+	var product map[string]models.Product
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), models.Product{
+		Name:               randomString(10),
+		Type:               models.ProductConsumerLoan,
+		Currencies:         []string{"RUB", "USD"},
+		MinAmount:          1000,
+		MaxAmount:          10_000_000,
+		MinTerm:            1,
+		MaxTerm:            360,
+		RateGrid:           []models.RateGridEntry{{MinTerm: 1, MaxTerm: 360, MinAmount: 1000, MaxAmount: 10_000_000, AnnualInterestRate: 12}},
+		ApprovalThresholds: map[string]int{"RUB": 1_000_000},
+	}, http.StatusOK, &product)
+	productID := product["Created Product"].ID
+
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+
+	//ниже порога и в валюте без порога кредит выдается сразу
+	for _, credit := range []models.Credit{
+		{UserID: userID, ProductID: productID, Amount: 1_000_000, Currency: "RUB", Term: 12},
+		{UserID: userID, ProductID: productID, Amount: 5_000_000, Currency: "USD", Term: 12},
+	} {
+		var created map[string]models.Credit
+		doJSONAs(t, st, "maker", "POST", creditsURL, credit, http.StatusOK, &created)
+		require.Equal(t, models.CreditStatusActive, created["Created Credit"].Status)
+		require.Nil(t, created["Created Credit"].Approval)
+	}
+
+	newPending := func() models.Credit {
+		var created map[string]models.Credit
+		doJSONAs(t, st, "maker", "POST", creditsURL,
+			models.Credit{UserID: userID, ProductID: productID, Amount: 1_000_000 + randomInt()%1000 + 1, Currency: "RUB", Term: 12}, http.StatusOK, &created)
+		return created["Created Credit"]
+	}
+
+	pending := newPending()
+	require.Equal(t, models.CreditStatusPending, pending.Status)
+	require.Equal(t, 1_000_000, pending.Approval.Threshold)
+	require.Equal(t, "maker", pending.Approval.RequestedBy)
+
+	var queue []models.Credit
+	doJSONAs(t, st, "checker", "GET", creditsURL+"/approvals", nil, http.StatusOK, &queue)
+	require.Len(t, queue, 1)
+	require.Equal(t, pending.ID, queue[0].ID)
+
+	creditURL := fmt.Sprintf("%s/objectID/%s", creditsURL, pending.ID)
+
+	//до одобрения деньги не выдаются
+	doJSONAs(t, st, "maker", "POST", creditURL+"/payments", models.Repayment{PaymentID: randomHex(), Amount: 1000}, http.StatusConflict, nil)
+
+	doJSONAs(t, st, "maker", "POST", creditURL+"/approve", nil, http.StatusForbidden, nil)
+
+	//сотрудник определяется только по токену:ни чужой X-Actor,ни отсутствие X-User-ID не помогают
+	for _, tt := range []struct {
+		token  string
+		status int
+	}{
+		{st.Token(randomInt64(), "maker", suite.RoleStaff), http.StatusForbidden},
+		{st.Token(userID, "checker", suite.RoleCustomer), http.StatusForbidden},
+		{"", http.StatusUnauthorized},
+	} {
+		req, err := http.NewRequest("POST", creditURL+"/approve", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Actor", "checker")
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+
+		resp, err := http.DefaultClient.Do(req) //без токена сотрудника от suite
+		require.NoError(t, err)
+		require.Equal(t, tt.status, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+	doJSONAs(t, st, "checker", "POST", creditURL+"/reject", models.ApprovalRequest{}, http.StatusBadRequest, nil)
+
+	var approved models.Credit
+	doJSONAs(t, st, "checker", "POST", creditURL+"/approve", models.ApprovalRequest{Comment: "income confirmed"}, http.StatusOK, &approved)
+	require.Equal(t, models.CreditStatusActive, approved.Status)
+	require.Len(t, approved.Approval.Decisions, 1)
+	require.Equal(t, models.DecisionApprove, approved.Approval.Decisions[0].Decision)
+	require.Equal(t, "checker", approved.Approval.Decisions[0].Actor)
+	require.Equal(t, "income confirmed", approved.Approval.Decisions[0].Comment)
+
+	doJSONAs(t, st, "checker", "POST", creditURL+"/reject", models.ApprovalRequest{Comment: "too late"}, http.StatusConflict, nil)
+
+	var quote models.PayoffQuote
+	doJSON(t, st, "GET", creditURL+"/payoff", nil, http.StatusOK, &quote)
+	require.Equal(t, approved.Amount, quote.Principal) //выдан при одобрении
+
+	rejected := newPending()
+	doJSONAs(t, st, "checker", "POST", fmt.Sprintf("%s/objectID/%s/reject", creditsURL, rejected.ID),
+		models.ApprovalRequest{Comment: "no collateral"}, http.StatusOK, &rejected)
+	require.Equal(t, models.CreditStatusRejected, rejected.Status)
+	require.Equal(t, models.DecisionReject, rejected.Approval.Decisions[0].Decision)
+
+	doJSONAs(t, st, "checker", "GET", creditsURL+"/approvals", nil, http.StatusOK, &queue)
+	require.Empty(t, queue)
+
+	var history []models.CreditHistory
+	doJSON(t, st, "GET", fmt.Sprintf("%s/objectID/%s/history", creditsURL, rejected.ID), nil, http.StatusOK, &history)
+	require.Equal(t, models.ActionReject, history[len(history)-1].Action)
+	require.Equal(t, "checker", history[len(history)-1].Actor)
+}
+
+func doJSONAs(t *testing.T, st *suite.Suite, actor, method, url string, body interface{}, expectedStatusCode int, res interface{}) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, url, &reqBody)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+st.Token(randomInt64(), actor, suite.RoleStaff))
+
+	resp, err := st.Client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+
+	if res != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/rest"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestCoBorrowers(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID, coBorrowerID := randomInt64(), randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), coBorrowerID))
+
+	credit := models.Credit{
+		UserID:    userID,
+		ProductID: createProduct(st, t, restPort),
+		Amount:    1_000_000,
+		Currency:  "RUB",
+		Term:      12,
+		Borrowers: []models.Borrower{
+			{UserID: userID, Role: models.BorrowerPrimary, MonthlyIncome: 50_000},
+			{UserID: coBorrowerID, Role: models.BorrowerCo, MonthlyIncome: 50_000},
+		},
+	}
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+
+	//платеж около 85 000 при общем доходе 100 000
+	var errResp rest.ErrorResponse
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusBadRequest, &errResp)
+	require.Equal(t, "Borrowers", errResp.Error.Fields[0].Field)
+
+	credit.Borrowers[1].MonthlyIncome = 500_000
+
+	var created map[string]models.Credit
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusOK, &created)
+	creditID := created["Created Credit"].ID
+	require.Len(t, created["Created Credit"].Borrowers, 2)
+
+	var credits []models.Credit
+	doJSON(t, st, "GET", fmt.Sprintf("%s/userID/%v", creditsURL, coBorrowerID), nil, http.StatusOK, &credits)
+	require.Len(t, credits, 1)
+	require.Equal(t, creditID, credits[0].ID)
+
+	stranger := randomInt64()
+
+	for _, tt := range []struct {
+		token  string
+		userID string //заголовок клиента не должен ничего менять
+		status int
+	}{
+		{st.Token(userID, "borrower", suite.RoleCustomer), "", http.StatusOK},
+		{st.Token(coBorrowerID, "co-borrower", suite.RoleCustomer), "", http.StatusOK},
+		{st.Token(stranger, "stranger", suite.RoleCustomer), "", http.StatusNotFound},
+		{st.Token(stranger, "stranger", suite.RoleCustomer), fmt.Sprint(userID), http.StatusNotFound},
+		{"forged", fmt.Sprint(userID), http.StatusUnauthorized},
+	} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/objectID/%s", creditsURL, creditID), nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		if tt.userID != "" {
+			req.Header.Set("X-User-ID", tt.userID)
+		}
+
+		resp, err := st.Client.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, tt.status, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+}
+
+func TestCustomerAccess(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID, otherID := randomInt64(), randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), otherID))
+
+	productID := createProduct(st, t, restPort)
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+
+	var created map[string]models.Credit
+	doJSON(t, st, "POST", creditsURL, models.Credit{UserID: userID, ProductID: productID, Amount: 100_000, Currency: "RUB", Term: 12}, http.StatusOK, &created)
+	creditID := created["Created Credit"].ID
+	doJSON(t, st, "POST", creditsURL, models.Credit{UserID: otherID, ProductID: productID, Amount: 200_000, Currency: "RUB", Term: 12}, http.StatusOK, nil)
+
+	customer := st.Token(userID, "borrower", suite.RoleCustomer)
+
+	var credits []models.Credit
+	doJSONWithToken(t, st, customer, "GET", creditsURL, nil, http.StatusOK, &credits)
+	require.Len(t, credits, 1) //чужой кредит не виден
+	require.Equal(t, creditID, credits[0].ID)
+
+	var summary models.Summary
+	doJSONWithToken(t, st, customer, "GET", creditsURL+"/summary", nil, http.StatusOK, &summary)
+	require.Equal(t, userID, summary.UserID)
+	require.Equal(t, 100_000, summary.Totals[0].Amount)
+
+	doJSONWithToken(t, st, customer, "GET", fmt.Sprintf("%s/userID/%v/summary", creditsURL, otherID), nil, http.StatusNotFound, nil)
+
+	//клиент не может выдать кредит другому юзеру
+	doJSONWithToken(t, st, customer, "POST", creditsURL,
+		models.Credit{UserID: otherID, ProductID: productID, Amount: 100_000, Currency: "RUB", Term: 12}, http.StatusForbidden, nil)
+	doJSONWithToken(t, st, customer, "POST", creditsURL+"/refinance",
+		models.RefinanceRequest{UserID: otherID, ProductID: productID, CreditIDs: []string{creditID}, Currency: "RUB", Term: 24}, http.StatusForbidden, nil)
+
+	for _, tt := range []struct {
+		method string
+		url    string
+		body   interface{}
+	}{
+		{"POST", fmt.Sprintf("%s/objectID/%s/payments", creditsURL, creditID), models.Repayment{PaymentID: randomHex(), Amount: 1000}},
+		{"POST", fmt.Sprintf("%s/objectID/%s/penalties", creditsURL, creditID), models.Penalty{Amount: 1000}},
+		{"POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), models.Product{Name: randomString(10)}},
+		{"POST", fmt.Sprintf("http://localhost:%s/admin/accruals", restPort), nil},
+		{"POST", fmt.Sprintf("http://localhost:%s/admin/rate-indices/KEY/values", restPort), models.IndexValue{Rate: 1}},
+		{"GET", fmt.Sprintf("http://localhost:%s/ledger/check", restPort), nil},
+		{"GET", fmt.Sprintf("http://localhost:%s/analytics/top-borrowers", restPort), nil},
+	} {
+		var errResp rest.ErrorResponse
+		doJSONWithToken(t, st, customer, tt.method, tt.url, tt.body, http.StatusForbidden, &errResp)
+		require.Equal(t, "staff_only", errResp.Error.Code, tt.url)
+	}
+}
+
+// doJSONWithToken is doJSON on behalf of the owner of the access token
+func doJSONWithToken(t *testing.T, st *suite.Suite, token, method, url string, body interface{}, expectedStatusCode int, res interface{}) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, url, &reqBody)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := st.Client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+
+	if res != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+}
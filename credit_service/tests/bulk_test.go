@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"bank/credit_service/internal/bulk"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/internal/validation"
+	"bank/credit_service/tests/suite"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// importService accepts every credit at a fixed rate and remembers the created ones
+type importService struct {
+	created []models.Credit
+}
+
+func (s *importService) ImportCredit(_ context.Context, credit models.Credit, dryRun bool) (models.Credit, error) {
+	if credit.ProductID == "missing" {
+		return models.Credit{}, fmt.Errorf("%w:%s", models.ErrProductNotFound, credit.ProductID)
+	}
+	credit.AnnualInterestRate = 12
+	if !dryRun {
+		s.created = append(s.created, credit)
+	}
+	return credit, nil
+}
+
+func TestImporter(t *testing.T) {
+	csvFile := strings.Join([]string{
+		"userID,productID,amount,currency,term",
+		"1,p,100000,RUB,12",
+		"2,p,abc,RUB,12",
+		"3,p,100000,GBP,12",
+		"4,missing,100000,RUB,12",
+		"5,p,100000",
+		"1,p,100000,RUB,12",
+	}, "\n")
+
+	t.Run("csv dry run", func(t *testing.T) {
+		service := &importService{}
+
+		report, err := bulk.NewImporter(service, validation.New(testLimits)).Import(context.Background(), strings.NewReader(csvFile), models.ImportCSV, true)
+		require.NoError(t, err)
+		require.Empty(t, service.created)
+
+		require.True(t, report.DryRun)
+		require.Equal(t, 6, report.Rows)
+		require.Equal(t, 1, report.Valid)
+		require.Equal(t, 0, report.Imported)
+
+		rows := make([]int, 0, len(report.Errors))
+		for _, rowErr := range report.Errors {
+			rows = append(rows, rowErr.Row)
+		}
+		require.Equal(t, []int{3, 4, 5, 6, 7}, rows)
+
+		require.Equal(t, []models.FieldError{{Field: "Amount", Message: "the 'Amount' value must be a whole number"}}, report.Errors[0].Fields)
+		require.Equal(t, "Currency", report.Errors[1].Fields[0].Field)
+		require.Contains(t, report.Errors[2].Message, models.ErrProductNotFound.Error())
+		require.Contains(t, report.Errors[4].Message, "same as the row 2") //повтор в файле
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		service := &importService{}
+
+		lines := strings.Join([]string{
+			`{"ID":"65a4f0c2e4b0a1b2c3d4e5f6","UserID":1,"ProductID":"p","Amount":100000,"Currency":"RUB","Term":12,"Status":"closed"}`,
+			``,
+			`{"UserID":2,`,
+			`{"UserID":3,"ProductID":"p","Amount":100000,"Currency":"USD","Term":24,"Scheme":"differentiated"}`,
+		}, "\n")
+
+		report, err := bulk.NewImporter(service, validation.New(testLimits)).Import(context.Background(), strings.NewReader(lines), models.ImportJSONL, false)
+		require.NoError(t, err)
+
+		require.Equal(t, 3, report.Rows)
+		require.Equal(t, 2, report.Imported)
+		require.Len(t, report.Errors, 1)
+		require.Equal(t, 3, report.Errors[0].Row)
+
+		//из выгрузки берется только то,что принимает CreateCredit
+		require.Empty(t, service.created[0].ID)
+		require.Empty(t, service.created[0].Status)
+		require.Equal(t, models.SchemeDifferentiated, service.created[1].Scheme)
+	})
+
+	t.Run("bad file", func(t *testing.T) {
+		importer := bulk.NewImporter(&importService{}, validation.New(testLimits))
+
+		_, err := importer.Import(context.Background(), strings.NewReader("userID,rate\n1,2"), models.ImportCSV, true)
+		require.ErrorIs(t, err, models.ErrInvalidRequest)
+
+		_, err = importer.Import(context.Background(), strings.NewReader(""), "xml", true)
+		require.ErrorIs(t, err, models.ErrInvalidRequest)
+	})
+}
+
+func TestBulk_OK(t *testing.T) {
+	st, ctx, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	userID := randomInt64()
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	require.NoError(t, consumer.NewUserIDCollection(ctx, userID))
+
+	productID := createProduct(st, t, restPort)
+
+	file := strings.Join([]string{
+		"UserID,ProductID,Amount,Currency,Term",
+		fmt.Sprintf("%v,%s,100000,RUB,12", userID, productID),
+		fmt.Sprintf("%v,%s,200000,RUB,24", userID, productID),
+		fmt.Sprintf("%v,%s,200000,RUB,24", randomInt64(), productID), //нет такого пользователя
+	}, "\n")
+
+	importURL := fmt.Sprintf("http://localhost:%s/admin/credits/import?format=csv", restPort)
+
+	var report models.ImportReport
+	postCSV(t, st, importURL+"&dryRun=true", file, http.StatusOK, &report)
+	require.Equal(t, 2, report.Valid)
+	require.Equal(t, 0, report.Imported)
+	require.Len(t, report.Errors, 1)
+	require.Equal(t, 4, report.Errors[0].Row)
+
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/userID/%v", restPort, userID), nil, http.StatusNotFound, nil)
+
+	report = models.ImportReport{}
+	postCSV(t, st, importURL, file, http.StatusOK, &report)
+	require.Equal(t, 2, report.Imported)
+
+	//повторный импорт не создает дубли
+	report = models.ImportReport{}
+	postCSV(t, st, importURL, file, http.StatusOK, &report)
+	require.Equal(t, 0, report.Imported)
+	require.Len(t, report.Errors, 3)
+
+	resp, err := st.Client.Get(fmt.Sprintf("http://localhost:%s/admin/credits/export", restPort))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var exported int
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var credit models.Credit
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &credit))
+		if credit.UserID == userID {
+			exported++
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 2, exported)
+}
+
+func postCSV(t *testing.T, st *suite.Suite, url, body string, expectedStatusCode int, res interface{}) {
+	resp, err := st.Client.Post(url, "text/csv", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+}
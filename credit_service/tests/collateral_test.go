@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/rest"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSecuredCredit(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID, guarantorID := randomInt64(), randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), guarantorID))
+
+	var product map[string]models.Product
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), models.Product{
+		Name:       randomString(10),
+		Type:       models.ProductCarLoan,
+		Currencies: []string{"RUB"},
+		MinAmount:  1000,
+		MaxAmount:  10_000_000,
+		MinTerm:    1,
+		MaxTerm:    84,
+		RateGrid:   []models.RateGridEntry{{MinTerm: 1, MaxTerm: 84, MinAmount: 1000, MaxAmount: 10_000_000, AnnualInterestRate: 12}},
+		MaxLTV:     80,
+	}, http.StatusOK, &product)
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	credit := models.Credit{
+		UserID:    userID,
+		ProductID: product["Created Product"].ID,
+		Amount:    900_000,
+		Currency:  "RUB",
+		Term:      60,
+	}
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+
+	var errResp rest.ErrorResponse
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusBadRequest, &errResp)
+	require.Equal(t, "Collateral", errResp.Error.Fields[0].Field)
+
+	credit.Collateral = []models.Collateral{{Type: models.CollateralCar, AppraisedValue: 1_000_000, ValuationDate: today}}
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusBadRequest, &errResp) //90% > 80%
+	require.Equal(t, "Collateral", errResp.Error.Fields[0].Field)
+
+	credit.Amount = 800_000
+	credit.Guarantors = []models.Guarantor{{UserID: userID, Share: 50}}
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusBadRequest, &errResp)
+	require.Equal(t, "Guarantors[0].UserID", errResp.Error.Fields[0].Field)
+
+	credit.Guarantors = []models.Guarantor{{UserID: guarantorID, Share: 50}}
+
+	var created map[string]models.Credit
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusOK, &created)
+	require.Equal(t, 80.0, created["Created Credit"].LTV)
+	require.Equal(t, models.LienPending, created["Created Credit"].Collateral[0].LienStatus)
+	require.Equal(t, guarantorID, created["Created Credit"].Guarantors[0].UserID)
+}
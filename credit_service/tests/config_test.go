@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"bank/credit_service/internal/config"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInitConfig_Defaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	//конфиг первого релиза,в нем только коллекции кредитов и юзеров
+	err := os.WriteFile(path, []byte("mongodb:\n  dbname: bank\n  credit_collection: credits\n  userid_collection: users\n"), 0o600)
+	require.NoError(t, err)
+
+	cfg, err := config.InitConfigByPath(path)
+	require.NoError(t, err)
+
+	require.Equal(t, "credits", cfg.MongoDb.CreditCollection)
+	require.Equal(t, "credit_history", cfg.MongoDb.HistoryCollection)
+	require.Equal(t, "products", cfg.MongoDb.ProductCollection)
+	require.Equal(t, "exchange_rates", cfg.MongoDb.ExchangeRateCollection)
+	require.Equal(t, "RUB", cfg.Exchange.BaseCurrency)
+	require.Equal(t, "ledger", cfg.MongoDb.LedgerCollection)
+	require.Equal(t, "payoff_quotes", cfg.MongoDb.PayoffQuoteCollection)
+	require.Equal(t, "credit_lines", cfg.MongoDb.CreditLineCollection)
+	require.Equal(t, "restructurings", cfg.MongoDb.RestructuringCollection)
+	require.Equal(t, "rate_indices", cfg.MongoDb.RateIndexCollection)
+	require.Equal(t, "documents", cfg.MongoDb.DocumentCollection)
+	require.Equal(t, "statements", cfg.MongoDb.StatementCollection)
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/rest"
 	"bank/credit_service/internal/storage"
 	"bank/credit_service/tests/suite"
 	"bytes"
@@ -19,6 +21,7 @@ import (
 type Request struct {
 	ID                 string
 	UserID             int64
+	ProductID          string
 	Amount             int
 	Currency           string
 	Term               int
@@ -52,12 +55,14 @@ func TestCredit_OK(t *testing.T) {
 	err = ConsumerMongoDB.NewUserIDCollection(ctx, userID)
 	require.NoError(t, err)
 
+	productID := createProduct(st, t, restPort)
+
 	createReqData := Request{
-		UserID:             userID,
-		Amount:             randomInt(),
-		Currency:           randomString(5),
-		Term:               randomInt(),
-		AnnualInterestRate: randomFloat64(),
+		UserID:    userID,
+		ProductID: productID,
+		Amount:    randomAmount(),
+		Currency:  "RUB",
+		Term:      randomTerm(),
 	}
 	jsonData, err := json.Marshal(createReqData)
 	require.NoError(t, err)
@@ -124,11 +129,10 @@ func TestCredit_OK(t *testing.T) {
 	defer getByUserIdResp.Body.Close()
 
 	updateReqData := Request{
-		UserID:             userID,
-		Amount:             randomInt(),
-		Currency:           randomString(5),
-		Term:               randomInt(),
-		AnnualInterestRate: randomFloat64(),
+		UserID:   userID,
+		Amount:   randomAmount(),
+		Currency: "USD",
+		Term:     randomTerm(),
 	}
 
 	jsonData, err = json.Marshal(updateReqData)
@@ -176,6 +180,7 @@ func TestCreate_Fail(t *testing.T) {
 	tests := []struct {
 		name               string
 		userID             int64
+		productID          string
 		amount             int
 		currency           string
 		term               int
@@ -201,6 +206,7 @@ func TestCreate_Fail(t *testing.T) {
 		{
 			name:               "empty amount",
 			userID:             randomInt64(),
+			productID:          randomHex(),
 			amount:             0,
 			currency:           randomString(5),
 			term:               randomInt(),
@@ -211,6 +217,7 @@ func TestCreate_Fail(t *testing.T) {
 		{
 			name:               "empty currency",
 			userID:             randomInt64(),
+			productID:          randomHex(),
 			amount:             randomInt(),
 			currency:           "",
 			term:               randomInt(),
@@ -221,6 +228,7 @@ func TestCreate_Fail(t *testing.T) {
 		{
 			name:               "empty term",
 			userID:             randomInt64(),
+			productID:          randomHex(),
 			amount:             randomInt(),
 			currency:           randomString(5),
 			term:               0,
@@ -229,20 +237,40 @@ func TestCreate_Fail(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:               "empty annualInterestRate",
+			name:               "empty productID",
 			userID:             randomInt64(),
 			amount:             randomInt(),
 			currency:           randomString(5),
 			term:               randomInt(),
-			annualInterestRate: 0,
-			expectedErr:        "you must fill the 'AnnualInterestRate' value",
+			expectedErr:        "you must fill the 'ProductID' value",
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name:               "out of range values",
+			userID:             randomInt64(),
+			productID:          randomHex(),
+			amount:             -randomAmount(),
+			currency:           randomString(5),
+			term:               361,
+			expectedErr:        `{"Field":"Term","Message":"the 'Term' value must be between 1 and 360"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown productID",
+			userID:             randomInt64(),
+			productID:          randomHex(),
+			amount:             randomAmount(),
+			currency:           "RUB",
+			term:               randomTerm(),
+			expectedErr:        "no product found with provided ID",
+			expectedStatusCode: http.StatusNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			createReqData := Request{
 				UserID:             tt.userID,
+				ProductID:          tt.productID,
 				Amount:             tt.amount,
 				Currency:           tt.currency,
 				Term:               tt.term,
@@ -267,6 +295,37 @@ func TestCreate_Fail(t *testing.T) {
 	}
 }
 
+func TestCreateProduct_Fail(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	for _, fee := range []models.Fee{
+		{Name: "origination", Type: "yearly", Amount: 100},
+		{Name: "origination", Type: models.FeeOneOff, Amount: -100},
+		{Name: "service", Type: models.FeeMonthly, Percent: -0.5},
+	} {
+		var errResp rest.ErrorResponse
+		doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), models.Product{
+			Name:       randomString(10),
+			Type:       models.ProductConsumerLoan,
+			Currencies: []string{"RUB"},
+			MinAmount:  1000,
+			MaxAmount:  1_000_000,
+			MinTerm:    1,
+			MaxTerm:    60,
+			RateGrid:   []models.RateGridEntry{{MinTerm: 1, MaxTerm: 60, MinAmount: 1000, MaxAmount: 1_000_000, AnnualInterestRate: 12}},
+			Fees:       []models.Fee{fee},
+		}, http.StatusBadRequest, &errResp)
+		require.Equal(t, "invalid_product", errResp.Error.Code)
+	}
+}
+
 func TestGetAll_Fail(t *testing.T) {
 	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
 	require.NoError(t, err)
@@ -321,11 +380,13 @@ func TestGetById_Fail(t *testing.T) {
 		name               string
 		expectedStatusCode int
 		expectedError      string
+		expectedCode       string
 	}{
 		{
 			name:               "no credit with provided id",
 			expectedStatusCode: http.StatusNotFound,
 			expectedError:      "no credit found with provided ID",
+			expectedCode:       "credit_not_found",
 		},
 	}
 	for _, tt := range tests {
@@ -342,6 +403,12 @@ func TestGetById_Fail(t *testing.T) {
 
 			require.Equal(t, getByIdResp.StatusCode, tt.expectedStatusCode)
 
+			var errResp rest.ErrorResponse
+			err = json.Unmarshal(body, &errResp)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCode, errResp.Error.Code)
+			require.NotEmpty(t, errResp.Error.RequestID)
+
 			defer getByIdResp.Body.Close()
 		})
 	}
@@ -438,15 +505,6 @@ func TestUpdate_Fail(t *testing.T) {
 			expectedErr:        "you must fill the 'Term' value",
 			expectedStatusCode: http.StatusBadRequest,
 		},
-		{
-			name:               "empty annualInterestRate",
-			amount:             randomInt(),
-			currency:           randomString(5),
-			term:               randomInt(),
-			annualInterestRate: 0,
-			expectedErr:        "you must fill the 'AnnualInterestRate' value",
-			expectedStatusCode: http.StatusBadRequest,
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -527,6 +585,85 @@ func TestDelete_Fail(t *testing.T) {
 	}
 }
 
+func TestCreditHistory_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	updateReqData := Request{
+		Amount:   randomAmount(),
+		Currency: "USD",
+		Term:     randomTerm(),
+	}
+
+	jsonData, err := json.Marshal(updateReqData)
+	require.NoError(t, err)
+
+	updateReq, err := http.NewRequest("PUT", fmt.Sprintf("http://localhost:%s/credits/%s", restPort, response.CreatedCredit.ID), bytes.NewBuffer(jsonData))
+	require.NoError(t, err)
+	updateReq.Header.Set("Authorization", "Bearer "+st.Token(2, "manager", suite.RoleStaff))
+
+	updateResp, err := st.Client.Do(updateReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, updateResp.StatusCode)
+
+	defer updateResp.Body.Close()
+
+	deleteReq, err := http.NewRequest("DELETE", fmt.Sprintf("http://localhost:%s/credits/%s", restPort, response.CreatedCredit.ID), nil)
+	require.NoError(t, err)
+
+	deleteResp, err := st.Client.Do(deleteReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, deleteResp.StatusCode)
+
+	defer deleteResp.Body.Close()
+
+	historyReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/history", restPort, response.CreatedCredit.ID), nil)
+	require.NoError(t, err)
+
+	historyResp, err := st.Client.Do(historyReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, historyResp.StatusCode)
+
+	defer historyResp.Body.Close()
+
+	var history []struct {
+		Action    string
+		Actor     string
+		RequestID string
+	}
+	err = json.NewDecoder(historyResp.Body).Decode(&history)
+	require.NoError(t, err)
+
+	require.Len(t, history, 3)
+	require.Equal(t, "create", history[0].Action)
+	require.Equal(t, "update", history[1].Action)
+	require.Equal(t, "manager", history[1].Actor)
+	require.NotEmpty(t, history[1].RequestID)
+	require.Equal(t, "delete", history[2].Action)
+
+	getByIdReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%s/credits/objectID/%s", restPort, response.CreatedCredit.ID), nil)
+	require.NoError(t, err)
+
+	getByIdResp, err := st.Client.Do(getByIdReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, getByIdResp.StatusCode) //soft deleted
+
+	defer getByIdResp.Body.Close()
+}
+
 func randomString(length int) string {
 	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	rand.Seed(uint64(time.Now().UnixNano()))
@@ -543,6 +680,14 @@ func randomInt() int {
 	return x
 }
 
+func randomAmount() int {
+	return rand.Intn(10_000_000) + 1000
+}
+
+func randomTerm() int {
+	return rand.Intn(360) + 1 //до 30 лет
+}
+
 func randomInt64() int64 {
 	rand.Seed(uint64(time.Now().UnixNano()))
 	return rand.Int63n(9223372036854775807) //max int in MongoDB
@@ -566,6 +711,39 @@ func randomHex() string {
 	return string(buffer)
 }
 
+func createProduct(st *suite.Suite, t *testing.T, restPort string) string {
+	product := models.Product{
+		Name:       randomString(10),
+		Type:       models.ProductConsumerLoan,
+		Currencies: []string{"RUB", "USD"},
+		MinAmount:  1000,
+		MaxAmount:  100_000_000,
+		MinTerm:    1,
+		MaxTerm:    360,
+		RateGrid: []models.RateGridEntry{
+			{MinTerm: 1, MaxTerm: 360, MinAmount: 1000, MaxAmount: 100_000_000, AnnualInterestRate: randomFloat64()/2 + 1},
+		},
+	}
+
+	jsonData, err := json.Marshal(product)
+	require.NoError(t, err)
+
+	createReq, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), bytes.NewBuffer(jsonData))
+	require.NoError(t, err)
+
+	createResp, err := st.Client.Do(createReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, createResp.StatusCode)
+
+	defer createResp.Body.Close()
+
+	var response map[string]models.Product
+	err = json.NewDecoder(createResp.Body).Decode(&response)
+	require.NoError(t, err)
+
+	return response["Created Product"].ID
+}
+
 func getIdForReq(st *suite.Suite, t *testing.T, restPort string) (CreditResponse, func() error) {
 	userID := randomInt64()
 
@@ -574,12 +752,14 @@ func getIdForReq(st *suite.Suite, t *testing.T, restPort string) (CreditResponse
 	err := ConsumerMongoDB.NewUserIDCollection(context.Background(), userID)
 	require.NoError(t, err)
 
+	productID := createProduct(st, t, restPort)
+
 	createReqData := Request{
-		UserID:             userID,
-		Amount:             randomInt(),
-		Currency:           randomString(5),
-		Term:               randomInt(),
-		AnnualInterestRate: randomFloat64(),
+		UserID:    userID,
+		ProductID: productID,
+		Amount:    randomAmount(),
+		Currency:  "RUB",
+		Term:      randomTerm(),
 	}
 	jsonData, err := json.Marshal(createReqData)
 	require.NoError(t, err)
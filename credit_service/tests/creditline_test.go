@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBillingStatements(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	line := models.CreditLine{ID: "line", OpenedAt: date(1, 10).Add(15 * time.Hour), BillingDay: 10}
+	rules := config.CreditLine{MinPaymentPercent: 5, MinPaymentAmount: 500, PaymentDueDays: 20}
+
+	entries := []models.JournalEntry{
+		{Type: models.EntryDrawdown, ValueDate: date(1, 12), Lines: []models.EntryLine{
+			{Account: models.AccountLoan, CreditID: "line", Debit: 10000},
+			{Account: models.AccountCash, Credit: 10000},
+		}},
+		{Type: models.EntryAccrual, ValueDate: date(1, 20), Lines: []models.EntryLine{
+			{Account: models.AccountInterestReceivable, CreditID: "line", Debit: 100},
+			{Account: models.AccountInterestIncome, Credit: 100},
+		}},
+		{Type: models.EntryRepayment, ValueDate: date(2, 15), Lines: []models.EntryLine{
+			{Account: models.AccountCash, Debit: 1000},
+			{Account: models.AccountInterestReceivable, CreditID: "line", Credit: 100},
+			{Account: models.AccountLoan, CreditID: "line", Credit: 900},
+		}},
+		{Type: models.EntryDrawdown, ValueDate: date(2, 20), Lines: []models.EntryLine{
+			{Account: models.AccountLoan, CreditID: "line", Debit: 2000},
+			{Account: models.AccountCash, Credit: 2000},
+		}},
+	}
+
+	statements := service.BillingStatements(line, entries, date(3, 15), rules)
+	require.Len(t, statements, 2)
+
+	first := statements[0]
+	require.Equal(t, date(1, 10), first.From)
+	require.Equal(t, date(2, 10), first.To)
+	require.Equal(t, 10000, first.Drawdowns)
+	require.Equal(t, 100, first.InterestCharged)
+	require.Equal(t, 10100, first.ClosingBalance)
+	require.Equal(t, 600, first.MinimumPayment) //5% от 10000 и проценты цикла
+	require.Equal(t, date(3, 1), first.DueDate)
+	require.Equal(t, 1000, first.PaidByDueDate)
+	require.False(t, first.Overdue)
+
+	second := statements[1]
+	require.Equal(t, 10100, second.OpeningBalance)
+	require.Equal(t, 2000, second.Drawdowns)
+	require.Equal(t, 1000, second.Repayments)
+	require.Equal(t, 11100, second.ClosingBalance)
+	require.Equal(t, 555, second.MinimumPayment)
+	require.False(t, second.Overdue) //срок платежа еще не прошел
+
+	statements = service.BillingStatements(line, entries, date(4, 1), rules)
+	require.Len(t, statements, 2)
+	require.True(t, statements[1].Overdue)
+
+	//минимальный платеж не больше долга
+	small := []models.JournalEntry{{Type: models.EntryDrawdown, ValueDate: date(1, 12), Lines: []models.EntryLine{
+		{Account: models.AccountLoan, CreditID: "line", Debit: 300},
+		{Account: models.AccountCash, Credit: 300},
+	}}}
+	statements = service.BillingStatements(line, small, date(2, 10), rules)
+	require.Len(t, statements, 1)
+	require.Equal(t, 300, statements[0].MinimumPayment)
+}
+
+func TestCreditLine_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	userID := randomInt64()
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+
+	var line models.CreditLine
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credit-lines", restPort),
+		models.CreditLine{UserID: userID, Currency: "RUB", Limit: 10000, AnnualInterestRate: 20}, http.StatusOK, &line)
+	require.Equal(t, 10000, line.Available)
+	require.Equal(t, models.CreditLineStatusActive, line.Status)
+
+	lineURL := fmt.Sprintf("http://localhost:%s/credit-lines/%s", restPort, line.ID)
+
+	var drawdown models.DrawdownResult
+	doJSON(t, st, "POST", lineURL+"/drawdowns", models.Drawdown{DrawdownID: "first", Amount: 7000}, http.StatusOK, &drawdown)
+	require.Equal(t, 3000, drawdown.Available)
+
+	doJSON(t, st, "POST", lineURL+"/drawdowns", models.Drawdown{Amount: 3001}, http.StatusUnprocessableEntity, nil)
+
+	//чужая линия для клиента не существует
+	owner, stranger := st.Token(userID, "owner", suite.RoleCustomer), st.Token(randomInt64(), "stranger", suite.RoleCustomer)
+	doJSONWithToken(t, st, owner, "GET", lineURL, nil, http.StatusOK, nil)
+	doJSONWithToken(t, st, stranger, "GET", lineURL, nil, http.StatusNotFound, nil)
+	doJSONWithToken(t, st, stranger, "GET", lineURL+"/statements", nil, http.StatusNotFound, nil)
+	doJSONWithToken(t, st, stranger, "POST", lineURL+"/drawdowns", models.Drawdown{Amount: 100}, http.StatusNotFound, nil)
+	doJSONWithToken(t, st, stranger, "DELETE", lineURL, nil, http.StatusNotFound, nil)
+	doJSONWithToken(t, st, stranger, "POST", fmt.Sprintf("http://localhost:%s/credit-lines", restPort),
+		models.CreditLine{UserID: userID, Currency: "RUB", Limit: 10000, AnnualInterestRate: 20}, http.StatusForbidden, nil)
+	doJSON(t, st, "POST", lineURL+"/drawdowns", models.Drawdown{DrawdownID: "first", Amount: 1000}, http.StatusConflict, nil)
+
+	var result models.RepaymentResult
+	doJSON(t, st, "POST", lineURL+"/payments", models.Repayment{Amount: 2000}, http.StatusOK, &result)
+	require.Equal(t, 2000, result.Principal)
+
+	doJSON(t, st, "GET", lineURL, nil, http.StatusOK, &line)
+	require.Equal(t, 5000, line.Drawn)
+	require.Equal(t, 5000, line.Available) //погашение восстанавливает лимит
+
+	doJSON(t, st, "DELETE", lineURL, nil, http.StatusConflict, nil)
+	doJSON(t, st, "POST", lineURL+"/payments", models.Repayment{Amount: 5000}, http.StatusOK, nil)
+	doJSON(t, st, "DELETE", lineURL, nil, http.StatusOK, nil)
+	doJSON(t, st, "POST", lineURL+"/drawdowns", models.Drawdown{Amount: 100}, http.StatusConflict, nil)
+
+	var statements []models.CreditLineStatement
+	doJSON(t, st, "GET", lineURL+"/statements", nil, http.StatusOK, &statements)
+	require.Empty(t, statements) //первый цикл еще не закончился
+}
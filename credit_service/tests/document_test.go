@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bank/credit_service/internal/document"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/tests/suite"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderer(t *testing.T) {
+	renderer, err := document.NewRenderer("")
+	require.NoError(t, err)
+
+	issuedAt := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	schedule, err := service.BuildSchedule(1_200_000, 12, 12, models.SchemeAnnuity, nil, issuedAt, models.InitialPeriod{})
+	require.NoError(t, err)
+
+	data := models.DocumentData{
+		Credit: models.Credit{
+			ID:                 "65a4f0c2e4b0a1b2c3d4e5f6",
+			Amount:             1_200_000,
+			Currency:           "RUB",
+			Term:               12,
+			AnnualInterestRate: 12,
+			IssuedAt:           issuedAt,
+			MaturesAt:          issuedAt.AddDate(1, 0, 0),
+			Borrowers:          []models.Borrower{{UserID: 1, Role: models.BorrowerPrimary}, {UserID: 2, Role: models.BorrowerCo}},
+		},
+		Schedule:    schedule,
+		GeneratedAt: issuedAt,
+	}
+
+	agreement, version, err := renderer.Render(models.DocumentAgreement, data)
+	require.NoError(t, err)
+	require.Equal(t, "v1", version)
+	require.Contains(t, string(agreement), "1 200 000 RUB")
+	require.Contains(t, string(agreement), "15.01.2024")
+	require.Contains(t, string(agreement), "Co-borrower")
+
+	scheduleDoc, _, err := renderer.Render(models.DocumentSchedule, data)
+	require.NoError(t, err)
+	require.Equal(t, len(schedule)+2, strings.Count(string(scheduleDoc), "<tr>")) //заголовок и итог
+
+	t.Run("latest version", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, content := range map[string]string{
+			"agreement_v1.html": "old",
+			"agreement_v2.html": "new {{.Credit.Currency}}",
+			"schedule_v1.html":  "schedule",
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+
+		renderer, err := document.NewRenderer(dir)
+		require.NoError(t, err)
+
+		content, version, err := renderer.Render(models.DocumentAgreement, data)
+		require.NoError(t, err)
+		require.Equal(t, "v2", version)
+		require.Equal(t, "new RUB", string(content))
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "agreement_v1.html"), []byte("agreement"), 0o600))
+
+		_, err := document.NewRenderer(dir) //нет шаблона графика
+		require.Error(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "schedule.html"), []byte("schedule"), 0o600))
+
+		_, err = document.NewRenderer(dir) //нет версии в имени
+		require.Error(t, err)
+	})
+}
+
+func TestDocuments_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	documentsURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s/documents", restPort, response.CreatedCredit.ID)
+
+	var documents []models.Document
+	doJSON(t, st, "GET", documentsURL, nil, http.StatusOK, &documents)
+	require.Len(t, documents, 2)
+
+	types := []string{documents[0].Type, documents[1].Type}
+	require.ElementsMatch(t, []string{models.DocumentAgreement, models.DocumentSchedule}, types)
+
+	download := func(id string) (*http.Response, []byte) {
+		resp, err := st.Client.Get(documentsURL + "/" + id)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, content
+	}
+
+	for _, doc := range documents {
+		require.Equal(t, "v1", doc.TemplateVersion)
+
+		resp, content := download(doc.ID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, doc.ContentType, resp.Header.Get("Content-Type"))
+		require.Equal(t, doc.SHA256, resp.Header.Get("X-Content-SHA256"))
+		require.Len(t, content, doc.Size)
+
+		sum := sha256.Sum256(content)
+		require.Equal(t, doc.SHA256, hex.EncodeToString(sum[:]))
+		require.Contains(t, string(content), response.CreatedCredit.ID)
+	}
+
+	resp, _ := download(randomHex())
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	//измененный документ не отдается
+	_, err = st.MongoClient.Database(st.Cfg.MongoDb.Dbname).Collection(st.Cfg.MongoDb.DocumentCollection).
+		UpdateOne(context.Background(), bson.M{"_id": documents[0].ID}, bson.M{"$set": bson.M{"content": []byte("forged")}})
+	require.NoError(t, err)
+
+	resp, _ = download(documents[0].ID)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/tests/suite"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreditEvents_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	deleteReq, err := http.NewRequest("DELETE", fmt.Sprintf("http://localhost:%s/credits/%s", restPort, response.CreatedCredit.ID), nil)
+	require.NoError(t, err)
+
+	deleteResp, err := st.Client.Do(deleteReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, deleteResp.StatusCode)
+
+	defer deleteResp.Body.Close()
+
+	created := readEvent(t, st, st.Cfg.Kafka.Events.CreditCreated)
+	require.Equal(t, models.EventCreditCreated, created.Type)
+	require.Equal(t, models.EventSchemaVersion, created.Version)
+	require.Equal(t, response.CreatedCredit.ID, created.CreditID)
+	require.Equal(t, models.CreditStatusActive, created.Data.Status)
+	require.Len(t, created.Data.Schedule, created.Data.Term) //по нему напоминают о платежах
+
+	statusChanged := readEvent(t, st, st.Cfg.Kafka.Events.CreditStatusChanged)
+	require.Equal(t, response.CreatedCredit.ID, statusChanged.CreditID)
+	require.Equal(t, models.CreditStatusActive, statusChanged.Data.PreviousStatus)
+	require.Equal(t, models.CreditStatusCancelled, statusChanged.Data.Status)
+
+	//опубликованные события удаляются из outbox
+	objectID, err := primitive.ObjectIDFromHex(response.CreatedCredit.ID)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		var credit models.Credit
+		err = st.MongoClient.Database(st.Cfg.MongoDb.Dbname).Collection(st.Cfg.MongoDb.CreditCollection).
+			FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&credit)
+		return err == nil && len(credit.Outbox) == 0
+	}, 10*time.Second, 500*time.Millisecond)
+}
+
+func readEvent(t *testing.T, st *suite.Suite, topic string) models.CreditEvent {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{st.Cfg.Kafka.Brokers},
+		Topic:       topic,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	msg, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+
+	var event models.CreditEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &event))
+	require.Equal(t, event.CreditID, string(msg.Key))
+
+	return event
+}
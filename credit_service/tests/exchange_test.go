@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/exchange"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInMemoryProvider(t *testing.T) {
+	provider := exchange.NewInMemoryProvider("RUB")
+	provider.AddRate("USD", 90, "2024-01-01")
+	provider.AddRate("USD", 92, "2024-02-01")
+	provider.AddRate("EUR", 100, "2024-01-01")
+
+	rate, err := provider.Rate(context.Background(), "USD", "RUB", time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 90.0, rate.Rate)
+	require.Equal(t, "2024-01-01", rate.Date)
+
+	rate, err = provider.Rate(context.Background(), "USD", "RUB", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 92.0, rate.Rate)
+
+	rate, err = provider.Rate(context.Background(), "EUR", "USD", time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.InDelta(t, 100.0/90, rate.Rate, 1e-9)
+
+	_, err = provider.Rate(context.Background(), "USD", "RUB", time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+
+	err := os.WriteFile(path, []byte(`{"base":"RUB","rates":[{"currency":"USD","rate":92.5,"date":"2024-05-01"}]}`), 0o600)
+	require.NoError(t, err)
+
+	provider, err := exchange.NewFileProvider(path)
+	require.NoError(t, err)
+
+	rate, err := provider.Rate(context.Background(), "USD", "RUB", time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 92.5, rate.Rate)
+	require.Equal(t, "file", rate.Source)
+}
+
+func TestSummary_Outstanding(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	userID := randomInt64()
+	credits := st.MongoClient.Database(st.Cfg.MongoDb.Dbname).Collection(st.Cfg.MongoDb.CreditCollection)
+
+	_, err = credits.InsertMany(context.Background(), []interface{}{
+		bson.M{"userID": userID, "amount": 100_000, "outstandingPrincipal": 40_000, "currency": "RUB", "status": models.CreditStatusActive},
+		bson.M{"userID": userID, "amount": 50_000, "currency": "RUB", "status": models.CreditStatusActive}, //остаток еще не считался
+		bson.M{"userID": userID, "amount": 1_000, "outstandingPrincipal": 700, "currency": "USD", "status": models.CreditStatusActive},
+		bson.M{"userID": userID, "amount": 300_000, "outstandingPrincipal": 0, "currency": "RUB", "status": models.CreditStatusClosed},
+		bson.M{"userID": userID, "amount": 200_000, "outstandingPrincipal": 150_000, "currency": "RUB", "status": models.CreditStatusActive, "refinancedBy": "new"},
+		bson.M{"userID": userID, "amount": 70_000, "currency": "RUB", "status": models.CreditStatusCancelled, "deletedAt": time.Now()},
+		bson.M{"userID": userID, "amount": 80_000, "currency": "RUB", "status": models.CreditStatusPending},
+	})
+	require.NoError(t, err)
+
+	var summary models.Summary
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/userID/%v/summary", restPort, userID), nil, http.StatusOK, &summary)
+
+	require.Len(t, summary.Totals, 2)
+	require.Equal(t, "RUB", summary.Totals[0].Currency)
+	require.Equal(t, 2, summary.Totals[0].Count)
+	require.Equal(t, 90_000, summary.Totals[0].Amount)
+	require.Equal(t, "USD", summary.Totals[1].Currency)
+	require.Equal(t, 1, summary.Totals[1].Count)
+	require.Equal(t, 700, summary.Totals[1].Amount)
+	require.Equal(t, 90_000+700*90.0, summary.TotalInBase)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRepriceSchedule(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	fees := []models.Fee{{Name: "service", Type: models.FeeMonthly, Amount: 100}}
+
+	schedule, err := service.BuildSchedule(120_000, 12, 12, models.SchemeAnnuity, fees, issuedAt, models.InitialPeriod{})
+	require.NoError(t, err)
+
+	repriced, err := service.RepriceSchedule(schedule, issuedAt, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), 18, models.SchemeAnnuity)
+	require.NoError(t, err)
+	require.Len(t, repriced, 12)
+	require.Equal(t, schedule[:3], repriced[:3]) //платеж в дату пересмотра относится к прошлому периоду
+
+	require.Equal(t, "2024-05-15", repriced[3].Date)
+	require.Equal(t, int(math.Round(float64(schedule[2].Balance)*0.015)), repriced[3].Interest)
+	require.Equal(t, 100, repriced[3].Fees)
+	require.Equal(t, 0, repriced[11].Balance)
+
+	var principal int
+	for _, payment := range repriced {
+		principal += payment.Principal
+	}
+	require.Equal(t, 120_000, principal)
+
+	t.Run("holiday", func(t *testing.T) {
+		holiday, err := service.RescheduleCredit(schedule[:3], service.Reschedule{
+			Principal:          schedule[2].Balance,
+			Term:               9,
+			AnnualInterestRate: 12,
+			MonthlyFee:         100,
+			HolidayMonths:      2,
+			InterestTreatment:  models.InterestCapitalize,
+			Start:              issuedAt.AddDate(0, 3, 0),
+		})
+		require.NoError(t, err)
+
+		repriced, err := service.RepriceSchedule(holiday, issuedAt, time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC), 18, models.SchemeAnnuity)
+		require.NoError(t, err)
+		require.Equal(t, holiday[:5], repriced[:5]) //каникулы не прерываются
+		require.Equal(t, int(math.Round(float64(holiday[4].Balance)*0.015)), repriced[5].Interest)
+	})
+
+	t.Run("no payments left", func(t *testing.T) {
+		repriced, err := service.RepriceSchedule(schedule, issuedAt, issuedAt.AddDate(1, 0, 0), 18, models.SchemeAnnuity)
+		require.NoError(t, err)
+		require.Equal(t, schedule, repriced)
+	})
+}
+
+func TestFloatingRate_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID := randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+
+	indexURL := fmt.Sprintf("http://localhost:%s/admin/rate-indices/key_rate/values", restPort)
+
+	doJSON(t, st, "POST", indexURL, models.IndexValue{Date: "2000-01-01", Rate: 150}, http.StatusBadRequest, nil)
+	doJSON(t, st, "POST", indexURL, models.IndexValue{Date: "2000-01-01", Rate: 16}, http.StatusOK, nil)
+
+	product := func(index string) string {
+		var product map[string]models.Product
+		doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/products", restPort), models.Product{
+			Name:       randomString(10),
+			Type:       models.ProductMortgage,
+			Currencies: []string{"RUB"},
+			MinAmount:  1000,
+			MaxAmount:  10_000_000,
+			MinTerm:    1,
+			MaxTerm:    360,
+			RateGrid:   []models.RateGridEntry{{MinTerm: 1, MaxTerm: 360, MinAmount: 1000, MaxAmount: 10_000_000, AnnualInterestRate: 2.5}},
+			RateIndex:  index,
+		}, http.StatusOK, &product)
+		return product["Created Product"].ID
+	}
+
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+	credit := models.Credit{UserID: userID, ProductID: product("unknown_index"), Amount: 100_000, Currency: "RUB", Term: 12}
+
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusUnprocessableEntity, nil)
+
+	credit.ProductID = product("key_rate")
+
+	var created map[string]models.Credit
+	doJSON(t, st, "POST", creditsURL, credit, http.StatusOK, &created)
+	credit = created["Created Credit"]
+	require.Equal(t, 18.5, credit.AnnualInterestRate)
+	require.Equal(t, 2.5, credit.RateMargin)
+	require.Equal(t, 3, credit.RateResetMonths) //из конфига
+	require.NotNil(t, credit.NextRateReset)
+	require.True(t, credit.IssuedAt.AddDate(0, 3, 0).Equal(*credit.NextRateReset))
+
+	doJSON(t, st, "POST", indexURL, models.IndexValue{Date: time.Now().UTC().Format(time.DateOnly), Rate: 20}, http.StatusOK, nil)
+
+	var values []models.IndexValue
+	doJSON(t, st, "GET", indexURL, nil, http.StatusOK, &values)
+	require.Len(t, values, 2)
+
+	//дата пересмотра наступает сегодня
+	objectID, err := primitive.ObjectIDFromHex(credit.ID)
+	require.NoError(t, err)
+	_, err = st.MongoClient.Database(st.Cfg.MongoDb.Dbname).Collection(st.Cfg.MongoDb.CreditCollection).
+		UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{"$set": bson.M{"nextRateReset": time.Now().UTC().Truncate(24 * time.Hour)}})
+	require.NoError(t, err)
+
+	var report models.RateResetReport
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/rate-resets", restPort), nil, http.StatusOK, &report)
+	require.Empty(t, report.Failed)
+	require.Len(t, report.Resets, 1)
+	require.Equal(t, credit.ID, report.Resets[0].CreditID)
+	require.Equal(t, 18.5, report.Resets[0].OldRate)
+	require.Equal(t, 22.5, report.Resets[0].NewRate)
+
+	var reset models.Credit
+	doJSON(t, st, "GET", fmt.Sprintf("%s/objectID/%s", creditsURL, credit.ID), nil, http.StatusOK, &reset)
+	require.Equal(t, 22.5, reset.AnnualInterestRate)
+	require.Len(t, reset.Schedule, 12)
+	require.Greater(t, reset.MonthlyPayment, credit.MonthlyPayment)
+	require.True(t, reset.NextRateReset.After(time.Now()))
+
+	//следующий запуск в тот же день ничего не пересматривает
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/rate-resets", restPort), nil, http.StatusOK, &report)
+	require.Empty(t, report.Resets)
+
+	var history []models.CreditHistory
+	doJSON(t, st, "GET", fmt.Sprintf("%s/objectID/%s/history", creditsURL, credit.ID), nil, http.StatusOK, &history)
+	require.Equal(t, models.ActionRateReset, history[len(history)-1].Action)
+}
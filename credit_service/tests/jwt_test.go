@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/pkg/jwt"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseAccessToken(t *testing.T) {
+	const secretKey = "test"
+
+	sign := func(method jwtlib.SigningMethod, key interface{}, claims jwtlib.MapClaims) string {
+		token, err := jwtlib.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	claims, err := jwt.ParseAccessToken(sign(jwtlib.SigningMethodHS256, []byte(secretKey),
+		jwtlib.MapClaims{"userId": 42, "username": "borrower", "role": "customer", "exp": exp}), secretKey)
+	require.NoError(t, err)
+	require.Equal(t, jwt.Claims{UserID: 42, Username: "borrower", Role: "customer"}, claims)
+
+	for name, token := range map[string]string{
+		"other secret": sign(jwtlib.SigningMethodHS256, []byte("other"), jwtlib.MapClaims{"userId": 42, "exp": exp}),
+		"alg none":     sign(jwtlib.SigningMethodNone, jwtlib.UnsafeAllowNoneSignatureType, jwtlib.MapClaims{"userId": 42, "exp": exp}),
+		"expired":      sign(jwtlib.SigningMethodHS256, []byte(secretKey), jwtlib.MapClaims{"userId": 42, "exp": time.Now().Add(-time.Minute).Unix()}),
+		"no expiry":    sign(jwtlib.SigningMethodHS256, []byte(secretKey), jwtlib.MapClaims{"userId": 42}),
+		"no user":      sign(jwtlib.SigningMethodHS256, []byte(secretKey), jwtlib.MapClaims{"exp": exp}),
+		"garbage":      "not.a.token",
+		//refresh токен auth_service подписан тем же ключом
+		"refresh token": sign(jwtlib.SigningMethodHS256, []byte(secretKey), jwtlib.MapClaims{"userId": 42, "exp": exp}),
+		"no role":       sign(jwtlib.SigningMethodHS256, []byte(secretKey), jwtlib.MapClaims{"userId": 42, "username": "borrower", "exp": exp}),
+	} {
+		_, err = jwt.ParseAccessToken(token, secretKey)
+		require.ErrorIs(t, err, models.ErrUnauthenticated, name)
+	}
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/tests/suite"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestJournalEntryBalanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []models.EntryLine
+		balanced bool
+	}{
+		{
+			name: "balanced",
+			lines: []models.EntryLine{
+				{Account: models.AccountLoan, Debit: 1000},
+				{Account: models.AccountCash, Credit: 990},
+				{Account: models.AccountFeeIncome, Credit: 10},
+			},
+			balanced: true,
+		},
+		{
+			name: "debits don't equal credits",
+			lines: []models.EntryLine{
+				{Account: models.AccountLoan, Debit: 1000},
+				{Account: models.AccountCash, Credit: 990},
+			},
+		},
+		{
+			name: "two-sided line",
+			lines: []models.EntryLine{
+				{Account: models.AccountLoan, Debit: 1000, Credit: 1000},
+			},
+		},
+		{
+			name: "negative amount",
+			lines: []models.EntryLine{
+				{Account: models.AccountLoan, Debit: -1000},
+				{Account: models.AccountCash, Credit: -1000},
+			},
+		},
+		{
+			name: "no lines",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.balanced, models.JournalEntry{Lines: tt.lines}.Balanced())
+		})
+	}
+}
+
+func TestLedger_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	creditID := response.CreatedCredit.ID
+
+	var credit models.Credit
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/objectID/%s", restPort, creditID), nil, http.StatusOK, &credit)
+
+	var result models.RepaymentResult
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payments", restPort, creditID),
+		models.Repayment{PaymentID: "first", Amount: 100}, http.StatusOK, &result)
+	require.Equal(t, 100, result.Principal)
+	require.Equal(t, credit.Amount-100, result.OutstandingPrincipal)
+	require.Equal(t, models.CreditStatusActive, result.Status)
+
+	//повтор того же платежа не проводится второй раз
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payments", restPort, creditID),
+		models.Repayment{PaymentID: "first", Amount: 100}, http.StatusConflict, nil)
+
+	doJSON(t, st, "PUT", fmt.Sprintf("http://localhost:%s/credits/%s", restPort, creditID),
+		Request{Amount: randomAmount(), Currency: "RUB", Term: randomTerm()}, http.StatusConflict, nil)
+
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/penalties", restPort, creditID),
+		models.Penalty{Amount: 50, Reason: "late payment"}, http.StatusOK, nil)
+
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payments", restPort, creditID),
+		models.Repayment{Amount: credit.Amount}, http.StatusUnprocessableEntity, nil)
+
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payments", restPort, creditID),
+		models.Repayment{Amount: credit.Amount - 100 + 50}, http.StatusOK, &result)
+	require.Equal(t, 50, result.Penalty)
+	require.Equal(t, 0, result.OutstandingPrincipal)
+	require.Equal(t, models.CreditStatusClosed, result.Status)
+
+	var statement models.AccountStatement
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/accounts/loan/statement?creditID=%s", restPort, creditID), nil, http.StatusOK, &statement)
+	require.Len(t, statement.Lines, 3) //выдача и два платежа
+	require.Equal(t, 0, statement.ClosingBalance)
+
+	var trialBalance models.TrialBalance
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/trial-balance", restPort), nil, http.StatusOK, &trialBalance)
+	require.True(t, trialBalance.Balanced)
+	require.Equal(t, credit.Amount+50+credit.Amount-100+50+100, trialBalance.TotalDebit["RUB"])
+
+	var check models.LedgerCheck
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/check", restPort), nil, http.StatusOK, &check)
+	require.True(t, check.Balanced)
+}
+
+func TestRepay_Concurrent(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	creditURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s", restPort, response.CreatedCredit.ID)
+
+	var credit models.Credit
+	doJSON(t, st, "GET", creditURL, nil, http.StatusOK, &credit)
+
+	//каждый платеж больше половины долга,пройти может только один
+	statuses := make(chan int, 5)
+	var wg sync.WaitGroup
+
+	for i := 0; i < cap(statuses); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			body, _ := json.Marshal(models.Repayment{PaymentID: randomHex(), Amount: credit.Amount/2 + 1})
+			resp, err := st.Client.Post(creditURL+"/payments", "application/json", bytes.NewReader(body))
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			statuses <- resp.StatusCode
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := make(map[int]int)
+	for status := range statuses {
+		counts[status]++
+	}
+	require.Equal(t, map[int]int{http.StatusOK: 1, http.StatusUnprocessableEntity: 4}, counts)
+
+	var statement models.AccountStatement
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/accounts/loan/statement?creditID=%s", restPort, credit.ID), nil, http.StatusOK, &statement)
+	require.Equal(t, credit.Amount-credit.Amount/2-1, statement.ClosingBalance)
+}
+
+func doJSON(t *testing.T, st *suite.Suite, method, url string, body interface{}, expectedStatusCode int, res interface{}) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, url, &reqBody)
+	require.NoError(t, err)
+
+	resp, err := st.Client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+
+	if res != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+}
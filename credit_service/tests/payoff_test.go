@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/tests/suite"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPayoff_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	creditID := response.CreatedCredit.ID
+	var credit models.Credit
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/objectID/%s", restPort, creditID), nil, http.StatusOK, &credit)
+
+	payoffURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payoff", restPort, creditID)
+	paymentsURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s/payments", restPort, creditID)
+
+	doJSON(t, st, "GET", payoffURL+"?date="+time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly), nil, http.StatusBadRequest, nil)
+
+	date := time.Now().UTC().AddDate(0, 0, 10).Format(time.DateOnly)
+
+	var quote models.PayoffQuote
+	doJSON(t, st, "GET", payoffURL+"?date="+date, nil, http.StatusOK, &quote)
+	require.NotEmpty(t, quote.ID)
+	require.Equal(t, date, quote.Date)
+	require.Equal(t, credit.Amount, quote.Principal)
+	require.Equal(t, (quote.Principal+50)/100, quote.Fees) //fee_percent: 1
+	require.Equal(t, quote.Principal+quote.AccruedInterest+quote.Penalties+quote.Fees, quote.Total)
+	require.True(t, quote.ExpiresAt.After(time.Now()))
+
+	doJSON(t, st, "POST", paymentsURL, models.Repayment{QuoteID: "unknown", Amount: quote.Total}, http.StatusNotFound, nil)
+	doJSON(t, st, "POST", paymentsURL, models.Repayment{QuoteID: quote.ID, Amount: quote.Total - 1}, http.StatusUnprocessableEntity, nil)
+
+	var result models.RepaymentResult
+	doJSON(t, st, "POST", paymentsURL, models.Repayment{QuoteID: quote.ID, Amount: quote.Total}, http.StatusOK, &result)
+	require.Equal(t, quote.Fees, result.Fee)
+	require.Equal(t, quote.AccruedInterest, result.Interest)
+	require.Equal(t, 0, result.OutstandingPrincipal)
+	require.Equal(t, models.CreditStatusClosed, result.Status)
+
+	doJSON(t, st, "POST", paymentsURL, models.Repayment{QuoteID: quote.ID, Amount: quote.Total}, http.StatusConflict, nil)
+
+	var check models.LedgerCheck
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/check", restPort), nil, http.StatusOK, &check)
+	require.True(t, check.Balanced)
+}
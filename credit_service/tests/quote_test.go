@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/tests/suite"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBuildSchedule(t *testing.T) {
+	dateOfIssue := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	fees := []models.Fee{{Name: "service", Type: models.FeeMonthly, Amount: 100}}
+
+	tests := []struct {
+		name   string
+		scheme string
+	}{
+		{name: "annuity", scheme: models.SchemeAnnuity},
+		{name: "differentiated", scheme: models.SchemeDifferentiated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := service.BuildSchedule(120000, 12, 12, tt.scheme, fees, dateOfIssue, models.InitialPeriod{})
+			require.NoError(t, err)
+			require.Len(t, schedule, 12)
+
+			var principal int
+			for _, payment := range schedule {
+				principal += payment.Principal
+				require.Equal(t, 100, payment.Fees)
+				require.Equal(t, payment.Principal+payment.Interest+payment.Fees, payment.Payment)
+			}
+
+			require.Equal(t, 120000, principal)
+			require.Equal(t, 0, schedule[11].Balance)
+			require.Equal(t, "2024-02-15", schedule[0].Date)
+			require.Equal(t, 1200, schedule[0].Interest)
+		})
+	}
+
+	_, err := service.BuildSchedule(120000, 12, 12, "balloon", nil, dateOfIssue, models.InitialPeriod{})
+	require.Error(t, err)
+}
+
+func TestBuildSchedule_InitialPeriod(t *testing.T) {
+	dateOfIssue := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	fees := []models.Fee{{Name: "service", Type: models.FeeMonthly, Amount: 100}}
+	initial := models.InitialPeriod{GraceMonths: 2, InterestOnlyMonths: 2}
+
+	schedule, err := service.BuildSchedule(120000, 12, 12, models.SchemeAnnuity, fees, dateOfIssue, initial)
+	require.NoError(t, err)
+	require.Len(t, schedule, 12)
+
+	//проценты льготного периода прибавляются к долгу,платежей нет
+	require.Equal(t, models.Payment{Number: 1, Date: "2024-02-15", Balance: 121200}, schedule[0])
+	require.Equal(t, 122412, schedule[1].Balance)
+
+	for _, payment := range schedule[2:4] {
+		require.Equal(t, 0, payment.Principal)
+		require.Equal(t, 1224, payment.Interest)
+		require.Equal(t, 1324, payment.Payment)
+		require.Equal(t, 122412, payment.Balance)
+	}
+
+	annuityPayment, _, _ := service.CalculateCreditParams(12, 120000, 12, initial)
+	require.Equal(t, annuityPayment, schedule[4].Principal+schedule[4].Interest)
+
+	var principal int
+	for _, payment := range schedule {
+		principal += payment.Principal
+	}
+	require.Equal(t, 122412, principal)
+	require.Equal(t, 0, schedule[11].Balance)
+
+	differentiated, err := service.BuildSchedule(120000, 12, 12, models.SchemeDifferentiated, nil, dateOfIssue, initial)
+	require.NoError(t, err)
+	require.Equal(t, 122412/8, differentiated[4].Principal)
+
+	//капитализация по той же ставке не меняет полную стоимость кредита
+	withoutFees, err := service.BuildSchedule(120000, 12, 12, models.SchemeAnnuity, nil, dateOfIssue, initial)
+	require.NoError(t, err)
+	apr, err := service.EffectiveAnnualRate(120000, 0, withoutFees)
+	require.NoError(t, err)
+	require.InDelta(t, 12.68, apr, 0.01)
+
+	_, err = service.BuildSchedule(120000, 4, 12, models.SchemeAnnuity, nil, dateOfIssue, initial)
+	require.ErrorIs(t, err, models.ErrProductMismatch)
+}
+
+func TestEffectiveAnnualRate(t *testing.T) {
+	dateOfIssue := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+
+	schedule, err := service.BuildSchedule(120000, 12, 12, models.SchemeAnnuity, nil, dateOfIssue, models.InitialPeriod{})
+	require.NoError(t, err)
+
+	apr, err := service.EffectiveAnnualRate(120000, 0, schedule)
+	require.NoError(t, err)
+	require.InDelta(t, 12.68, apr, 0.01) //(1+0.01)^12-1
+
+	fees := []models.Fee{{Name: "service", Type: models.FeeMonthly, Amount: 100}}
+
+	scheduleWithFees, err := service.BuildSchedule(120000, 12, 12, models.SchemeAnnuity, fees, dateOfIssue, models.InitialPeriod{})
+	require.NoError(t, err)
+
+	aprWithFees, err := service.EffectiveAnnualRate(120000, 2000, scheduleWithFees)
+	require.NoError(t, err)
+	require.Greater(t, aprWithFees, apr)
+}
+
+func TestQuote_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	quoteReqData := models.QuoteRequest{
+		ProductID: createProduct(st, t, restPort),
+		Amount:    100000,
+		Currency:  "RUB",
+		Term:      24,
+		Scheme:    models.SchemeDifferentiated,
+	}
+
+	jsonData, err := json.Marshal(quoteReqData)
+	require.NoError(t, err)
+
+	quoteReq, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:%s/credits/quote", restPort), bytes.NewBuffer(jsonData))
+	require.NoError(t, err)
+
+	quoteResp, err := st.Client.Do(quoteReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, quoteResp.StatusCode)
+
+	defer quoteResp.Body.Close()
+
+	var quote models.Quote
+	err = json.NewDecoder(quoteResp.Body).Decode(&quote)
+	require.NoError(t, err)
+
+	require.Len(t, quote.Schedule, 24)
+	require.NotZero(t, quote.TotalInterest)
+	require.Equal(t, quote.Amount+quote.TotalInterest+quote.TotalFees, quote.TotalPayment)
+	require.Greater(t, quote.APR, quote.AnnualInterestRate)
+}
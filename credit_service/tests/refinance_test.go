@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/storage"
+	"bank/credit_service/tests/suite"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestRefinance_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	consumer := storage.NewConsumerMongoDB(st.MongoClient.Database(st.Cfg.MongoDb.Dbname), st.Cfg.MongoDb.UserIDCollection)
+	userID := randomInt64()
+	require.NoError(t, consumer.NewUserIDCollection(context.Background(), userID))
+
+	productID := createProduct(st, t, restPort)
+	creditsURL := fmt.Sprintf("http://localhost:%s/credits", restPort)
+
+	var creditIDs []string
+	for _, amount := range []int{100_000, 200_000} {
+		var created map[string]models.Credit
+		doJSON(t, st, "POST", creditsURL, models.Credit{UserID: userID, ProductID: productID, Amount: amount, Currency: "RUB", Term: 12}, http.StatusOK, &created)
+		creditIDs = append(creditIDs, created["Created Credit"].ID)
+	}
+
+	//кредит другого пользователя откатывает всю операцию
+	other, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	req := models.RefinanceRequest{
+		UserID:    userID,
+		ProductID: productID,
+		CreditIDs: append(creditIDs, other.CreatedCredit.ID),
+		Currency:  "RUB",
+		Term:      24,
+	}
+	doJSON(t, st, "POST", creditsURL+"/refinance", req, http.StatusBadRequest, nil)
+
+	var credits []models.Credit
+	doJSON(t, st, "GET", fmt.Sprintf("%s/userID/%v", creditsURL, userID), nil, http.StatusOK, &credits)
+	require.Len(t, credits, 2)
+
+	req.CreditIDs = creditIDs
+
+	var refinancing models.Refinancing
+	doJSON(t, st, "POST", creditsURL+"/refinance", req, http.StatusOK, &refinancing)
+	require.Len(t, refinancing.Closed, 2)
+	require.Equal(t, 303_000, refinancing.PayoffTotal) //проценты еще не начислены,комиссия 1%
+	require.Equal(t, creditIDs, refinancing.Credit.RefinancedFrom)
+	require.GreaterOrEqual(t, refinancing.CashOut, 0)
+
+	for _, creditID := range creditIDs {
+		var credit models.Credit
+		doJSON(t, st, "GET", fmt.Sprintf("%s/objectID/%s", creditsURL, creditID), nil, http.StatusOK, &credit)
+		require.Equal(t, models.CreditStatusClosed, credit.Status)
+		require.Equal(t, refinancing.Credit.ID, credit.RefinancedBy)
+	}
+
+	doJSON(t, st, "POST", creditsURL+"/refinance", req, http.StatusConflict, nil) //кредиты уже закрыты
+}
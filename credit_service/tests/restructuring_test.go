@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/tests/suite"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRescheduleCredit(t *testing.T) {
+	kept := []models.Payment{
+		{Number: 1, Date: "2024-02-15", Payment: 2000, Principal: 1900, Interest: 100, Balance: 10100},
+		{Number: 2, Date: "2024-03-15", Payment: 2000, Principal: 1900, Interest: 100, Balance: 10000},
+	}
+	terms := service.Reschedule{
+		Principal:          10000,
+		Term:               4,
+		AnnualInterestRate: 12,
+		Scheme:             models.SchemeAnnuity,
+		MonthlyFee:         10,
+		HolidayMonths:      2,
+		Start:              time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	sum := func(payments []models.Payment, field func(models.Payment) int) int {
+		var total int
+		for _, payment := range payments {
+			total += field(payment)
+		}
+		return total
+	}
+	principal := func(payment models.Payment) int { return payment.Principal }
+	interest := func(payment models.Payment) int { return payment.Interest }
+
+	t.Run("capitalize", func(t *testing.T) {
+		terms := terms
+		terms.InterestTreatment = models.InterestCapitalize
+
+		schedule, err := service.RescheduleCredit(kept, terms)
+		require.NoError(t, err)
+		require.Len(t, schedule, 8)
+		require.Equal(t, kept, schedule[:2])
+
+		for i, payment := range schedule {
+			require.Equal(t, i+1, payment.Number)
+		}
+
+		holiday := schedule[2:4]
+		require.Equal(t, "2024-04-15", holiday[0].Date)
+		require.Equal(t, 0, holiday[0].Payment)
+		require.Equal(t, 10100, holiday[0].Balance) //1% в месяц прибавляется к долгу
+		require.Equal(t, 10201, holiday[1].Balance)
+
+		payments := schedule[4:]
+		require.Equal(t, "2024-06-15", payments[0].Date)
+		require.Equal(t, 10201, sum(payments, principal))
+		require.Equal(t, 0, payments[3].Balance)
+		require.Equal(t, 10, payments[0].Fees)
+		require.Equal(t, payments[0].Principal+payments[0].Interest+10, payments[0].Payment)
+	})
+
+	t.Run("defer", func(t *testing.T) {
+		terms := terms
+		terms.InterestTreatment = models.InterestDefer
+
+		schedule, err := service.RescheduleCredit(kept, terms)
+		require.NoError(t, err)
+		require.Len(t, schedule, 8)
+		require.Equal(t, 10000, schedule[3].Balance)
+
+		regular, err := service.BuildSchedule(10000, 4, 12, models.SchemeAnnuity, nil, terms.Start.AddDate(0, 2, 0), models.InitialPeriod{})
+		require.NoError(t, err)
+
+		payments := schedule[4:]
+		require.Equal(t, 10000, sum(payments, principal))
+		require.Equal(t, sum(regular, interest)+200, sum(payments, interest)) //проценты двух месяцев каникул
+		require.Equal(t, regular[0].Interest+50, payments[0].Interest)
+	})
+
+	t.Run("new term", func(t *testing.T) {
+		terms := terms
+		terms.HolidayMonths = 0
+		terms.Term = 6
+
+		schedule, err := service.RescheduleCredit(nil, terms)
+		require.NoError(t, err)
+		require.Len(t, schedule, 6)
+		require.Equal(t, "2024-04-15", schedule[0].Date)
+		require.Equal(t, 10000, sum(schedule, principal))
+	})
+}
+
+func TestRestructuring_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	creditID := response.CreatedCredit.ID
+	creditURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s", restPort, creditID)
+
+	var credit models.Credit
+	doJSON(t, st, "GET", creditURL, nil, http.StatusOK, &credit)
+
+	doJSON(t, st, "POST", creditURL+"/holidays",
+		models.PaymentHoliday{Months: 3, InterestTreatment: "skip", Reason: "job loss"}, http.StatusBadRequest, nil)
+
+	var holiday models.Restructuring
+	doJSON(t, st, "POST", creditURL+"/holidays",
+		models.PaymentHoliday{Months: 3, InterestTreatment: models.InterestDefer, Reason: "job loss"}, http.StatusOK, &holiday)
+	require.Equal(t, models.RestructuringHoliday, holiday.Type)
+	require.Equal(t, suite.StaffUsername, holiday.ApprovedBy) //из токена,а не из тела запроса
+	require.Equal(t, "job loss", holiday.Reason)
+	require.Len(t, holiday.OriginalSchedule, credit.Term)
+	require.Len(t, holiday.Schedule, credit.Term+3)
+	require.Equal(t, credit.Term+3, holiday.NewTerm)
+	for _, payment := range holiday.Schedule[:3] {
+		require.Equal(t, 0, payment.Payment)
+	}
+
+	var restructured models.Credit
+	doJSON(t, st, "GET", creditURL, nil, http.StatusOK, &restructured)
+	require.Equal(t, credit.Term+3, restructured.Term)
+	require.Equal(t, holiday.Schedule, restructured.Schedule)
+
+	//пересчет по условиям потерял бы график
+	doJSON(t, st, "PUT", fmt.Sprintf("http://localhost:%s/credits/%s", restPort, creditID),
+		Request{Amount: randomAmount(), Currency: "RUB", Term: randomTerm()}, http.StatusConflict, nil)
+
+	doJSON(t, st, "POST", creditURL+"/restructurings",
+		models.RestructureRequest{Reason: "lower payment"}, http.StatusBadRequest, nil)
+
+	//заемщик не может сам себе изменить условия
+	customer := st.Token(credit.UserID, "borrower", suite.RoleCustomer)
+	doJSONWithToken(t, st, customer, "POST", creditURL+"/holidays",
+		models.PaymentHoliday{Months: 3, InterestTreatment: models.InterestDefer, Reason: "job loss"}, http.StatusForbidden, nil)
+	doJSONWithToken(t, st, customer, "POST", creditURL+"/restructurings",
+		models.RestructureRequest{Term: 12, Reason: "lower payment"}, http.StatusForbidden, nil)
+
+	var restructuring models.Restructuring
+	doJSON(t, st, "POST", creditURL+"/restructurings",
+		models.RestructureRequest{Term: 12, AnnualInterestRate: 5, Reason: "lower payment"}, http.StatusOK, &restructuring)
+	require.Equal(t, models.RestructuringTerms, restructuring.Type)
+	require.Equal(t, 12, restructuring.NewTerm)
+	require.Equal(t, float64(5), restructuring.NewInterestRate)
+	require.Equal(t, credit.AnnualInterestRate, restructuring.OldInterestRate)
+	require.Len(t, restructuring.Schedule, 12)
+	require.Equal(t, 0, restructuring.Schedule[11].Balance)
+
+	var restructurings []models.Restructuring
+	doJSON(t, st, "GET", creditURL+"/restructurings", nil, http.StatusOK, &restructurings)
+	require.Len(t, restructurings, 2)
+	require.Equal(t, restructurings[0].Schedule, restructurings[1].OriginalSchedule)
+
+	var history []models.CreditHistory
+	doJSON(t, st, "GET", creditURL+"/history", nil, http.StatusOK, &history)
+	require.Equal(t, models.ActionRestructure, history[len(history)-1].Action)
+
+	var check models.LedgerCheck
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/ledger/check", restPort), nil, http.StatusOK, &check)
+	require.True(t, check.Balanced)
+}
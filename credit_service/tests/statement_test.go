@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"bank/credit_service/internal/document"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/service"
+	"bank/credit_service/tests/suite"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/sfnt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildStatement(t *testing.T) {
+	const creditID = "65a4f0c2e4b0a1b2c3d4e5f6"
+
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	entry := func(id, kind string, date time.Time, lines ...models.EntryLine) models.JournalEntry {
+		for i := range lines {
+			lines[i].CreditID = creditID
+		}
+		return models.JournalEntry{ID: id, Type: kind, CreditID: creditID, ValueDate: date, Lines: lines}
+	}
+
+	entries := []models.JournalEntry{
+		entry("d", models.EntryDisbursement, day(time.January, 10),
+			models.EntryLine{Account: models.AccountLoan, Debit: 100_000}),
+		entry("a1", models.EntryAccrual, day(time.January, 31),
+			models.EntryLine{Account: models.AccountInterestReceivable, Debit: 500}),
+		entry("a2", models.EntryAccrual, day(time.February, 5),
+			models.EntryLine{Account: models.AccountInterestReceivable, Debit: 100}),
+		entry("r", models.EntryRepayment, day(time.February, 10),
+			models.EntryLine{Account: models.AccountLoan, Credit: 9_000},
+			models.EntryLine{Account: models.AccountInterestReceivable, Credit: 600}),
+		entry("p", models.EntryPenalty, day(time.February, 12),
+			models.EntryLine{Account: models.AccountPenaltyReceivable, Debit: 300}),
+		entry("a3", models.EntryAccrual, day(time.February, 20),
+			models.EntryLine{Account: models.AccountInterestReceivable, Debit: 200}),
+		entry("c", models.EntryCapitalization, day(time.February, 25),
+			models.EntryLine{Account: models.AccountLoan, Debit: 200},
+			models.EntryLine{Account: models.AccountInterestReceivable, Credit: 200}),
+		entry("a4", models.EntryAccrual, day(time.March, 1),
+			models.EntryLine{Account: models.AccountInterestReceivable, Debit: 100}),
+	}
+
+	credit := models.Credit{ID: creditID, UserID: 7, Currency: "RUB"}
+	statement := service.BuildStatement(credit, entries, day(time.February, 1), day(time.March, 1))
+
+	require.Equal(t, models.DebtBalance{Principal: 100_000, Interest: 500, Total: 100_500}, statement.OpeningBalance)
+	require.Equal(t, 0, statement.Disbursed)
+	require.Equal(t, 9_600, statement.Payments)
+	require.Equal(t, 300, statement.Interest)
+	require.Equal(t, 300, statement.Penalties)
+	require.Equal(t, 0, statement.Adjustments)
+	require.Equal(t, models.DebtBalance{Principal: 91_200, Penalties: 300, Total: 91_500}, statement.ClosingBalance)
+
+	//начисления за день не перечисляются
+	types := make([]string, 0, len(statement.Transactions))
+	for _, tx := range statement.Transactions {
+		types = append(types, tx.Type)
+	}
+	require.Equal(t, []string{models.EntryRepayment, models.EntryPenalty, models.EntryCapitalization}, types)
+
+	closing := statement.OpeningBalance.Total + statement.Disbursed + statement.Interest + statement.Penalties +
+		statement.Adjustments - statement.Payments
+	require.Equal(t, statement.ClosingBalance.Total, closing)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, document.WriteStatementsCSV(&buf, []models.CreditStatement{statement}))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 1+1+len(statement.Transactions)+1+1) //заголовок,входящий остаток,проценты,исходящий остаток
+
+		require.Equal(t, "opening_balance", rows[1][3])
+		require.Equal(t, "100500", rows[1][8])
+		require.Equal(t, "closing_balance", rows[len(rows)-1][3])
+		require.Equal(t, "2024-02-29", rows[len(rows)-1][2])
+		require.Equal(t, "91500", rows[len(rows)-1][8])
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		cyrillic := statement
+		cyrillic.Transactions = slices.Clone(statement.Transactions)
+		cyrillic.Transactions[0].Description = "Погашение по графику"
+
+		var buf bytes.Buffer
+		require.NoError(t, document.WriteStatementsPDF(&buf, []models.CreditStatement{cyrillic}))
+
+		content := buf.String()
+		require.True(t, strings.HasPrefix(content, "%PDF-"))
+		require.True(t, strings.HasSuffix(content, "%%EOF\n"))
+		require.Contains(t, content, "/FontFile2")
+		require.Contains(t, content, pdfText(t, "Statement of credit "+creditID))
+		require.Contains(t, content, pdfText(t, "Closing balance: 91500"))
+		require.Contains(t, content, pdfText(t, "Погашение по графику")) //кириллица не заменяется на '?'
+		require.Contains(t, content, "> <041F>\n")                       //П в ToUnicode,текст можно скопировать
+	})
+}
+
+func TestStatements_OK(t *testing.T) {
+	st, _, killDB, closeDB, killKafkaContainer, restPort, err := suite.New(t)
+	require.NoError(t, err)
+
+	defer func() {
+		killDB()
+		closeDB()
+		killKafkaContainer()
+	}()
+
+	response, closeReq := getIdForReq(st, t, restPort)
+	defer func() {
+		if err = closeReq(); err != nil {
+			t.Errorf("failed to close request body: %v", err)
+		}
+	}()
+
+	credit := response.CreatedCredit
+	statementURL := fmt.Sprintf("http://localhost:%s/credits/objectID/%s/statement", restPort, credit.ID)
+
+	var statement models.CreditStatement
+	doJSON(t, st, "GET", statementURL, nil, http.StatusOK, &statement)
+	require.Equal(t, credit.ID, statement.CreditID)
+	require.Positive(t, statement.Disbursed)
+	require.Equal(t, statement.Disbursed, statement.ClosingBalance.Principal)
+
+	var userStatement models.UserStatement
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/userID/%v/statement", restPort, credit.UserID), nil, http.StatusOK, &userStatement)
+	require.Len(t, userStatement.Credits, 1)
+	require.Equal(t, statement.ClosingBalance, userStatement.Credits[0].ClosingBalance)
+
+	for format, contentType := range map[string]string{"csv": "text/csv", "pdf": "application/pdf"} {
+		resp, err := st.Client.Get(statementURL + "?format=" + format)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), contentType))
+		require.NotEmpty(t, content)
+	}
+
+	doJSON(t, st, "GET", statementURL+"?format=xml", nil, http.StatusBadRequest, nil)
+	doJSON(t, st, "GET", statementURL+"?from=2024-02-10&to=2024-02-01", nil, http.StatusBadRequest, nil)
+
+	//текущий месяц еще не закончился
+	now := time.Now().UTC()
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/statements?month=%s", restPort, now.Format("2006-01")), nil, http.StatusBadRequest, nil)
+
+	var report models.StatementReport
+	doJSON(t, st, "POST", fmt.Sprintf("http://localhost:%s/admin/statements", restPort), nil, http.StatusOK, &report)
+	require.Equal(t, 0, report.Generated) //кредит выдан в этом месяце
+
+	doJSON(t, st, "GET", fmt.Sprintf("http://localhost:%s/credits/objectID/%s/statements", restPort, credit.ID), nil, http.StatusNotFound, nil)
+}
+
+// pdfText is the text as glyph ids of the embedded Go Mono,as pdf content streams show it
+func pdfText(t *testing.T, text string) string {
+	f, err := sfnt.Parse(gomono.TTF)
+	require.NoError(t, err)
+
+	var buf sfnt.Buffer
+	var hex strings.Builder
+	for _, r := range text {
+		glyph, err := f.GlyphIndex(&buf, r)
+		require.NoError(t, err)
+		require.NotZero(t, glyph, string(r))
+		fmt.Fprintf(&hex, "%04X", glyph)
+	}
+	return hex.String()
+}
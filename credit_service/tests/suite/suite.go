@@ -5,12 +5,14 @@ import (
 	"bank/credit_service/internal/config"
 	"context"
 	"fmt"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
 	"net"
 	"net/http"
 	"strings"
@@ -59,27 +61,68 @@ func New(t *testing.T) (st *Suite, ctx context.Context, killMongoDBContainer, cl
 
 	time.Sleep(time.Second * 1) //for stop
 
-	client := &http.Client{}
 	st = &Suite{
 		Cfg:         cfg,
 		t:           t,
-		Client:      client,
 		MongoClient: mongoClient,
 	}
+	st.Client = &http.Client{Transport: staffTransport{token: st.Token(1, StaffUsername, RoleStaff)}}
 
 	return st, ctx, killMongoDBContainer, closeTestDbConnection, killKafkaContainer, cfg.Rest.Port, err
 }
 
+const (
+	RoleStaff    = "staff"
+	RoleCustomer = "customer"
+
+	StaffUsername = "staff" //сотрудник,от имени которого Client шлет запросы без своего токена
+)
+
+// Token is an access token of the user like the ones auth_service issues
+func (s *Suite) Token(userID int64, username, role string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userId":   userID,
+		"username": username,
+		"role":     role,
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+
+	signed, err := token.SignedString([]byte(s.Cfg.Auth.SecretKey))
+	if err != nil {
+		s.t.Fatalf("sign access token failed:%s", err)
+	}
+
+	return signed
+}
+
+// staffTransport authorizes requests that have no token of their own as a staff member
+type staffTransport struct {
+	token string
+}
+
+func (t staffTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
 func ConnToTestMongoDB(cfg *config.Config, ctx context.Context, t *testing.T) (func(), func(), *mongo.Client, error) {
 	mongoContainer, err := mongodb.RunContainer(ctx,
 		testcontainers.WithImage("mongo:6"),
 		mongodb.WithUsername(cfg.MongoDb.Username),
 		mongodb.WithPassword(cfg.MongoDb.Password),
+		withReplicaSet(),
 	)
 	if err != nil {
 		t.Fatalf("failed to start container in testmongo: %s", err)
 	}
 
+	if err = initReplicaSet(ctx, mongoContainer, cfg); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to init replica set: %v", err)
+	}
+
 	mongoPort, err := mongoContainer.MappedPort(ctx, "27017")
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to get mapped port for MongoDB container: %v", err)
@@ -94,7 +137,7 @@ func ConnToTestMongoDB(cfg *config.Config, ctx context.Context, t *testing.T) (f
 		Password: cfg.MongoDb.Password,
 	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).SetAuth(credentials))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).SetAuth(credentials).SetDirect(true))
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create TestMongoDB client: %v", err)
 	}
@@ -113,6 +156,43 @@ func ConnToTestMongoDB(cfg *config.Config, ctx context.Context, t *testing.T) (f
 	return killMongoDBContainer, closeTestDbConnection, client, nil
 }
 
+// withReplicaSet starts mongo as a single node replica set,transactions don't work on a standalone server.
+// A replica set with auth needs a key file,it's generated on start
+func withReplicaSet() testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) {
+		req.Entrypoint = []string{"bash", "-c"}
+		req.Cmd = []string{"head -c 756 /dev/urandom | base64 > /tmp/keyfile && chmod 400 /tmp/keyfile && chown mongodb:mongodb /tmp/keyfile && " +
+			"exec docker-entrypoint.sh mongod --replSet rs0 --keyFile /tmp/keyfile --bind_ip_all"}
+	}
+}
+
+// initReplicaSet initiates the replica set and waits until the node becomes primary
+func initReplicaSet(ctx context.Context, container *mongodb.MongoDBContainer, cfg *config.Config) error {
+	mongosh := func(script string) (string, error) {
+		_, out, err := container.Exec(ctx, []string{"mongosh", "--quiet",
+			"-u", cfg.MongoDb.Username, "-p", cfg.MongoDb.Password, "--authenticationDatabase", "admin", "--eval", script})
+		if err != nil {
+			return "", err
+		}
+		res, err := io.ReadAll(out)
+		return string(res), err
+	}
+
+	if _, err := mongosh("rs.initiate({_id: 'rs0', members: [{_id: 0, host: 'localhost:27017'}]})"); err != nil {
+		return err
+	}
+
+	for i := 0; i < 60; i++ {
+		out, err := mongosh("db.hello().isWritablePrimary")
+		if err == nil && strings.Contains(out, "true") {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("node didn't become primary")
+}
+
 func NewTestKafka(ctx context.Context, cfg *config.Config, t *testing.T) func() {
 	/*var mu = &sync.Mutex{}
 	mu.Lock()
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bank/credit_service/internal/config"
+	"bank/credit_service/internal/domain/models"
+	"bank/credit_service/internal/validation"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testLimits = config.Validation{
+	MinAmount:       1000,
+	MaxAmount:       100_000_000,
+	MinTerm:         1,
+	MaxTerm:         360,
+	Currencies:      []string{"RUB", "USD", "EUR"},
+	MaxInterestRate: 100,
+}
+
+func TestValidator_AllViolations(t *testing.T) {
+	v := validation.New(testLimits)
+
+	err := v.Struct(&models.Credit{
+		UserID:        randomInt64(),
+		ProductID:     randomHex(),
+		Amount:        -1,
+		Currency:      "GBP",
+		Term:          0,
+		Scheme:        "balloon",
+		OperationType: "create",
+	})
+	require.Error(t, err)
+
+	validationErr, ok := err.(*models.ValidationError)
+	require.True(t, ok)
+	require.Equal(t, []models.FieldError{
+		{Field: "Amount", Message: "the 'Amount' value must be between 1000 and 100000000"},
+		{Field: "Currency", Message: "the 'Currency' value must be one of RUB,USD,EUR"},
+		{Field: "Term", Message: "you must fill the 'Term' value"},
+		{Field: "Scheme", Message: "the 'Scheme' value must be one of annuity differentiated"},
+	}, validationErr.Fields)
+}
+
+func TestValidator_FieldPaths(t *testing.T) {
+	v := validation.New(testLimits)
+
+	err := v.Struct(&models.Product{
+		Name:       randomString(10),
+		Type:       models.ProductConsumerLoan,
+		Currencies: []string{"RUB", "XXX"},
+		MinAmount:  1000,
+		MaxAmount:  1_000_000,
+		MinTerm:    1,
+		MaxTerm:    60,
+		RateGrid: []models.RateGridEntry{
+			{MinTerm: 1, MaxTerm: 60, MinAmount: 1000, MaxAmount: 1_000_000, AnnualInterestRate: 12},
+			{MinTerm: 1, MaxTerm: 60, MinAmount: 1000, MaxAmount: 1_000_000, AnnualInterestRate: 5000},
+		},
+		ApprovalThresholds: map[string]int{"RUB": 500_000, "GBP": 100},
+	})
+	require.Error(t, err)
+
+	validationErr, ok := err.(*models.ValidationError)
+	require.True(t, ok)
+	require.Len(t, validationErr.Fields, 3)
+	require.Equal(t, "Currencies[1]", validationErr.Fields[0].Field)
+	require.Equal(t, "RateGrid[1].AnnualInterestRate", validationErr.Fields[1].Field)
+	require.Equal(t, "ApprovalThresholds[GBP]", validationErr.Fields[2].Field)
+}
+
+func TestValidator_Bounds(t *testing.T) {
+	v := validation.New(testLimits)
+
+	for _, tt := range []struct {
+		months  int
+		message string
+	}{
+		{-1, "the 'Months' value must be greater than 0"},
+		{13, "the 'Months' value must be at most 12"},
+	} {
+		err := v.Struct(&models.PaymentHoliday{Months: tt.months, InterestTreatment: models.InterestDefer, Reason: "job loss"})
+		require.Error(t, err)
+
+		validationErr, ok := err.(*models.ValidationError)
+		require.True(t, ok)
+		require.Equal(t, []models.FieldError{{Field: "Months", Message: tt.message}}, validationErr.Fields)
+	}
+}
+
+func TestValidator_OK(t *testing.T) {
+	v := validation.New(testLimits)
+
+	require.NoError(t, v.Struct(&models.QuoteRequest{
+		ProductID: randomHex(),
+		Amount:    randomAmount(),
+		Currency:  "EUR",
+		Term:      randomTerm(),
+	}))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bank/notification_service/internal/app"
+	"bank/notification_service/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	logger := logrus.New()
+	logger.SetLevel(logrus.DebugLevel)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	cfg, err := config.InitConfig()
+	if err != nil {
+		logger.Fatalf("init config failed:%s", err)
+	}
+
+	app.Run(cfg, logger)
+}
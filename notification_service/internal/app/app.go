@@ -0,0 +1,108 @@
+package app
+
+import (
+	"bank/notification_service/internal/channel"
+	"bank/notification_service/internal/config"
+	"bank/notification_service/internal/kafka/consumer"
+	"bank/notification_service/internal/rest"
+	"bank/notification_service/internal/service"
+	"bank/notification_service/internal/storage"
+	"bank/notification_service/internal/templates"
+	"bank/notification_service/pkg/mongodb"
+	"context"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func Run(cfg *config.Config, logger *logrus.Logger) {
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logger)
+
+	db, err := mongodb.ConnToMongoDB(cfg)
+	if err != nil {
+		logger.Fatalf("connect to mongo failed:%s", err)
+	}
+
+	defer func() {
+		if err = db.Client().Disconnect(context.Background()); err != nil {
+			logger.Fatalf("mongodb close failed:%s", err)
+		}
+		logger.Info("mongodb connection closed")
+	}()
+
+	senders, err := channel.NewSenders(cfg.Channels, logger)
+	if err != nil {
+		logger.Fatalf("init notification channels failed:%s", err)
+	}
+
+	tmpl, err := templates.New()
+	if err != nil {
+		logger.Fatalf("init templates failed:%s", err)
+	}
+
+	storages := storage.NewStorage(db, cfg.MongoDb)
+	services := service.NewService(logger, storages, senders, tmpl, cfg.Reminders)
+	handlers := rest.NewHandler(logger, services)
+	kc := consumer.NewKafkaConsumer(services)
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.Rest.Port,
+		Handler: handlers.InitRoutes(r),
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		logger.Infof("kafka consumer starting:%s", cfg.Kafka.Brokers)
+		if err = kc.KafkaConsumer(ctx, cfg, logger); err != nil {
+			logger.Fatalf("kafka consumer failed:%s", err)
+		}
+	}()
+
+	go runReminders(ctx, services, cfg.Reminders.Interval, logger)
+
+	go func() {
+		logger.Infof("rest starting on port:%s", cfg.Rest.Port)
+		if err = srv.ListenAndServe(); err != nil && !errors.Is(http.ErrServerClosed, err) {
+			logger.Fatalf("run application failed:%s", err)
+		}
+	}()
+
+	<-stop
+
+	cancel()
+
+	if err = srv.Shutdown(context.Background()); err != nil {
+		logger.Fatalf("shutdown rest failed:%s", err)
+	}
+
+	logger.Info("notification service stopped")
+}
+
+// runReminders checks payment due dates right after the start and then every interval
+func runReminders(ctx context.Context, services *service.Service, interval time.Duration, logger *logrus.Logger) {
+	for {
+		if err := services.SendReminders(ctx, time.Now().UTC()); err != nil {
+			logger.Errorf("failed to send reminders:%s", err)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			logger.Info("reminders stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package channel
+
+import (
+	"bank/notification_service/internal/config"
+	"bank/notification_service/internal/domain/models"
+	"bank/notification_service/internal/service"
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	TypeLog  = "log"
+	TypeFile = "file"
+)
+
+// NewSenders builds the sender of every channel from the config.
+// Email,SMS and push providers plug in here as new types,log and file senders are for local use
+func NewSenders(cfg config.Channels, logger *logrus.Logger) (map[string]service.Sender, error) {
+	channels := map[string]config.Channel{
+		models.ChannelEmail: cfg.Email,
+		models.ChannelSMS:   cfg.SMS,
+		models.ChannelPush:  cfg.Push,
+	}
+
+	senders := make(map[string]service.Sender, len(channels))
+
+	for name, channelCfg := range channels {
+		switch channelCfg.Type {
+		case TypeLog:
+			senders[name] = NewLogSender(logger)
+		case TypeFile:
+			senders[name] = NewFileSender(channelCfg.Path)
+		default:
+			return nil, fmt.Errorf("%w:channel %s has type '%s'", models.ErrUnknownChannel, name, channelCfg.Type)
+		}
+	}
+
+	return senders, nil
+}
@@ -0,0 +1,42 @@
+package channel
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSender appends messages to a file as JSON lines
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) Send(_ context.Context, msg models.Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message failed:%s", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open file failed:%s", err)
+	}
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write message failed:%s", err)
+	}
+
+	return file.Close()
+}
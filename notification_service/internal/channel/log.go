@@ -0,0 +1,26 @@
+package channel
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+// LogSender writes messages to the logger instead of sending them
+type LogSender struct {
+	logger *logrus.Logger
+}
+
+func NewLogSender(logger *logrus.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(_ context.Context, msg models.Message) error {
+	s.logger.WithFields(logrus.Fields{
+		"channel": msg.Channel,
+		"to":      msg.To,
+		"userID":  msg.UserID,
+		"subject": msg.Subject,
+	}).Info(msg.Body)
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"time"
+)
+
+type Config struct {
+	Rest      Rest
+	MongoDb   MongoDb
+	Kafka     Kafka
+	Reminders Reminders
+	Channels  Channels
+}
+
+type Rest struct {
+	Port string
+}
+
+type MongoDb struct {
+	Host                 string
+	Port                 string
+	Dbname               string
+	CreditCollection     string //кредиты,собранные из событий credit_service
+	PreferenceCollection string
+	SentCollection       string //отправленные напоминания,для дедупликации
+	Username             string
+	Password             string
+}
+
+type Kafka struct {
+	Brokers string
+	GroupID string
+	Topics  []string //топики событий кредитов
+}
+
+type Reminders struct {
+	Interval        time.Duration //как часто проверяются сроки платежей
+	RemindBefore    time.Duration //за сколько до даты платежа напоминать
+	DefaultLanguage string        //для пользователей без настроек
+	DefaultChannels []string
+}
+
+type Channels struct {
+	Email Channel
+	SMS   Channel
+	Push  Channel
+}
+
+// Channel selects the implementation of a notification channel:log writes to the logger,file appends to Path
+type Channel struct {
+	Type string
+	Path string
+}
+
+func InitConfig() (*Config, error) {
+	return InitConfigByPath("config/local.yml")
+}
+
+func InitConfigByPath(configPath string) (*Config, error) {
+	var cfg Config
+
+	viper.SetConfigFile(configPath)
+
+	viper.SetDefault("reminders.interval", time.Hour)
+	viper.SetDefault("reminders.remind_before", 72*time.Hour)
+	viper.SetDefault("reminders.default_language", "ru")
+	viper.SetDefault("reminders.default_channels", []string{"email"})
+	viper.SetDefault("channels.email.type", "log")
+	viper.SetDefault("channels.sms.type", "log")
+	viper.SetDefault("channels.push.type", "log")
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config failed:%s", err)
+	}
+
+	cfg = Config{
+		Rest: Rest{
+			Port: viper.GetString("rest.port"),
+		},
+		MongoDb: MongoDb{
+			Host:                 viper.GetString("mongodb.host"),
+			Port:                 viper.GetString("mongodb.port"),
+			Dbname:               viper.GetString("mongodb.dbname"),
+			CreditCollection:     viper.GetString("mongodb.credit_collection"),
+			PreferenceCollection: viper.GetString("mongodb.preference_collection"),
+			SentCollection:       viper.GetString("mongodb.sent_collection"),
+			Username:             viper.GetString("mongodb.username"),
+			Password:             viper.GetString("mongodb.password"),
+		},
+		Kafka: Kafka{
+			Brokers: viper.GetString("kafka.brokers"),
+			GroupID: viper.GetString("kafka.group_id"),
+			Topics:  viper.GetStringSlice("kafka.topics"),
+		},
+		Reminders: Reminders{
+			Interval:        viper.GetDuration("reminders.interval"),
+			RemindBefore:    viper.GetDuration("reminders.remind_before"),
+			DefaultLanguage: viper.GetString("reminders.default_language"),
+			DefaultChannels: viper.GetStringSlice("reminders.default_channels"),
+		},
+		Channels: Channels{
+			Email: channel("email"),
+			SMS:   channel("sms"),
+			Push:  channel("push"),
+		},
+	}
+	return &cfg, nil
+}
+
+func channel(name string) Channel {
+	return Channel{
+		Type: viper.GetString("channels." + name + ".type"),
+		Path: viper.GetString("channels." + name + ".path"),
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	CreditStatusActive    = "active"
+	CreditStatusClosed    = "closed"
+	CreditStatusCancelled = "cancelled"
+)
+
+// Credit is what the service knows about a credit from its events
+type Credit struct {
+	ID             string             `bson:"_id"`
+	UserID         int64              `bson:"userID"`
+	Currency       string             `bson:"currency"`
+	MonthlyPayment int                `bson:"monthlyPayment"`
+	Term           int                `bson:"term"`
+	MaturesAt      time.Time          `bson:"maturesAt"`
+	Schedule       []ScheduledPayment `bson:"schedule,omitempty"` //нет в событиях до графика
+	Status         string             `bson:"status"`
+	StatusAt       time.Time          `bson:"statusAt"`   //время события,которое поставило статус
+	PaidAmount     int                `bson:"paidAmount"` //сумма PaymentReceived
+	Events         []string           `bson:"events"`     //id примененных событий,для дедупликации
+}
+
+// ScheduledPayment is one payment of the credit schedule,zero Amount is a month of a payment holiday
+type ScheduledPayment struct {
+	Number  int       `bson:"number"`
+	DueDate time.Time `bson:"dueDate"`
+	Amount  int       `bson:"amount"`
+}
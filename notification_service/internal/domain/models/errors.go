@@ -0,0 +1,12 @@
+package models
+
+import "errors"
+
+var (
+	ErrInvalidRequest     = errors.New("invalid request")
+	ErrPreferenceNotFound = errors.New("no notification preferences found")
+	ErrAlreadySent        = errors.New("reminder already sent")
+	ErrUnknownChannel     = errors.New("unknown notification channel")
+	ErrUnknownTemplate    = errors.New("unknown message template")
+	ErrUnsupportedEvent   = errors.New("unsupported event schema version")
+)
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	EventCreditCreated       = "CreditCreated"
+	EventCreditUpdated       = "CreditUpdated"
+	EventCreditStatusChanged = "CreditStatusChanged"
+	EventPaymentReceived     = "PaymentReceived"
+	EventCreditClosed        = "CreditClosed"
+)
+
+// EventSchemaVersion is the version of credit_service events this service understands
+const EventSchemaVersion = 1
+
+// CreditEvent is a domain event published by credit_service
+type CreditEvent struct {
+	ID         string
+	Type       string
+	Version    int
+	CreditID   string
+	UserID     int64
+	Actor      string
+	OccurredAt time.Time
+	Data       CreditEventData
+}
+
+type CreditEventData struct {
+	ProductID            string
+	Amount               int
+	Currency             string
+	Term                 int
+	AnnualInterestRate   float64
+	MonthlyPayment       int
+	OutstandingPrincipal int
+	MaturesAt            time.Time
+	Status               string
+	PreviousStatus       string
+	PaymentAmount        int
+	Schedule             []ScheduledPayment //график из кредитного сервиса
+}
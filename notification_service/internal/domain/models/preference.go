@@ -0,0 +1,11 @@
+package models
+
+// Preference is how the user wants to get notifications
+type Preference struct {
+	UserID      int64    `bson:"_id"`
+	Language    string   `bson:"language" validate:"required,oneof=ru en"`
+	Channels    []string `bson:"channels" validate:"required,dive,oneof=email sms push"`
+	Email       string   `bson:"email" validate:"omitempty,email"`
+	Phone       string   `bson:"phone"`
+	DeviceToken string   `bson:"deviceToken"` //для push
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ReminderUpcoming = "upcoming" //скоро дата платежа
+	ReminderOverdue  = "overdue"  //платеж просрочен
+)
+
+const (
+	LanguageRU = "ru"
+	LanguageEN = "en"
+)
+
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+type Reminder struct {
+	Kind        string
+	CreditID    string
+	UserID      int64
+	Installment int //номер платежа по графику
+	DueDate     time.Time
+	Amount      int
+	Currency    string
+}
+
+// Key identifies the reminder sent through the channel,the same key is never sent twice
+func (r Reminder) Key(channel string) string {
+	return fmt.Sprintf("%s:%d:%s:%s", r.CreditID, r.Installment, r.Kind, channel)
+}
+
+type Message struct {
+	Channel string
+	To      string
+	UserID  int64
+	Subject string
+	Body    string
+}
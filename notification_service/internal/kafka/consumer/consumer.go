@@ -0,0 +1,65 @@
+package consumer
+
+import (
+	"bank/notification_service/internal/config"
+	"bank/notification_service/internal/domain/models"
+	"bank/notification_service/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+type KafkaConsumer struct {
+	service *service.Service
+}
+
+func NewKafkaConsumer(service *service.Service) *KafkaConsumer {
+	return &KafkaConsumer{service: service}
+}
+
+// KafkaConsumer reads credit events and commits them only after they were handled
+func (k *KafkaConsumer) KafkaConsumer(ctx context.Context, cfg *config.Config, logger *logrus.Logger) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{cfg.Kafka.Brokers},
+		GroupID:     cfg.Kafka.GroupID,
+		GroupTopics: cfg.Kafka.Topics,
+	})
+
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Errorf("failed to close kafka reader:%s", err)
+			return
+		}
+		logger.Info("kafka reader closed")
+	}()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if errors.Is(err, context.Canceled) {
+			logger.Info("kafka consumer shutting down...")
+			return nil
+		}
+		if err != nil {
+			logger.Errorf("Error reading message from Kafka: %v", err)
+			return err
+		}
+
+		var event models.CreditEvent
+
+		if err = json.Unmarshal(msg.Value, &event); err != nil {
+			logger.Errorf("skip malformed event at %s/%v:%s", msg.Topic, msg.Offset, err) //повторное чтение его не исправит
+		} else if err = k.service.HandleEvent(ctx, event); err != nil {
+			if !errors.Is(err, models.ErrUnsupportedEvent) {
+				return err
+			}
+			logger.Errorf("skip event %s:%s", event.ID, err)
+		}
+
+		if err = reader.CommitMessages(ctx, msg); err != nil {
+			logger.Errorf("failed to commit message:%s", err)
+			return err
+		}
+	}
+}
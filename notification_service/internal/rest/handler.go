@@ -0,0 +1,21 @@
+package rest
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+type Service interface {
+	SavePreference(ctx context.Context, preference models.Preference) error
+	GetPreference(ctx context.Context, userID int64) (models.Preference, error)
+}
+
+func (h *Handler) InitRoutes(r *chi.Mux) http.Handler {
+	r.Route("/preferences", func(r chi.Router) {
+		r.Get("/{userID}", h.GetPreference())
+		r.Put("/{userID}", h.SavePreference())
+	})
+	return r
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type Handler struct {
+	logger   *logrus.Logger
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(logger *logrus.Logger, service Service) *Handler {
+	return &Handler{
+		logger:   logger,
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+func (h *Handler) SavePreference() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		userID, err := userIDFromURL(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		var preference models.Preference
+
+		if err = json.NewDecoder(r.Body).Decode(&preference); err != nil {
+			h.writeError(w, r, fmt.Errorf("%w:failed to decode request body:%s", models.ErrInvalidRequest, err))
+			return
+		}
+
+		preference.UserID = userID
+
+		if err = h.validate.Struct(preference); err != nil {
+			h.writeError(w, r, fmt.Errorf("%w:%s", models.ErrInvalidRequest, err))
+			return
+		}
+
+		if err = h.service.SavePreference(r.Context(), preference); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, map[string]interface{}{"Saved Preference": preference})
+	}
+}
+
+func (h *Handler) GetPreference() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		userID, err := userIDFromURL(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		preference, err := h.service.GetPreference(r.Context(), userID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, preference)
+	}
+}
+
+func userIDFromURL(r *http.Request) (int64, error) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w:userID must be a number:%s", models.ErrInvalidRequest, err)
+	}
+	return userID, nil
+}
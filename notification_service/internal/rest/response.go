@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+type ErrorResponse struct {
+	Error ErrorBody
+}
+
+type ErrorBody struct {
+	Code      string
+	Message   string
+	RequestID string `json:",omitempty"`
+}
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+var errorMappings = []errorMapping{
+	{models.ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{models.ErrPreferenceNotFound, http.StatusNotFound, "preference_not_found"},
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	body := ErrorBody{
+		Code:      "internal_error",
+		Message:   "internal server error",
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	status := http.StatusInternalServerError
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			status = mapping.status
+			body.Code = mapping.code
+			body.Message = err.Error()
+			break
+		}
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.Errorf("request %s failed:%s", body.RequestID, err)
+	} else {
+		h.logger.Infof("request %s rejected:%s", body.RequestID, err)
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, ErrorResponse{Error: body})
+}
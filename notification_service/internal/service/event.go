@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"fmt"
+)
+
+// HandleEvent updates the credit the reminders are built from.
+// Redelivered events are ignored by the storage,events of different topics may come in any order
+func (s *Service) HandleEvent(ctx context.Context, event models.CreditEvent) error {
+	if event.Version != models.EventSchemaVersion {
+		return fmt.Errorf("%w:%v", models.ErrUnsupportedEvent, event.Version)
+	}
+
+	var err error
+
+	switch event.Type {
+	case models.EventCreditCreated, models.EventCreditUpdated:
+		err = s.saveCredit(ctx, event)
+	case models.EventCreditStatusChanged, models.EventCreditClosed:
+		if len(event.Data.Schedule) > 0 { //условия тоже могли измениться,одобренный кредит выдается с новым графиком
+			err = s.saveCredit(ctx, event)
+			break
+		}
+		err = s.storage.SetCreditStatus(ctx, event.ID, event.CreditID, event.Data.Status, event.OccurredAt)
+	case models.EventPaymentReceived:
+		err = s.storage.AddPayment(ctx, event.ID, event.CreditID, event.Data.PaymentAmount)
+	default:
+		s.logger.Infof("skip event %s of type %s", event.ID, event.Type)
+		return nil
+	}
+
+	if err != nil {
+		s.logger.Errorf("failed to handle event %s:%s", event.ID, err)
+		return err
+	}
+
+	s.logger.Infof("event %s of credit %s handled", event.Type, event.CreditID)
+
+	return nil
+}
+
+// saveCredit saves the terms and the schedule of the credit from the event
+func (s *Service) saveCredit(ctx context.Context, event models.CreditEvent) error {
+	return s.storage.SaveCredit(ctx, event.ID, models.Credit{
+		ID:             event.CreditID,
+		UserID:         event.UserID,
+		Currency:       event.Data.Currency,
+		MonthlyPayment: event.Data.MonthlyPayment,
+		Term:           event.Data.Term,
+		MaturesAt:      event.Data.MaturesAt,
+		Schedule:       event.Data.Schedule,
+		Status:         event.Data.Status,
+	}, event.OccurredAt)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+)
+
+func (s *Service) SavePreference(ctx context.Context, preference models.Preference) error {
+	s.logger.Info("received save preference req")
+
+	if err := s.storage.SavePreference(ctx, preference); err != nil {
+		s.logger.Errorf("failed to save preference:%s", err)
+		return err
+	}
+
+	s.logger.Info("preference saved")
+
+	return nil
+}
+
+func (s *Service) GetPreference(ctx context.Context, userID int64) (models.Preference, error) {
+	s.logger.Info("received get preference req")
+
+	preference, err := s.storage.GetPreference(ctx, userID)
+	if err != nil {
+		s.logger.Errorf("failed to get preference:%s", err)
+		return models.Preference{}, err
+	}
+
+	s.logger.Info("preference got")
+
+	return preference, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SendReminders reminds about the next unpaid installment of every active credit
+func (s *Service) SendReminders(ctx context.Context, now time.Time) error {
+	credits, err := s.storage.GetActiveCredits(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get active credits:%s", err)
+		return err
+	}
+
+	for _, credit := range credits {
+		reminder, ok := DueReminder(credit, now, s.cfg.RemindBefore)
+		if !ok {
+			continue
+		}
+
+		if err = s.notify(ctx, reminder, now); err != nil {
+			s.logger.Errorf("failed to remind about credit %s:%s", credit.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// DueReminder returns the reminder about the next unpaid installment if it's due within remindBefore or already overdue.
+// Installments come from the schedule of the credit service,payments cover them in order
+func DueReminder(credit models.Credit, now time.Time, remindBefore time.Duration) (models.Reminder, bool) {
+	schedule := credit.Schedule
+	if len(schedule) == 0 {
+		schedule = annuitySchedule(credit)
+	}
+
+	var due int //сумма платежей по графику до этого платежа включительно
+
+	for _, payment := range schedule {
+		due += payment.Amount
+		if payment.Amount <= 0 || due <= credit.PaidAmount { //каникулы или уже оплачен
+			continue
+		}
+
+		reminder := models.Reminder{
+			CreditID:    credit.ID,
+			UserID:      credit.UserID,
+			Installment: payment.Number,
+			DueDate:     payment.DueDate,
+			Amount:      min(payment.Amount, due-credit.PaidAmount), //частично оплаченный платеж
+			Currency:    credit.Currency,
+		}
+
+		switch {
+		case now.After(reminder.DueDate):
+			reminder.Kind = models.ReminderOverdue
+		case reminder.DueDate.Sub(now) <= remindBefore:
+			reminder.Kind = models.ReminderUpcoming
+		default:
+			return models.Reminder{}, false
+		}
+
+		return reminder, true
+	}
+
+	return models.Reminder{}, false
+}
+
+// annuitySchedule is the schedule of credits whose events came without it:equal monthly payments,the last one on the maturity date
+func annuitySchedule(credit models.Credit) []models.ScheduledPayment {
+	if credit.MonthlyPayment <= 0 {
+		return nil
+	}
+
+	schedule := make([]models.ScheduledPayment, 0, credit.Term)
+	for i := 1; i <= credit.Term; i++ {
+		schedule = append(schedule, models.ScheduledPayment{
+			Number:  i,
+			DueDate: credit.MaturesAt.AddDate(0, i-credit.Term, 0),
+			Amount:  credit.MonthlyPayment,
+		})
+	}
+
+	return schedule
+}
+
+// notify sends the reminder through every channel the user chose,each channel gets it at most once
+func (s *Service) notify(ctx context.Context, reminder models.Reminder, now time.Time) error {
+	preference, err := s.preference(ctx, reminder.UserID)
+	if err != nil {
+		return err
+	}
+
+	subject, body, err := s.templates.Render(preference.Language, reminder)
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+
+	for _, channel := range preference.Channels {
+		sender, ok := s.senders[channel]
+		if !ok {
+			sendErr = fmt.Errorf("%w:%s", models.ErrUnknownChannel, channel)
+			continue
+		}
+
+		key := reminder.Key(channel)
+
+		if err = s.storage.ReserveReminder(ctx, key, now); err != nil {
+			if errors.Is(err, models.ErrAlreadySent) {
+				continue
+			}
+			sendErr = err
+			continue
+		}
+
+		msg := models.Message{
+			Channel: channel,
+			To:      recipient(preference, channel),
+			UserID:  reminder.UserID,
+			Subject: subject,
+			Body:    body,
+		}
+
+		if err = sender.Send(ctx, msg); err != nil {
+			sendErr = fmt.Errorf("send through %s failed:%s", channel, err)
+			if err = s.storage.ReleaseReminder(ctx, key); err != nil {
+				s.logger.Errorf("failed to release reminder %s:%s", key, err)
+			}
+			continue
+		}
+
+		s.logger.Infof("%s reminder %s sent", reminder.Kind, key)
+	}
+
+	return sendErr
+}
+
+// preference returns the user preferences or the default ones
+func (s *Service) preference(ctx context.Context, userID int64) (models.Preference, error) {
+	preference, err := s.storage.GetPreference(ctx, userID)
+	if errors.Is(err, models.ErrPreferenceNotFound) {
+		return models.Preference{
+			UserID:   userID,
+			Language: s.cfg.DefaultLanguage,
+			Channels: s.cfg.DefaultChannels,
+		}, nil
+	}
+
+	return preference, err
+}
+
+func recipient(preference models.Preference, channel string) string {
+	var to string
+
+	switch channel {
+	case models.ChannelEmail:
+		to = preference.Email
+	case models.ChannelSMS:
+		to = preference.Phone
+	case models.ChannelPush:
+		to = preference.DeviceToken
+	}
+
+	if to == "" {
+		to = strconv.FormatInt(preference.UserID, 10) //провайдер найдет контакт по userID
+	}
+
+	return to
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"bank/notification_service/internal/config"
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type Service struct {
+	logger    *logrus.Logger
+	storage   Storage
+	senders   map[string]Sender //ключ-канал
+	templates Renderer
+	cfg       config.Reminders
+}
+
+func NewService(logger *logrus.Logger, storage Storage, senders map[string]Sender, templates Renderer, cfg config.Reminders) *Service {
+	return &Service{
+		logger:    logger,
+		storage:   storage,
+		senders:   senders,
+		templates: templates,
+		cfg:       cfg,
+	}
+}
+
+type Storage interface {
+	Credit
+	Preference
+	Sent
+}
+
+type Credit interface {
+	SaveCredit(ctx context.Context, eventID string, credit models.Credit, occurredAt time.Time) error
+	SetCreditStatus(ctx context.Context, eventID, creditID, status string, occurredAt time.Time) error
+	AddPayment(ctx context.Context, eventID, creditID string, amount int) error
+	GetActiveCredits(ctx context.Context) ([]models.Credit, error)
+}
+
+type Preference interface {
+	SavePreference(ctx context.Context, preference models.Preference) error
+	GetPreference(ctx context.Context, userID int64) (models.Preference, error)
+}
+
+type Sent interface {
+	ReserveReminder(ctx context.Context, key string, sentAt time.Time) error
+	ReleaseReminder(ctx context.Context, key string) error
+}
+
+// Sender delivers a message through one channel
+type Sender interface {
+	Send(ctx context.Context, msg models.Message) error
+}
+
+// Renderer turns a reminder into a message in the user language
+type Renderer interface {
+	Render(language string, reminder models.Reminder) (subject, body string, err error)
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// CreditMongoDB keeps credits built from credit events.
+// Every update is applied only if the event is not in the credit events yet,so redelivered events change nothing.
+// Every event upserts the credit,an event that came before CreditCreated isn't lost
+type CreditMongoDB struct {
+	creditCollection *mongo.Collection
+}
+
+func NewCreditMongoDB(DB *mongo.Database, creditCollection string) *CreditMongoDB {
+	return &CreditMongoDB{creditCollection: DB.Collection(creditCollection)}
+}
+
+// SaveCredit creates or updates the credit terms from the event.
+// Topics keep no order between each other,so the paid amount is changed only by payments
+// and the status only by events newer than the one that set it
+func (d *CreditMongoDB) SaveCredit(ctx context.Context, eventID string, credit models.Credit, occurredAt time.Time) error {
+	if err := d.setStatus(ctx, credit.ID, credit.Status, occurredAt); err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"userID":         credit.UserID,
+			"currency":       credit.Currency,
+			"monthlyPayment": credit.MonthlyPayment,
+			"term":           credit.Term,
+			"maturesAt":      credit.MaturesAt,
+			"schedule":       credit.Schedule,
+		},
+	}
+
+	return d.apply(ctx, eventID, credit.ID, update)
+}
+
+func (d *CreditMongoDB) SetCreditStatus(ctx context.Context, eventID, creditID, status string, occurredAt time.Time) error {
+	if err := d.setStatus(ctx, creditID, status, occurredAt); err != nil {
+		return err
+	}
+
+	return d.apply(ctx, eventID, creditID, bson.M{})
+}
+
+// AddPayment creates the credit if the payment came before CreditCreated,the terms are filled in later
+func (d *CreditMongoDB) AddPayment(ctx context.Context, eventID, creditID string, amount int) error {
+	return d.apply(ctx, eventID, creditID, bson.M{"$inc": bson.M{"paidAmount": amount}})
+}
+
+func (d *CreditMongoDB) GetActiveCredits(ctx context.Context) ([]models.Credit, error) {
+	query := bson.M{
+		"status": models.CreditStatusActive,
+		"userID": bson.M{"$exists": true}, //условия приходят с CreditCreated,до него напоминать не о чем
+	}
+
+	res, err := d.creditCollection.Find(ctx, query, options.Find().SetProjection(bson.M{"events": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("find failed:%s", err)
+	}
+
+	defer res.Close(ctx)
+
+	var credits []models.Credit
+
+	if err = res.All(ctx, &credits); err != nil {
+		return nil, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return credits, nil
+}
+
+func (d *CreditMongoDB) apply(ctx context.Context, eventID, creditID string, update bson.M) error {
+	query := bson.M{"_id": creditID, "events": bson.M{"$ne": eventID}}
+	update["$push"] = bson.M{"events": eventID}
+
+	_, err := d.creditCollection.UpdateOne(ctx, query, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) { //кредит уже есть и событие к нему применено
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply event %s:%s", eventID, err)
+	}
+
+	return nil
+}
+
+// setStatus changes the status only if the event is newer than the one that set the current status.
+// Closed and cancelled credits keep their status.
+// It is safe to repeat,so it runs before the event is marked as applied
+func (d *CreditMongoDB) setStatus(ctx context.Context, creditID, status string, occurredAt time.Time) error {
+	if status == "" {
+		return nil
+	}
+
+	query := bson.M{
+		"_id":    creditID,
+		"status": bson.M{"$nin": bson.A{models.CreditStatusClosed, models.CreditStatusCancelled}},
+		"$or": bson.A{
+			bson.M{"statusAt": bson.M{"$lt": occurredAt}},
+			bson.M{"statusAt": bson.M{"$exists": false}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"status": status, "statusAt": occurredAt}}
+
+	_, err := d.creditCollection.UpdateOne(ctx, query, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) { //кредит есть,но его статус новее или окончательный
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set status of %s:%s", creditID, err)
+	}
+
+	return nil
+}
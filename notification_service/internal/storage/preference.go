@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PreferenceMongoDB struct {
+	preferenceCollection *mongo.Collection
+}
+
+func NewPreferenceMongoDB(DB *mongo.Database, preferenceCollection string) *PreferenceMongoDB {
+	return &PreferenceMongoDB{preferenceCollection: DB.Collection(preferenceCollection)}
+}
+
+func (d *PreferenceMongoDB) SavePreference(ctx context.Context, preference models.Preference) error {
+	query := bson.M{"_id": preference.UserID}
+
+	if _, err := d.preferenceCollection.ReplaceOne(ctx, query, preference, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save preference:%s", err)
+	}
+
+	return nil
+}
+
+func (d *PreferenceMongoDB) GetPreference(ctx context.Context, userID int64) (preference models.Preference, err error) {
+	res := d.preferenceCollection.FindOne(ctx, bson.M{"_id": userID})
+
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return preference, fmt.Errorf("%w:%v", models.ErrPreferenceNotFound, userID)
+	}
+	if res.Err() != nil {
+		return preference, fmt.Errorf("failed to find preference:%s", res.Err())
+	}
+
+	if err = res.Decode(&preference); err != nil {
+		return preference, fmt.Errorf("decode failed:%s", err)
+	}
+
+	return preference, nil
+}
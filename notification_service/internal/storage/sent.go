@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// SentMongoDB remembers sent reminders by their key,the key is the _id so a reminder can be reserved only once
+type SentMongoDB struct {
+	sentCollection *mongo.Collection
+}
+
+func NewSentMongoDB(DB *mongo.Database, sentCollection string) *SentMongoDB {
+	return &SentMongoDB{sentCollection: DB.Collection(sentCollection)}
+}
+
+// ReserveReminder marks the reminder as sent before sending,models.ErrAlreadySent means someone sent it already
+func (d *SentMongoDB) ReserveReminder(ctx context.Context, key string, sentAt time.Time) error {
+	_, err := d.sentCollection.InsertOne(ctx, bson.M{"_id": key, "sentAt": sentAt})
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w:%s", models.ErrAlreadySent, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve reminder:%s", err)
+	}
+
+	return nil
+}
+
+// ReleaseReminder removes the reservation when sending failed,so the reminder is retried on the next run
+func (d *SentMongoDB) ReleaseReminder(ctx context.Context, key string) error {
+	if _, err := d.sentCollection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("failed to release reminder:%s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"bank/notification_service/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoDB struct {
+	*CreditMongoDB
+	*PreferenceMongoDB
+	*SentMongoDB
+}
+
+func NewStorage(DB *mongo.Database, cfg config.MongoDb) *MongoDB {
+	return &MongoDB{
+		CreditMongoDB:     NewCreditMongoDB(DB, cfg.CreditCollection),
+		PreferenceMongoDB: NewPreferenceMongoDB(DB, cfg.PreferenceCollection),
+		SentMongoDB:       NewSentMongoDB(DB, cfg.SentCollection),
+	}
+}
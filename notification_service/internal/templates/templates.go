@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"bank/notification_service/internal/domain/models"
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+)
+
+//go:embed ru/*.tmpl en/*.tmpl
+var files embed.FS
+
+// dateLayouts is how due dates are written in each language
+var dateLayouts = map[string]string{
+	models.LanguageRU: "02.01.2006",
+	models.LanguageEN: "January 2, 2006",
+}
+
+// Templates renders reminders in the user language,each template defines a subject and a body
+type Templates struct {
+	templates map[string]*template.Template //ключ-язык/вид напоминания
+}
+
+func New() (*Templates, error) {
+	t := &Templates{templates: make(map[string]*template.Template)}
+
+	for language := range dateLayouts {
+		for _, kind := range []string{models.ReminderUpcoming, models.ReminderOverdue} {
+			tmpl, err := template.ParseFS(files, fmt.Sprintf("%s/%s.tmpl", language, kind))
+			if err != nil {
+				return nil, fmt.Errorf("parse template %s/%s failed:%s", language, kind, err)
+			}
+			t.templates[language+"/"+kind] = tmpl
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Templates) Render(language string, reminder models.Reminder) (subject, body string, err error) {
+	tmpl, ok := t.templates[language+"/"+reminder.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("%w:%s/%s", models.ErrUnknownTemplate, language, reminder.Kind)
+	}
+
+	data := struct {
+		CreditID    string
+		Installment int
+		DueDate     string
+		Amount      int
+		Currency    string
+	}{
+		CreditID:    reminder.CreditID,
+		Installment: reminder.Installment,
+		DueDate:     reminder.DueDate.Format(dateLayouts[language]),
+		Amount:      reminder.Amount,
+		Currency:    reminder.Currency,
+	}
+
+	if subject, err = execute(tmpl, "subject", data); err != nil {
+		return "", "", err
+	}
+
+	if body, err = execute(tmpl, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return subject, body, nil
+}
+
+func execute(tmpl *template.Template, name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("execute template %s failed:%s", name, err)
+	}
+	return buf.String(), nil
+}
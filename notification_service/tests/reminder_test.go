@@ -0,0 +1,427 @@
+package tests
+
+import (
+	"bank/notification_service/internal/channel"
+	"bank/notification_service/internal/config"
+	"bank/notification_service/internal/domain/models"
+	"bank/notification_service/internal/service"
+	"bank/notification_service/internal/templates"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDueReminder(t *testing.T) {
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	credit := models.Credit{
+		ID:             "credit",
+		UserID:         1,
+		Currency:       "RUB",
+		MonthlyPayment: 1000,
+		Term:           12,
+		MaturesAt:      time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), //платежи 12-го числа,первый 12.02.2024
+		Status:         models.CreditStatusActive,
+	}
+
+	tests := []struct {
+		name        string
+		paidAmount  int
+		ok          bool
+		kind        string
+		installment int
+	}{
+		{name: "first installment overdue", paidAmount: 0, ok: true, kind: models.ReminderOverdue, installment: 1},
+		{name: "second installment upcoming", paidAmount: 1000, ok: true, kind: models.ReminderUpcoming, installment: 2},
+		{name: "third installment not due yet", paidAmount: 2000, ok: false},
+		{name: "fully paid", paidAmount: 12000, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credit.PaidAmount = tt.paidAmount
+
+			reminder, ok := service.DueReminder(credit, now, 72*time.Hour)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+
+			require.Equal(t, tt.kind, reminder.Kind)
+			require.Equal(t, tt.installment, reminder.Installment)
+			require.Equal(t, 12, reminder.DueDate.Day())
+		})
+	}
+}
+
+func TestDueReminder_Schedule(t *testing.T) {
+	now := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	date := func(month time.Month) time.Time {
+		return time.Date(2024, month, 12, 0, 0, 0, 0, time.UTC)
+	}
+
+	//дифференцированные платежи с каникулами в марте,аннуитетом их не посчитать
+	credit := models.Credit{
+		ID:             "credit",
+		UserID:         1,
+		Currency:       "RUB",
+		MonthlyPayment: 1500,
+		Term:           4,
+		MaturesAt:      date(time.May),
+		Schedule: []models.ScheduledPayment{
+			{Number: 1, DueDate: date(time.February), Amount: 1500},
+			{Number: 2, DueDate: date(time.March), Amount: 0},
+			{Number: 3, DueDate: date(time.April), Amount: 1300},
+			{Number: 4, DueDate: date(time.May), Amount: 1200},
+		},
+		Status: models.CreditStatusActive,
+	}
+
+	tests := []struct {
+		name        string
+		paidAmount  int
+		ok          bool
+		kind        string
+		installment int
+		amount      int
+	}{
+		{name: "first installment overdue", paidAmount: 0, ok: true, kind: models.ReminderOverdue, installment: 1, amount: 1500},
+		{name: "holiday is skipped", paidAmount: 1500, ok: true, kind: models.ReminderUpcoming, installment: 3, amount: 1300},
+		{name: "rest of partly paid installment", paidAmount: 2000, ok: true, kind: models.ReminderUpcoming, installment: 3, amount: 800},
+		{name: "last installment not due yet", paidAmount: 2800, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credit.PaidAmount = tt.paidAmount
+
+			reminder, ok := service.DueReminder(credit, now, 72*time.Hour)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+
+			require.Equal(t, tt.kind, reminder.Kind)
+			require.Equal(t, tt.installment, reminder.Installment)
+			require.Equal(t, tt.amount, reminder.Amount)
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	tmpl, err := templates.New()
+	require.NoError(t, err)
+
+	reminder := models.Reminder{
+		Kind:        models.ReminderUpcoming,
+		CreditID:    "credit",
+		Installment: 2,
+		DueDate:     time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC),
+		Amount:      1000,
+		Currency:    "RUB",
+	}
+
+	subject, body, err := tmpl.Render(models.LanguageRU, reminder)
+	require.NoError(t, err)
+	require.Equal(t, "Напоминание о платеже по кредиту", subject)
+	require.Contains(t, body, "12.03.2024")
+
+	subject, body, err = tmpl.Render(models.LanguageEN, reminder)
+	require.NoError(t, err)
+	require.Equal(t, "Upcoming credit payment", subject)
+	require.Contains(t, body, "March 12, 2024")
+
+	_, _, err = tmpl.Render("de", reminder)
+	require.ErrorIs(t, err, models.ErrUnknownTemplate)
+}
+
+func TestSendReminders_Dedup(t *testing.T) {
+	dir := t.TempDir()
+
+	senders, err := channel.NewSenders(config.Channels{
+		Email: config.Channel{Type: channel.TypeFile, Path: filepath.Join(dir, "email.log")},
+		SMS:   config.Channel{Type: channel.TypeFile, Path: filepath.Join(dir, "sms.log")},
+		Push:  config.Channel{Type: channel.TypeLog},
+	}, logrus.New())
+	require.NoError(t, err)
+
+	tmpl, err := templates.New()
+	require.NoError(t, err)
+
+	storage := newMemoryStorage()
+	services := service.NewService(logrus.New(), storage, senders, tmpl, config.Reminders{
+		RemindBefore:    72 * time.Hour,
+		DefaultLanguage: models.LanguageRU,
+		DefaultChannels: []string{models.ChannelEmail},
+	})
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	created := models.CreditEvent{
+		ID:       "event-1",
+		Type:     models.EventCreditCreated,
+		Version:  models.EventSchemaVersion,
+		CreditID: "credit",
+		UserID:   1,
+		Data: models.CreditEventData{
+			Currency:       "RUB",
+			Term:           12,
+			MonthlyPayment: 1000,
+			MaturesAt:      time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+			Status:         models.CreditStatusActive,
+		},
+	}
+	payment := models.CreditEvent{
+		ID:       "event-2",
+		Type:     models.EventPaymentReceived,
+		Version:  models.EventSchemaVersion,
+		CreditID: "credit",
+		Data:     models.CreditEventData{PaymentAmount: 1000},
+	}
+
+	for _, event := range []models.CreditEvent{created, payment, payment} { //платеж доставлен дважды
+		require.NoError(t, services.HandleEvent(ctx, event))
+	}
+
+	require.NoError(t, services.SavePreference(ctx, models.Preference{
+		UserID:   1,
+		Language: models.LanguageEN,
+		Channels: []string{models.ChannelEmail, models.ChannelSMS},
+		Email:    "user@example.com",
+	}))
+
+	require.NoError(t, services.SendReminders(ctx, now))
+	require.NoError(t, services.SendReminders(ctx, now)) //повторный запуск ничего не отправляет
+
+	emails := readMessages(t, filepath.Join(dir, "email.log"))
+	require.Len(t, emails, 1)
+	require.Equal(t, "user@example.com", emails[0].To)
+	require.Equal(t, "Upcoming credit payment", emails[0].Subject)
+	require.Contains(t, emails[0].Body, "Payment 2")
+
+	sms := readMessages(t, filepath.Join(dir, "sms.log"))
+	require.Len(t, sms, 1)
+	require.Equal(t, "1", sms[0].To)
+
+	unsupported := created
+	unsupported.Version = models.EventSchemaVersion + 1
+	require.ErrorIs(t, services.HandleEvent(ctx, unsupported), models.ErrUnsupportedEvent)
+}
+
+func TestHandleEvent_OutOfOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	senders, err := channel.NewSenders(config.Channels{
+		Email: config.Channel{Type: channel.TypeLog},
+		SMS:   config.Channel{Type: channel.TypeFile, Path: filepath.Join(dir, "sms.log")},
+		Push:  config.Channel{Type: channel.TypeLog},
+	}, logrus.New())
+	require.NoError(t, err)
+
+	tmpl, err := templates.New()
+	require.NoError(t, err)
+
+	storage := newMemoryStorage()
+	services := service.NewService(logrus.New(), storage, senders, tmpl, config.Reminders{
+		RemindBefore:    72 * time.Hour,
+		DefaultLanguage: models.LanguageEN,
+		DefaultChannels: []string{models.ChannelSMS},
+	})
+
+	ctx := context.Background()
+	issuedAt := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
+
+	event := func(id, kind, creditID string, userID int64, occurredAt time.Time, data models.CreditEventData) models.CreditEvent {
+		return models.CreditEvent{ID: id, Type: kind, Version: models.EventSchemaVersion, CreditID: creditID, UserID: userID, OccurredAt: occurredAt, Data: data}
+	}
+	terms := func(status string) models.CreditEventData {
+		return models.CreditEventData{
+			Currency:       "RUB",
+			Term:           12,
+			MonthlyPayment: 1000,
+			MaturesAt:      time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+			Status:         status,
+		}
+	}
+	later := issuedAt.Add(time.Hour)
+
+	//события из разных топиков приходят раньше CreditCreated
+	for _, e := range []models.CreditEvent{
+		event("paid-payment", models.EventPaymentReceived, "paid", 1, later, models.CreditEventData{PaymentAmount: 1000}),
+		event("paid-created", models.EventCreditCreated, "paid", 1, issuedAt, terms(models.CreditStatusActive)),
+		event("closed-closed", models.EventCreditClosed, "closed", 2, later, models.CreditEventData{Status: models.CreditStatusClosed}),
+		event("closed-created", models.EventCreditCreated, "closed", 2, issuedAt, terms(models.CreditStatusActive)),
+		event("closed-updated", models.EventCreditUpdated, "closed", 2, later.Add(time.Hour), terms(models.CreditStatusActive)),
+		event("approved-approved", models.EventCreditStatusChanged, "approved", 3, later, models.CreditEventData{Status: models.CreditStatusActive}),
+		event("approved-created", models.EventCreditCreated, "approved", 3, issuedAt, terms("pending_approval")),
+	} {
+		require.NoError(t, services.HandleEvent(ctx, e))
+	}
+
+	credits, err := storage.GetActiveCredits(ctx)
+	require.NoError(t, err)
+
+	active := make(map[string]models.Credit, len(credits))
+	for _, credit := range credits {
+		active[credit.ID] = credit
+	}
+	require.Len(t, active, 2)
+	require.Equal(t, 1000, active["paid"].PaidAmount)
+	require.Contains(t, active, "approved")
+
+	require.NoError(t, services.SendReminders(ctx, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)))
+
+	bodies := make(map[string]string)
+	for _, msg := range readMessages(t, filepath.Join(dir, "sms.log")) {
+		bodies[msg.To] = msg.Body
+	}
+	require.Len(t, bodies, 2) //закрытому кредиту ничего не приходит
+	require.Contains(t, bodies["1"], "Payment 2")
+	require.Contains(t, bodies["3"], "Payment 1")
+}
+
+func readMessages(t *testing.T, path string) []models.Message {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var messages []models.Message
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var msg models.Message
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		messages = append(messages, msg)
+	}
+	require.NoError(t, scanner.Err())
+
+	return messages
+}
+
+// memoryStorage is service.Storage in memory with the same dedup rules as the mongo one
+type memoryStorage struct {
+	mu          sync.Mutex
+	credits     map[string]*models.Credit
+	events      map[string]struct{}
+	preferences map[int64]models.Preference
+	sent        map[string]time.Time
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		credits:     make(map[string]*models.Credit),
+		events:      make(map[string]struct{}),
+		preferences: make(map[int64]models.Preference),
+		sent:        make(map[string]time.Time),
+	}
+}
+
+func (m *memoryStorage) apply(eventID, creditID string, fn func(credit *models.Credit)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[eventID]; ok {
+		return
+	}
+
+	credit, ok := m.credits[creditID]
+	if !ok {
+		credit = &models.Credit{ID: creditID}
+		m.credits[creditID] = credit
+	}
+
+	fn(credit)
+	m.events[eventID] = struct{}{}
+}
+
+// setStatus keeps the status of the newest event,closed and cancelled credits don't change
+func setStatus(credit *models.Credit, status string, occurredAt time.Time) {
+	if status == "" || credit.Status == models.CreditStatusClosed || credit.Status == models.CreditStatusCancelled {
+		return
+	}
+	if !credit.StatusAt.IsZero() && !credit.StatusAt.Before(occurredAt) {
+		return
+	}
+	credit.Status, credit.StatusAt = status, occurredAt
+}
+
+func (m *memoryStorage) SaveCredit(_ context.Context, eventID string, credit models.Credit, occurredAt time.Time) error {
+	m.apply(eventID, credit.ID, func(c *models.Credit) {
+		c.UserID = credit.UserID
+		c.Currency = credit.Currency
+		c.MonthlyPayment = credit.MonthlyPayment
+		c.Term = credit.Term
+		c.MaturesAt = credit.MaturesAt
+		c.Schedule = credit.Schedule
+		setStatus(c, credit.Status, occurredAt)
+	})
+	return nil
+}
+
+func (m *memoryStorage) SetCreditStatus(_ context.Context, eventID, creditID, status string, occurredAt time.Time) error {
+	m.apply(eventID, creditID, func(c *models.Credit) { setStatus(c, status, occurredAt) })
+	return nil
+}
+
+func (m *memoryStorage) AddPayment(_ context.Context, eventID, creditID string, amount int) error {
+	m.apply(eventID, creditID, func(c *models.Credit) { c.PaidAmount += amount })
+	return nil
+}
+
+func (m *memoryStorage) GetActiveCredits(_ context.Context) ([]models.Credit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var credits []models.Credit
+	for _, credit := range m.credits {
+		if credit.Status == models.CreditStatusActive && credit.UserID != 0 {
+			credits = append(credits, *credit)
+		}
+	}
+	return credits, nil
+}
+
+func (m *memoryStorage) SavePreference(_ context.Context, preference models.Preference) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.preferences[preference.UserID] = preference
+	return nil
+}
+
+func (m *memoryStorage) GetPreference(_ context.Context, userID int64) (models.Preference, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	preference, ok := m.preferences[userID]
+	if !ok {
+		return models.Preference{}, fmt.Errorf("%w:%v", models.ErrPreferenceNotFound, userID)
+	}
+	return preference, nil
+}
+
+func (m *memoryStorage) ReserveReminder(_ context.Context, key string, sentAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sent[key]; ok {
+		return fmt.Errorf("%w:%s", models.ErrAlreadySent, key)
+	}
+	m.sent[key] = sentAt
+	return nil
+}
+
+func (m *memoryStorage) ReleaseReminder(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sent, key)
+	return nil
+}